@@ -0,0 +1,135 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"time"
+
+	"jantungin-api-server/internal/data/migrations"
+	"jantungin-api-server/internal/data/repository"
+	"jantungin-api-server/pkg/database"
+	"jantungin-api-server/pkg/fieldcrypt"
+	"jantungin-api-server/pkg/utils"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// stage menentukan sejauh mana App disiapkan sebelum subcommand berjalan
+type stage int
+
+const (
+	stageConfig   stage = iota // config dan logger
+	stageDatabase              // + koneksi database dan migrator
+	stageSchema                // + migrasi otomatis atau pengecekan skema
+	stageKeyring               // + data key enkripsi kolom sensitif
+)
+
+// App berisi dependensi bersama semua subcommand
+type App struct {
+	Config   *utils.Config
+	DB       *gorm.DB
+	Migrator *database.Migrator
+	Envelope *fieldcrypt.Envelope
+
+	manager *database.Manager
+}
+
+// openApp memuat config, logger, database, skema dan keyring sampai tahap target.
+// Log console ditulis ke console agar stdout perintah CLI tetap bisa diparse.
+func openApp(ctx context.Context, target stage, console io.Writer) (*App, error) {
+	cfg, err := utils.LoadConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load config: %w", err)
+	}
+	if err := utils.InitLoggerTo(cfg.App.Env, console); err != nil {
+		return nil, fmt.Errorf("failed to initialize logger: %w", err)
+	}
+
+	app := &App{Config: cfg}
+	if target < stageDatabase {
+		return app, nil
+	}
+
+	// Logger bawaan GORM menulis ke os.Stdout; arahkan ke console yang sama
+	logger.Default = logger.New(log.New(console, "\r\n", log.LstdFlags), logger.Config{
+		SlowThreshold: 200 * time.Millisecond,
+		LogLevel:      logger.Warn,
+		Colorful:      console == os.Stdout,
+	})
+
+	app.manager, err = database.NewManager(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize databases: %w", err)
+	}
+	app.DB = app.manager.Postgres.GetDB()
+
+	sqlDB, err := app.DB.DB()
+	if err != nil {
+		app.Close()
+		return nil, fmt.Errorf("failed to get database instance: %w", err)
+	}
+	app.Migrator, err = database.NewMigrator(sqlDB, migrations.FS)
+	if err != nil {
+		app.Close()
+		return nil, fmt.Errorf("failed to load migrations: %w", err)
+	}
+	if target < stageSchema {
+		return app, nil
+	}
+
+	if err := app.prepareSchema(ctx); err != nil {
+		app.Close()
+		return nil, err
+	}
+
+	// Data key enkripsi kolom sensitif harus terpasang sebelum data dibaca/ditulis
+	app.Envelope, err = fieldcrypt.NewEnvelope(repository.NewEncryptionKeyRepository(app.DB), cfg.Encryption)
+	if err != nil {
+		app.Close()
+		return nil, fmt.Errorf("invalid encryption configuration: %w", err)
+	}
+	if target < stageKeyring {
+		return app, nil
+	}
+
+	keyring, err := app.Envelope.Open(ctx)
+	if err != nil {
+		app.Close()
+		return nil, fmt.Errorf("failed to load encryption keys: %w", err)
+	}
+	fieldcrypt.SetDefault(keyring)
+	utils.Info("Encryption keys loaded", zap.String("active_key_id", keyring.ActiveKeyID()))
+
+	return app, nil
+}
+
+// prepareSchema menjalankan migrasi tertunda jika DB_AUTO_MIGRATE aktif, atau menolak
+// skema yang belum terbaru. Beberapa instance boleh start bersamaan; advisory lock
+// memastikan migrasi jalan sekali.
+func (a *App) prepareSchema(ctx context.Context) error {
+	if !a.Config.Database.AutoMigrate {
+		if err := a.Migrator.Check(ctx); err != nil {
+			return fmt.Errorf("database schema check failed: %w", err)
+		}
+		return nil
+	}
+
+	applied, err := a.Migrator.Up(ctx)
+	if err != nil {
+		return fmt.Errorf("database migration failed: %w", err)
+	}
+	utils.Info("Database schema up to date", zap.Int("applied", applied))
+	return nil
+}
+
+func (a *App) Close() {
+	if a.manager != nil {
+		a.manager.Close()
+	}
+	utils.Sync()
+}
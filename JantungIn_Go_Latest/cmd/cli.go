@@ -0,0 +1,213 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// Exit code CLI
+const (
+	ExitOK      = 0
+	ExitFailure = 1 // perintah gagal dijalankan
+	ExitUsage   = 2 // argumen tidak valid
+)
+
+const usage = `usage: jantungin [-output text|json] <command> [args]
+
+commands:
+  serve                                    jalankan HTTP server (default)
+  migrate up|down [steps]|status|force <v> kelola skema database
+  seed [-scenario file] [-only kinds] [-count N] [-seed N] [-deterministic]
+                                           sisipkan data dummy dari scenario (idempoten)
+  seed generate|export -file out.json      tulis fixture JSON dari scenario / isi database
+                                           (export ditolak di production tanpa -allow-production)
+  seed import -file in.json [-only kinds]  sisipkan fixture JSON
+  user create-admin -username U [-name N] [-email E] [-password-stdin]
+  user reset-password -username U [-password-stdin]
+  config check                             validasi konfigurasi tanpa koneksi database
+  encryption rotate-key|reencrypt          kelola data key enkripsi kolom sensitif
+  migrate-legacy [-report path] [-restart] salin data dari database API Node lama
+`
+
+// usageError ditampilkan bersama usage dan keluar dengan ExitUsage
+type usageError struct{ msg string }
+
+func (e *usageError) Error() string { return e.msg }
+
+func usagef(format string, args ...any) error {
+	return &usageError{msg: fmt.Sprintf(format, args...)}
+}
+
+// textWriter diimplementasikan hasil perintah yang punya tampilan teks khusus;
+// hasil lain ditampilkan sebagai JSON pada mode text juga
+type textWriter interface {
+	WriteText(w io.Writer)
+}
+
+// cli menyimpan opsi global yang dipakai semua subcommand
+type cli struct {
+	output string
+	stdin  io.Reader
+	stdout io.Writer
+	stderr io.Writer
+}
+
+type command struct {
+	name string
+	run  func(ctx context.Context, c *cli, args []string) (any, error)
+}
+
+var commands = []command{
+	{"serve", runServe},
+	{"migrate", runMigrateCommand},
+	{"seed", runSeed},
+	{"user", runUser},
+	{"config", runConfig},
+	{"encryption", runEncryption},
+	{"migrate-legacy", runMigrateLegacy},
+}
+
+// Execute menjalankan CLI dengan argumen tanpa nama program dan mengembalikan exit code.
+// Tanpa subcommand, server dijalankan agar image Docker lama tetap bekerja.
+func Execute(args []string) int {
+	c := &cli{stdin: os.Stdin, stdout: os.Stdout, stderr: os.Stderr}
+
+	global := flag.NewFlagSet("jantungin", flag.ContinueOnError)
+	global.SetOutput(c.stderr)
+	global.Usage = func() { fmt.Fprint(c.stderr, usage) }
+	global.StringVar(&c.output, "output", "text", "format output: text atau json")
+
+	if err := global.Parse(translateLegacyFlags(args)); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return ExitOK
+		}
+		return ExitUsage
+	}
+	if c.output != "text" && c.output != "json" {
+		fmt.Fprintf(c.stderr, "invalid -output %q, use text or json\n", c.output)
+		return ExitUsage
+	}
+
+	rest := global.Args()
+	name := "serve"
+	if len(rest) > 0 {
+		name, rest = rest[0], rest[1:]
+	}
+	if name == "help" {
+		fmt.Fprint(c.stdout, usage)
+		return ExitOK
+	}
+
+	for _, cmd := range commands {
+		if cmd.name != name {
+			continue
+		}
+		result, err := cmd.run(context.Background(), c, rest)
+		return c.finish(name, result, err)
+	}
+	return c.finish(name, nil, usagef("unknown command %q", name))
+}
+
+// finish menulis hasil atau error ke stdout sesuai format output lalu memilih exit code
+func (c *cli) finish(name string, result any, err error) int {
+	code := ExitOK
+	var usageErr *usageError
+	switch {
+	case errors.Is(err, flag.ErrHelp):
+		return ExitOK
+	case errors.As(err, &usageErr):
+		code = ExitUsage
+	case err != nil:
+		code = ExitFailure
+	}
+
+	if c.output == "json" {
+		out := map[string]any{"command": name, "ok": err == nil}
+		if err != nil {
+			out["error"] = err.Error()
+		} else if result != nil {
+			out["result"] = result
+		}
+		c.writeJSON(out)
+		return code
+	}
+
+	if err != nil {
+		fmt.Fprintf(c.stderr, "error: %v\n", err)
+		if code == ExitUsage {
+			fmt.Fprint(c.stderr, usage)
+		}
+		return code
+	}
+	if tw, ok := result.(textWriter); ok {
+		tw.WriteText(c.stdout)
+	} else if result != nil {
+		c.writeJSON(result)
+	}
+	return code
+}
+
+func (c *cli) writeJSON(v any) {
+	enc := json.NewEncoder(c.stdout)
+	enc.SetIndent("", "  ")
+	_ = enc.Encode(v)
+}
+
+// flagSet membuat FlagSet subcommand yang mengembalikan error alih-alih exit
+func (c *cli) flagSet(name string) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(c.stderr)
+	return fs
+}
+
+// parseFlags mengubah error parsing flag menjadi usageError
+func parseFlags(fs *flag.FlagSet, args []string) error {
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return err
+		}
+		return usagef("%s: %v", fs.Name(), err)
+	}
+	if fs.NArg() > 0 {
+		return usagef("%s: unexpected argument %q", fs.Name(), fs.Arg(0))
+	}
+	return nil
+}
+
+// translateLegacyFlags memetakan flag lama (-seed, -rotate-key, dst.) ke subcommand
+// agar skrip deploy yang sudah ada tetap berjalan. Hanya flag sebelum argumen
+// non-flag pertama yang diperiksa, sehingga flag subcommand (mis. seed -seed) tidak ikut.
+func translateLegacyFlags(args []string) []string {
+	legacy := map[string][]string{
+		"seed":           {"seed"},
+		"reencrypt":      {"encryption", "reencrypt"},
+		"rotate-key":     {"encryption", "rotate-key"},
+		"migrate-legacy": {"migrate-legacy"},
+	}
+
+	var translated, rest []string
+	for i, arg := range args {
+		name := strings.TrimLeft(arg, "-")
+		if !strings.HasPrefix(arg, "-") {
+			rest = append(rest, args[i:]...)
+			break
+		}
+		if sub, ok := legacy[name]; ok && translated == nil {
+			fmt.Fprintf(os.Stderr, "warning: flag %s is deprecated, use `%s`\n", arg, strings.Join(sub, " "))
+			translated = sub
+			continue
+		}
+		// -legacy-report=path dan -legacy-restart menjadi flag migrate-legacy
+		rest = append(rest, strings.Replace(arg, "-legacy-", "-", 1))
+	}
+	if translated == nil {
+		return args
+	}
+	return append(translated, rest...)
+}
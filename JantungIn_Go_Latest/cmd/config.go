@@ -0,0 +1,120 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+
+	"jantungin-api-server/pkg/utils"
+)
+
+const configUsage = "usage: config check [-connect]"
+
+// configCheckResult ringkasan konfigurasi tanpa nilai rahasia
+type configCheckResult struct {
+	Env      string            `json:"env"`
+	Port     string            `json:"port"`
+	Database string            `json:"database"`
+	Settings map[string]string `json:"settings"`
+	Warnings []string          `json:"warnings"`
+	Schema   *schemaCheck      `json:"schema,omitempty"`
+}
+
+type schemaCheck struct {
+	Version int64 `json:"version"`
+	Dirty   bool  `json:"dirty"`
+	Pending int   `json:"pending"`
+}
+
+func (r configCheckResult) WriteText(w io.Writer) {
+	fmt.Fprintf(w, "config ok (env: %s, port: %s)\n", r.Env, r.Port)
+	fmt.Fprintf(w, "database: %s\n", r.Database)
+	if r.Schema != nil {
+		fmt.Fprintf(w, "schema: version %d (dirty: %t), %d pending\n", r.Schema.Version, r.Schema.Dirty, r.Schema.Pending)
+	}
+	for _, warning := range r.Warnings {
+		fmt.Fprintf(w, "warning: %s\n", warning)
+	}
+}
+
+func runConfig(ctx context.Context, c *cli, args []string) (any, error) {
+	if len(args) == 0 || args[0] != "check" {
+		return nil, usagef(configUsage)
+	}
+
+	fs := c.flagSet("config check")
+	connect := fs.Bool("connect", false, "cek juga koneksi database dan versi skema")
+	if err := parseFlags(fs, args[1:]); err != nil {
+		return nil, err
+	}
+
+	target := stageConfig
+	if *connect {
+		target = stageDatabase
+	}
+	// LoadConfig menjalankan Validate; konfigurasi tidak valid menjadi error perintah
+	app, err := openApp(ctx, target, os.Stderr)
+	if err != nil {
+		return nil, err
+	}
+	defer app.Close()
+
+	cfg := app.Config
+	result := configCheckResult{
+		Env:      cfg.App.Env,
+		Port:     cfg.App.Port,
+		Database: fmt.Sprintf("%s@%s:%s/%s (sslmode=%s)", cfg.Database.User, cfg.Database.Host, cfg.Database.Port, cfg.Database.Name, cfg.Database.SSLMode),
+		Settings: map[string]string{
+			"mlServiceUrl":        cfg.App.MLServiceURL,
+			"mlModelVersion":      cfg.App.MLModelVersion,
+			"mlShadowServiceUrl":  cfg.App.MLShadowURL,
+			"dbAutoMigrate":       fmt.Sprint(cfg.Database.AutoMigrate),
+			"smtpEnabled":         fmt.Sprint(cfg.SMTP.Enabled),
+			"encryptionMasterKey": secretState(cfg.Encryption.MasterKey != "" || cfg.Encryption.MasterKeyFile != ""),
+			"jwtSecret":           secretState(cfg.JWT.Secret != ""),
+			"legacyDatabaseUrl":   secretState(cfg.Legacy.DatabaseURL != ""),
+		},
+		Warnings: configWarnings(cfg),
+	}
+
+	if *connect {
+		status, err := app.Migrator.Status(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read schema version: %w", err)
+		}
+		result.Schema = &schemaCheck{Version: status.Version, Dirty: status.Dirty, Pending: len(status.Pending)}
+		if status.Dirty || (len(status.Pending) > 0 && !cfg.Database.AutoMigrate) {
+			result.Warnings = append(result.Warnings, "server will refuse to start until the schema is migrated")
+		}
+	}
+	return result, nil
+}
+
+// configWarnings mencatat nilai default yang masih diterima Validate di luar production
+func configWarnings(cfg *utils.Config) []string {
+	warnings := []string{}
+	if cfg.JWT.Secret == "change-this-secret-key" {
+		warnings = append(warnings, "JWT_SECRET uses the default value")
+	}
+	if cfg.Encryption.MasterKey == "" && cfg.Encryption.MasterKeyFile == "" {
+		warnings = append(warnings, "ENCRYPTION_MASTER_KEY is not set, the development master key is used")
+	}
+	if cfg.Encryption.BlindIndexKey == utils.DefaultBlindIndexKey {
+		warnings = append(warnings, "BLIND_INDEX_KEY uses the default value")
+	}
+	if cfg.SMTP.Enabled && (cfg.SMTP.Username == "" || cfg.SMTP.Password == "") {
+		warnings = append(warnings, "SMTP is enabled without credentials")
+	}
+	if o := cfg.RequestLog.Overflow; o != utils.RequestLogOverflowDrop && o != utils.RequestLogOverflowBlock {
+		warnings = append(warnings, fmt.Sprintf("REQUEST_LOG_OVERFLOW %q is unknown, drop is used", o))
+	}
+	return warnings
+}
+
+func secretState(set bool) string {
+	if set {
+		return "set"
+	}
+	return "unset"
+}
@@ -0,0 +1,62 @@
+package cmd
+
+import (
+	"context"
+	"os"
+
+	"jantungin-api-server/internal/data"
+	"jantungin-api-server/pkg/utils"
+
+	"go.uber.org/zap"
+)
+
+const encryptionUsage = "usage: encryption rotate-key | reencrypt"
+
+type encryptionResult struct {
+	Action      string `json:"action"`
+	ActiveKeyID string `json:"activeKeyId,omitempty"`
+	Rewrapped   int    `json:"rewrapped"`
+}
+
+// runEncryption mengelola data key enkripsi kolom sensitif:
+//
+//	encryption rotate-key  buat data key baru dan jadikan aktif; dipakai instance yang
+//	                       start setelahnya; data lama tetap terbaca dengan kunci lama
+//	encryption reencrypt   bungkus ulang data key lalu tulis ulang kolom terenkripsi dengan kunci aktif.
+//	                       Hanya satu proses yang bisa berjalan (advisory lock).
+func runEncryption(ctx context.Context, c *cli, args []string) (any, error) {
+	if len(args) != 1 || (args[0] != "rotate-key" && args[0] != "reencrypt") {
+		return nil, usagef(encryptionUsage)
+	}
+
+	if args[0] == "rotate-key" {
+		app, err := openApp(ctx, stageSchema, os.Stderr)
+		if err != nil {
+			return nil, err
+		}
+		defer app.Close()
+
+		keyID, err := app.Envelope.Rotate(ctx)
+		if err != nil {
+			return nil, err
+		}
+		utils.Info("Encryption key rotated", zap.String("active_key_id", keyID))
+		return encryptionResult{Action: "rotate-key", ActiveKeyID: keyID}, nil
+	}
+
+	app, err := openApp(ctx, stageKeyring, os.Stderr)
+	if err != nil {
+		return nil, err
+	}
+	defer app.Close()
+
+	rewrapped, err := app.Envelope.Rewrap(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := data.ReencryptSensitiveData(ctx, app.DB); err != nil {
+		return nil, err
+	}
+	utils.Info("Re-encryption completed successfully")
+	return encryptionResult{Action: "reencrypt", Rewrapped: rewrapped}, nil
+}
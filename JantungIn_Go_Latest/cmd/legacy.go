@@ -0,0 +1,54 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+
+	"jantungin-api-server/internal/data/legacy"
+	"jantungin-api-server/pkg/utils"
+
+	"go.uber.org/zap"
+)
+
+// runMigrateLegacy menyalin data API Node lama dan bisa dilanjutkan jika terhenti.
+// Laporan rekonsiliasi selalu ditulis ke -report; perintah gagal jika ada baris yang hilang.
+func runMigrateLegacy(ctx context.Context, c *cli, args []string) (any, error) {
+	fs := c.flagSet("migrate-legacy")
+	reportPath := fs.String("report", "legacy-migration-report.json", "path laporan rekonsiliasi")
+	restart := fs.Bool("restart", false, "abaikan checkpoint dan proses ulang semua baris")
+	if err := parseFlags(fs, args); err != nil {
+		return nil, err
+	}
+
+	app, err := openApp(ctx, stageKeyring, os.Stderr)
+	if err != nil {
+		return nil, err
+	}
+	defer app.Close()
+
+	legacyDB, err := legacy.Open(app.Config.Legacy.DatabaseURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open legacy database: %w", err)
+	}
+
+	migrator := legacy.NewMigrator(legacyDB, app.DB, legacy.Options{
+		EncryptionKey: app.Config.Legacy.EncryptionKey,
+		Restart:       *restart,
+	})
+	report, err := migrator.Run(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("legacy migration failed: %w", err)
+	}
+
+	report.Log()
+	if err := report.WriteFile(*reportPath); err != nil {
+		return nil, fmt.Errorf("failed to write legacy migration report: %w", err)
+	}
+	if !report.Complete() {
+		return nil, errors.New("legacy migration finished with missing rows, see report " + *reportPath)
+	}
+	utils.Info("Legacy migration completed successfully", zap.String("report", *reportPath))
+	return report, nil
+}
@@ -0,0 +1,138 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+
+	"jantungin-api-server/pkg/database"
+	"jantungin-api-server/pkg/utils"
+
+	"go.uber.org/zap"
+)
+
+const migrateUsage = "usage: migrate up | down [steps] | status | force <version>"
+
+// migrateResult hasil migrate up/down/force
+type migrateResult struct {
+	Action  string `json:"action"`
+	Count   int    `json:"count"`
+	Version int64  `json:"version"`
+}
+
+func (r migrateResult) WriteText(w io.Writer) {
+	fmt.Fprintf(w, "%s: %d migration(s), schema version %d\n", r.Action, r.Count, r.Version)
+}
+
+type migrationInfo struct {
+	Version int64  `json:"version"`
+	Name    string `json:"name"`
+}
+
+// migrateStatusResult hasil migrate status
+type migrateStatusResult struct {
+	Version int64           `json:"version"`
+	Dirty   bool            `json:"dirty"`
+	Applied int             `json:"applied"`
+	Pending []migrationInfo `json:"pending"`
+}
+
+func (r migrateStatusResult) WriteText(w io.Writer) {
+	fmt.Fprintf(w, "version: %d (dirty: %t)\n", r.Version, r.Dirty)
+	fmt.Fprintf(w, "applied: %d, pending: %d\n", r.Applied, len(r.Pending))
+	for _, m := range r.Pending {
+		fmt.Fprintf(w, "  pending %06d_%s\n", m.Version, m.Name)
+	}
+}
+
+func runMigrateCommand(ctx context.Context, c *cli, args []string) (any, error) {
+	if len(args) == 0 {
+		return nil, usagef(migrateUsage)
+	}
+	app, err := openApp(ctx, stageDatabase, os.Stderr)
+	if err != nil {
+		return nil, err
+	}
+	defer app.Close()
+
+	return RunMigrate(ctx, app.Migrator, args)
+}
+
+// RunMigrate menjalankan subcommand migrate:
+//
+//	migrate up               jalankan semua migrasi tertunda
+//	migrate down [steps]     batalkan migrasi terakhir (default 1)
+//	migrate status           tampilkan versi skema dan migrasi tertunda
+//	migrate force <version>  tetapkan versi tanpa menjalankan migrasi (-1 = kosong)
+func RunMigrate(ctx context.Context, migrator *database.Migrator, args []string) (any, error) {
+	if len(args) == 0 {
+		return nil, usagef(migrateUsage)
+	}
+
+	switch args[0] {
+	case "up":
+		applied, err := migrator.Up(ctx)
+		if err != nil {
+			return nil, err
+		}
+		utils.Info("Migrations applied", zap.Int("count", applied))
+		return migrationOutcome(ctx, migrator, "up", applied)
+
+	case "down":
+		steps := 1
+		if len(args) > 1 {
+			n, err := strconv.Atoi(args[1])
+			if err != nil || n < 1 {
+				return nil, usagef("invalid steps %q", args[1])
+			}
+			steps = n
+		}
+		reverted, err := migrator.Down(ctx, steps)
+		if err != nil {
+			return nil, err
+		}
+		utils.Info("Migrations reverted", zap.Int("count", reverted))
+		return migrationOutcome(ctx, migrator, "down", reverted)
+
+	case "status":
+		status, err := migrator.Status(ctx)
+		if err != nil {
+			return nil, err
+		}
+		result := migrateStatusResult{
+			Version: status.Version,
+			Dirty:   status.Dirty,
+			Applied: len(status.Applied),
+			Pending: []migrationInfo{},
+		}
+		for _, m := range status.Pending {
+			result.Pending = append(result.Pending, migrationInfo{Version: m.Version, Name: m.Name})
+		}
+		return result, nil
+
+	case "force":
+		if len(args) < 2 {
+			return nil, usagef(migrateUsage)
+		}
+		version, err := strconv.ParseInt(args[1], 10, 64)
+		if err != nil {
+			return nil, usagef("invalid version %q", args[1])
+		}
+		if err := migrator.Force(ctx, version); err != nil {
+			return nil, err
+		}
+		utils.Info("Schema version forced", zap.Int64("version", version))
+		return migrateResult{Action: "force", Version: version}, nil
+	}
+	return nil, usagef(migrateUsage)
+}
+
+func migrationOutcome(ctx context.Context, migrator *database.Migrator, action string, count int) (any, error) {
+	status, err := migrator.Status(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return migrateResult{Action: action, Count: count, Version: status.Version}, nil
+}
@@ -0,0 +1,131 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"jantungin-api-server/internal/data"
+	"jantungin-api-server/internal/services"
+	"jantungin-api-server/internal/wire"
+	"jantungin-api-server/pkg/background"
+	"jantungin-api-server/pkg/database"
+	"jantungin-api-server/pkg/health"
+	"jantungin-api-server/pkg/utils"
+
+	"go.uber.org/zap"
+)
+
+// runServe menjalankan HTTP server sampai menerima SIGINT/SIGTERM
+func runServe(ctx context.Context, c *cli, args []string) (any, error) {
+	fs := c.flagSet("serve")
+	if err := parseFlags(fs, args); err != nil {
+		return nil, err
+	}
+
+	// Server menulis log ke stdout seperti sebelumnya; tidak ada output hasil
+	app, err := openApp(ctx, stageKeyring, os.Stdout)
+	if err != nil {
+		return nil, err
+	}
+	defer app.Close()
+
+	cfg := app.Config
+	utils.Info("Starting application",
+		zap.String("name", cfg.App.Name),
+		zap.String("env", cfg.App.Env),
+		zap.String("port", cfg.App.Port),
+	)
+
+	// Runner untuk pekerjaan background (import diagnosis, dsb.)
+	runner := background.NewRunner()
+	checker := newHealthChecker(app)
+	router := wire.Wiring(cfg, app.DB, runner, checker)
+	data.StartEncryptionMaintenance(runner, app.Envelope)
+
+	server := NewServer(router, cfg)
+
+	serveErr := make(chan error, 1)
+	go func() {
+		serveErr <- server.Run()
+	}()
+
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
+	select {
+	case err := <-serveErr:
+		utils.Error("Failed to start server", zap.Error(err))
+		return nil, err
+	case <-quit:
+	}
+
+	utils.Info("Shutting down application")
+	return nil, shutdown(ctx, cfg, checker, server, runner)
+}
+
+// backgroundShutdownTimeout batas waktu menunggu pekerjaan background dan request log
+const backgroundShutdownTimeout = 30 * time.Second
+
+// shutdown menghentikan aplikasi per fase dan mencatat durasi setiap fase:
+//  1. readiness: /readyz melaporkan not_ready, lalu tunggu SHUTDOWN_DRAIN_DELAY agar
+//     load balancer berhenti mengirim request baru
+//  2. http: tolak koneksi baru dan tunggu request yang sedang berjalan selesai
+//  3. background: tunggu pekerjaan background dan penulisan request log yang tertunda
+//
+// Koneksi database ditutup setelahnya oleh App.Close.
+func shutdown(ctx context.Context, cfg *utils.Config, checker *health.Checker, server *Server, runner *background.Runner) error {
+	var errs []error
+	phase := func(name string, fn func() error) {
+		start := time.Now()
+		utils.Info("Shutdown phase started", zap.String("phase", name))
+		if err := fn(); err != nil {
+			utils.Warn("Shutdown phase incomplete", zap.String("phase", name), zap.Duration("elapsed", time.Since(start)), zap.Error(err))
+			errs = append(errs, fmt.Errorf("shutdown %s: %w", name, err))
+			return
+		}
+		utils.Info("Shutdown phase completed", zap.String("phase", name), zap.Duration("elapsed", time.Since(start)))
+	}
+
+	phase("readiness", func() error {
+		checker.SetShuttingDown()
+		time.Sleep(cfg.Health.DrainDelay)
+		return nil
+	})
+	phase("http", func() error {
+		return server.Shutdown(ctx)
+	})
+	phase("background", func() error {
+		runnerCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), backgroundShutdownTimeout)
+		defer cancel()
+		return runner.Shutdown(runnerCtx)
+	})
+
+	if len(errs) > 0 {
+		return errors.Join(errs...)
+	}
+	utils.Info("Application stopped gracefully")
+	return nil
+}
+
+// newHealthChecker mendaftarkan dependensi yang dicek /readyz. Postgres dan skema wajib up;
+// ML service hanya wajib jika HEALTH_REQUIRE_ML=true, Redis hanya dicek jika REDIS_HOST diisi.
+func newHealthChecker(app *App) *health.Checker {
+	cfg := app.Config
+	checker := health.NewChecker(cfg.Health.CheckTimeout)
+	checker.Register("postgres", true, app.manager.HealthCheck)
+	checker.Register("migrations", true, app.Migrator.Check)
+	checker.Register("ml_service", cfg.Health.RequireML, services.NewMLClient(cfg.App.MLServiceURL, 0, 0).Health)
+	if cfg.App.MLShadowURL != "" {
+		checker.Register("ml_shadow_service", false, services.NewMLClient(cfg.App.MLShadowURL, 0, 0).Health)
+	}
+	if cfg.Redis.Host != "" {
+		checker.Register("redis", false, func(ctx context.Context) error {
+			return database.PingRedis(ctx, &cfg.Redis)
+		})
+	}
+	return checker
+}
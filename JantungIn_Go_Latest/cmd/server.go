@@ -2,58 +2,67 @@ package cmd
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"jantungin-api-server/pkg/utils"
 	"net/http"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"go.uber.org/zap"
 )
 
+// Server membungkus satu http.Server yang sama untuk Run dan Shutdown,
+// sehingga Shutdown benar-benar menunggu request yang sedang berjalan
 type Server struct {
-	engine *gin.Engine
+	http   *http.Server
 	config *utils.Config
 }
 
 func NewServer(engine *gin.Engine, cfg *utils.Config) *Server {
 	return &Server{
-		engine: engine,
+		http: &http.Server{
+			Addr:    fmt.Sprintf(":%s", cfg.App.Port),
+			Handler: engine,
+		},
 		config: cfg,
 	}
 }
 
+// Run melayani request sampai Shutdown dipanggil. Mengembalikan nil setelah
+// shutdown normal, atau error jika server gagal listen.
 func (s *Server) Run() error {
-	addr := fmt.Sprintf(":%s", s.config.App.Port)
-
-	srv := &http.Server{
-		Addr:    addr,
-		Handler: s.engine,
-	}
-
 	utils.Info("Starting HTTP server",
-		zap.String("address", addr),
+		zap.String("address", s.http.Addr),
 		zap.String("env", s.config.App.Env),
 	)
 
-	return srv.ListenAndServe()
+	if err := s.http.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
 }
 
+// Shutdown berhenti menerima koneksi baru lalu menunggu request yang sedang berjalan
+// selesai, paling lama SHUTDOWN_TIMEOUT. Koneksi yang tersisa setelah itu ditutup paksa.
 func (s *Server) Shutdown(ctx context.Context) error {
-	utils.Info("Shutting down HTTP server gracefully")
-
-	srv := &http.Server{
-		Addr:    fmt.Sprintf(":%s", s.config.App.Port),
-		Handler: s.engine,
-	}
+	utils.Info("Draining HTTP connections", zap.Duration("timeout", s.config.App.ShutdownTimeout))
+	start := time.Now()
 
 	shutdownCtx, cancel := context.WithTimeout(ctx, s.config.App.ShutdownTimeout)
 	defer cancel()
 
-	if err := srv.Shutdown(shutdownCtx); err != nil {
-		utils.Error("Server forced to shutdown", zap.Error(err))
+	if err := s.http.Shutdown(shutdownCtx); err != nil {
+		utils.Error("HTTP drain timed out, closing remaining connections",
+			zap.Duration("elapsed", time.Since(start)),
+			zap.Error(err),
+		)
+		if closeErr := s.http.Close(); closeErr != nil {
+			return errors.Join(err, closeErr)
+		}
 		return err
 	}
 
-	utils.Info("HTTP server stopped gracefully")
+	utils.Info("HTTP server stopped gracefully", zap.Duration("elapsed", time.Since(start)))
 	return nil
 }
@@ -0,0 +1,198 @@
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"jantungin-api-server/internal/data/entity"
+	"jantungin-api-server/internal/data/repository"
+	"jantungin-api-server/pkg/utils"
+
+	"go.uber.org/zap"
+	"golang.org/x/crypto/bcrypt"
+)
+
+const userUsage = "usage: user create-admin -username U [-name N] [-email E] [-password-stdin] | user reset-password -username U [-password-stdin]"
+
+// Sama dengan aturan Register
+const minPasswordLength = 6
+
+type userResult struct {
+	ID       string  `json:"id"`
+	Username string  `json:"username"`
+	Email    *string `json:"email,omitempty"`
+	Role     string  `json:"role"`
+	// Password hanya diisi jika dibuat otomatis (tanpa -password-stdin)
+	Password string `json:"password,omitempty"`
+}
+
+func (r userResult) WriteText(w io.Writer) {
+	fmt.Fprintf(w, "id: %s\nusername: %s\nrole: %s\n", r.ID, r.Username, r.Role)
+	if r.Password != "" {
+		fmt.Fprintf(w, "password: %s\n", r.Password)
+	}
+}
+
+func runUser(ctx context.Context, c *cli, args []string) (any, error) {
+	if len(args) == 0 {
+		return nil, usagef(userUsage)
+	}
+	switch args[0] {
+	case "create-admin":
+		return runCreateAdmin(ctx, c, args[1:])
+	case "reset-password":
+		return runResetPassword(ctx, c, args[1:])
+	}
+	return nil, usagef(userUsage)
+}
+
+// runCreateAdmin membuat akun admin baru. Password dibaca dari stdin dengan
+// -password-stdin; tanpa flag itu password acak dibuat dan ditampilkan sekali.
+func runCreateAdmin(ctx context.Context, c *cli, args []string) (any, error) {
+	fs := c.flagSet("user create-admin")
+	username := fs.String("username", "", "username admin (wajib)")
+	name := fs.String("name", "", "nama tampilan (default username)")
+	email := fs.String("email", "", "email admin (opsional)")
+	passwordStdin := fs.Bool("password-stdin", false, "baca password dari stdin")
+	if err := parseFlags(fs, args); err != nil {
+		return nil, err
+	}
+
+	normalizedUsername := strings.ToLower(strings.TrimSpace(*username))
+	if len(normalizedUsername) < 3 {
+		return nil, usagef("user create-admin: -username is required (min 3 characters)")
+	}
+	password, generated, err := c.readPassword(*passwordStdin)
+	if err != nil {
+		return nil, err
+	}
+
+	app, err := openApp(ctx, stageKeyring, os.Stderr)
+	if err != nil {
+		return nil, err
+	}
+	defer app.Close()
+
+	userRepo := repository.NewUserRepository(app.DB)
+	existing, err := userRepo.FindByUsername(ctx, normalizedUsername)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check username: %w", err)
+	}
+	if existing != nil {
+		return nil, fmt.Errorf("username %q already exists", normalizedUsername)
+	}
+
+	admin := entity.User{
+		Name:     strings.TrimSpace(*name),
+		Username: &normalizedUsername,
+		Role:     "admin",
+	}
+	if admin.Name == "" {
+		admin.Name = normalizedUsername
+	}
+	if normalizedEmail := strings.ToLower(strings.TrimSpace(*email)); normalizedEmail != "" {
+		existing, err := userRepo.FindByEmail(ctx, normalizedEmail)
+		if err != nil {
+			return nil, fmt.Errorf("failed to check email: %w", err)
+		}
+		if existing != nil {
+			return nil, fmt.Errorf("email %q already exists", normalizedEmail)
+		}
+		admin.Email = &normalizedEmail
+	}
+
+	hashed, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return nil, fmt.Errorf("failed to hash password: %w", err)
+	}
+	admin.Password = string(hashed)
+
+	if err := userRepo.Create(ctx, &admin); err != nil {
+		return nil, fmt.Errorf("failed to create admin: %w", err)
+	}
+	utils.Info("Admin user created", zap.String("user_id", admin.ID.String()))
+
+	result := userResult{ID: admin.ID.String(), Username: normalizedUsername, Email: admin.Email, Role: admin.Role}
+	if generated {
+		result.Password = password
+	}
+	return result, nil
+}
+
+// runResetPassword mengganti password user mana pun berdasarkan username
+func runResetPassword(ctx context.Context, c *cli, args []string) (any, error) {
+	fs := c.flagSet("user reset-password")
+	username := fs.String("username", "", "username yang direset (wajib)")
+	passwordStdin := fs.Bool("password-stdin", false, "baca password baru dari stdin")
+	if err := parseFlags(fs, args); err != nil {
+		return nil, err
+	}
+
+	normalizedUsername := strings.ToLower(strings.TrimSpace(*username))
+	if normalizedUsername == "" {
+		return nil, usagef("user reset-password: -username is required")
+	}
+	password, generated, err := c.readPassword(*passwordStdin)
+	if err != nil {
+		return nil, err
+	}
+
+	app, err := openApp(ctx, stageKeyring, os.Stderr)
+	if err != nil {
+		return nil, err
+	}
+	defer app.Close()
+
+	userRepo := repository.NewUserRepository(app.DB)
+	user, err := userRepo.FindByUsername(ctx, normalizedUsername)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find user: %w", err)
+	}
+	if user == nil {
+		return nil, fmt.Errorf("user %q not found", normalizedUsername)
+	}
+
+	hashed, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return nil, fmt.Errorf("failed to hash password: %w", err)
+	}
+	user.Password = string(hashed)
+	if err := userRepo.Update(ctx, user); err != nil {
+		return nil, fmt.Errorf("failed to update password: %w", err)
+	}
+	utils.Info("User password reset", zap.String("user_id", user.ID.String()))
+
+	result := userResult{ID: user.ID.String(), Username: normalizedUsername, Email: user.Email, Role: user.Role}
+	if generated {
+		result.Password = password
+	}
+	return result, nil
+}
+
+// readPassword membaca satu baris password dari stdin, atau membuat password acak
+// agar password tidak pernah muncul di argumen proses (ps, shell history)
+func (c *cli) readPassword(fromStdin bool) (password string, generated bool, err error) {
+	if !fromStdin {
+		buf := make([]byte, 12)
+		if _, err := rand.Read(buf); err != nil {
+			return "", false, err
+		}
+		return base64.RawURLEncoding.EncodeToString(buf), true, nil
+	}
+
+	line, err := bufio.NewReader(c.stdin).ReadString('\n')
+	if err != nil && err != io.EOF {
+		return "", false, fmt.Errorf("failed to read password: %w", err)
+	}
+	password = strings.TrimRight(line, "\r\n")
+	if len(password) < minPasswordLength {
+		return "", false, usagef("password must be at least %d characters", minPasswordLength)
+	}
+	return password, false, nil
+}
@@ -1,7 +1,12 @@
 package adaptor
 
 import (
+	"errors"
+
 	"jantungin-api-server/internal/usecase"
+	"jantungin-api-server/pkg/utils"
+
+	"github.com/gin-gonic/gin"
 )
 
 type Adaptor struct {
@@ -9,6 +14,12 @@ type Adaptor struct {
 	DiagnosisAdaptor *DiagnosisAdaptor
 	StatsAdaptor     *StatsAdaptor
 	PatientAdaptor   *PatientAdaptor
+	MetaAdaptor      *MetaAdaptor
+	FHIRAdaptor      *FHIRAdaptor
+	ImportAdaptor    *ImportAdaptor
+	RescoreAdaptor   *RescoreAdaptor
+	DriftAdaptor     *DriftAdaptor
+	AlertAdaptor     *AlertAdaptor
 }
 
 func NewAdaptor(usecases *usecase.UseCase) *Adaptor {
@@ -17,5 +28,21 @@ func NewAdaptor(usecases *usecase.UseCase) *Adaptor {
 		DiagnosisAdaptor: NewDiagnosisAdaptor(usecases.DiagnosisUseCase),
 		StatsAdaptor:     NewStatsAdaptor(usecases.StatsUseCase),
 		PatientAdaptor:   NewPatientAdaptor(usecases.PatientUseCase),
+		MetaAdaptor:      NewMetaAdaptor(),
+		FHIRAdaptor:      NewFHIRAdaptor(usecases.FHIRUseCase),
+		ImportAdaptor:    NewImportAdaptor(usecases.ImportUseCase),
+		RescoreAdaptor:   NewRescoreAdaptor(usecases.RescoreUseCase),
+		DriftAdaptor:     NewDriftAdaptor(usecases.DriftUseCase),
+		AlertAdaptor:     NewAlertAdaptor(usecases.AlertUseCase),
+	}
+}
+
+// handleInputError mengirim response 400 jika err berasal dari validasi input usecase
+func handleInputError(c *gin.Context, err error) bool {
+	var inputErr *usecase.InputError
+	if errors.As(err, &inputErr) {
+		utils.BadRequestResponse(c, inputErr.Message, inputErr.Details)
+		return true
 	}
+	return false
 }
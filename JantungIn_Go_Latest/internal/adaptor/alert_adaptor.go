@@ -0,0 +1,129 @@
+package adaptor
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"jantungin-api-server/internal/dto"
+	"jantungin-api-server/internal/usecase"
+	"jantungin-api-server/pkg/middleware"
+	"jantungin-api-server/pkg/utils"
+)
+
+type AlertAdaptor struct {
+	alertUsecase usecase.AlertUsecase
+}
+
+func NewAlertAdaptor(alertUsecase usecase.AlertUsecase) *AlertAdaptor {
+	return &AlertAdaptor{alertUsecase: alertUsecase}
+}
+
+// GetNotifications - alert milik user yang login, ?unacknowledged=true hanya yang belum ditindaklanjuti
+func (h *AlertAdaptor) GetNotifications(c *gin.Context) {
+	userID := c.GetString(middleware.AuthUserIDKey)
+	unacknowledgedOnly := c.Query("unacknowledged") == "true"
+
+	notifications, err := h.alertUsecase.GetNotifications(c.Request.Context(), userID, unacknowledgedOnly)
+	if err != nil {
+		switch err.Error() {
+		case "invalid user ID":
+			utils.BadRequestResponse(c, err.Error(), nil)
+		default:
+			utils.InternalServerErrorResponse(c, err.Error(), nil)
+		}
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Notifications retrieved successfully", notifications)
+}
+
+// AcknowledgeNotification - hanya penerima alert yang bisa menandai sudah ditindaklanjuti
+func (h *AlertAdaptor) AcknowledgeNotification(c *gin.Context) {
+	userID := c.GetString(middleware.AuthUserIDKey)
+
+	var req dto.AcknowledgeNotificationRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			utils.BadRequestResponse(c, "Format data tidak valid", err.Error())
+			return
+		}
+	}
+
+	notification, err := h.alertUsecase.AcknowledgeNotification(c.Request.Context(), userID, c.Param("id"), req)
+	if err != nil {
+		switch err.Error() {
+		case "notification not found":
+			utils.NotFoundResponse(c, err.Error())
+		case "invalid user ID", "invalid notification ID":
+			utils.BadRequestResponse(c, err.Error(), nil)
+		default:
+			utils.InternalServerErrorResponse(c, err.Error(), nil)
+		}
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Notification acknowledged successfully", notification)
+}
+
+func (h *AlertAdaptor) GetAlertRules(c *gin.Context) {
+	rules, err := h.alertUsecase.GetAlertRules(c.Request.Context())
+	if err != nil {
+		utils.InternalServerErrorResponse(c, err.Error(), nil)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Alert rules retrieved successfully", rules)
+}
+
+func (h *AlertAdaptor) CreateAlertRule(c *gin.Context) {
+	var req dto.AlertRuleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequestResponse(c, "Format data tidak valid", err.Error())
+		return
+	}
+
+	rule, err := h.alertUsecase.CreateAlertRule(c.Request.Context(), req)
+	if err != nil {
+		handleAlertRuleError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusCreated, "Alert rule created successfully", rule)
+}
+
+func (h *AlertAdaptor) UpdateAlertRule(c *gin.Context) {
+	var req dto.AlertRuleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequestResponse(c, "Format data tidak valid", err.Error())
+		return
+	}
+
+	rule, err := h.alertUsecase.UpdateAlertRule(c.Request.Context(), c.Param("id"), req)
+	if err != nil {
+		handleAlertRuleError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Alert rule updated successfully", rule)
+}
+
+func (h *AlertAdaptor) DeleteAlertRule(c *gin.Context) {
+	if err := h.alertUsecase.DeleteAlertRule(c.Request.Context(), c.Param("id")); err != nil {
+		handleAlertRuleError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Alert rule deleted successfully", nil)
+}
+
+func handleAlertRuleError(c *gin.Context, err error) {
+	switch err.Error() {
+	case "alert rule not found":
+		utils.NotFoundResponse(c, err.Error())
+	case "invalid alert rule ID", "threshold wajib diisi untuk aturan risk_threshold":
+		utils.BadRequestResponse(c, err.Error(), nil)
+	default:
+		utils.InternalServerErrorResponse(c, err.Error(), nil)
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"github.com/gin-gonic/gin"
 
 	"jantungin-api-server/internal/dto"
+	"jantungin-api-server/internal/identity"
 	"jantungin-api-server/internal/usecase"
 	"jantungin-api-server/pkg/middleware"
 	"jantungin-api-server/pkg/utils"
@@ -35,10 +36,15 @@ func (h *AuthAdaptor) Register(c *gin.Context) {
 		case "username wajib diisi",
 			"username minimal 3 karakter",
 			"password minimal 6 karakter",
-			"format tanggal lahir tidak valid, gunakan YYYY-MM-DD":
+			"format tanggal lahir tidak valid, gunakan YYYY-MM-DD",
+			identity.ErrNIKFormat.Error(),
+			identity.ErrNIKRegion.Error(),
+			identity.ErrNIKBirthDate.Error(),
+			identity.ErrNIKSerial.Error():
 			utils.BadRequestResponse(c, err.Error(), nil)
 		case "username sudah terdaftar",
-			"email sudah terdaftar":
+			"email sudah terdaftar",
+			"NIK sudah terdaftar":
 			utils.ErrorResponse(c, http.StatusConflict, err.Error(), nil)
 		default:
 			utils.InternalServerErrorResponse(c, err.Error(), nil)
@@ -168,8 +174,14 @@ func (h *AuthAdaptor) UpdateProfile(c *gin.Context) {
 		switch err.Error() {
 		case "user not found":
 			utils.NotFoundResponse(c, err.Error())
-		case "format tanggal lahir tidak valid, gunakan YYYY-MM-DD":
+		case "format tanggal lahir tidak valid, gunakan YYYY-MM-DD",
+			identity.ErrNIKFormat.Error(),
+			identity.ErrNIKRegion.Error(),
+			identity.ErrNIKBirthDate.Error(),
+			identity.ErrNIKSerial.Error():
 			utils.BadRequestResponse(c, err.Error(), nil)
+		case "NIK sudah terdaftar":
+			utils.ErrorResponse(c, http.StatusConflict, err.Error(), nil)
 		default:
 			utils.InternalServerErrorResponse(c, err.Error(), nil)
 		}
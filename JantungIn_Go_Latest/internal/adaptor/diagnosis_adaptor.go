@@ -1,11 +1,15 @@
 package adaptor
 
 import (
+	"errors"
+	"fmt"
+	"io"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
 
 	"jantungin-api-server/internal/dto"
+	"jantungin-api-server/internal/report"
 	"jantungin-api-server/internal/usecase"
 	"jantungin-api-server/pkg/middleware"
 	"jantungin-api-server/pkg/utils"
@@ -29,13 +33,28 @@ func (h *DiagnosisAdaptor) CreateDiagnosis(c *gin.Context) {
 		return
 	}
 
-	result, err := h.diagnosisUsecase.CreateDiagnosis(c.Request.Context(), creatorID, req)
+	// Header Idempotency-Key opsional: retry dengan key dan body yang sama mendapat hasil asli
+	var result *dto.DiagnosisResultData
+	var replayed bool
+	var err error
+	if key := c.GetHeader("Idempotency-Key"); key != "" {
+		result, replayed, err = h.diagnosisUsecase.CreateDiagnosisIdempotent(c.Request.Context(), creatorID, key, req)
+	} else {
+		result, err = h.diagnosisUsecase.CreateDiagnosis(c.Request.Context(), creatorID, req)
+	}
 	if err != nil {
+		if handleInputError(c, err) {
+			return
+		}
+
 		switch err.Error() {
 		case "pasien tidak ditemukan":
 			utils.NotFoundResponse(c, err.Error())
-		case "invalid patient ID", "invalid creator ID":
+		case "invalid patient ID", "invalid creator ID", "Idempotency-Key maksimal 255 karakter":
 			utils.BadRequestResponse(c, err.Error(), nil)
+		case "Idempotency-Key sudah dipakai untuk request yang berbeda",
+			"request dengan Idempotency-Key yang sama masih diproses":
+			utils.ErrorResponse(c, http.StatusConflict, err.Error(), nil)
 		case "gagal melakukan prediksi":
 			utils.ErrorResponse(c, http.StatusServiceUnavailable, err.Error(), nil)
 		default:
@@ -44,6 +63,9 @@ func (h *DiagnosisAdaptor) CreateDiagnosis(c *gin.Context) {
 		return
 	}
 
+	if replayed {
+		c.Header("Idempotent-Replayed", "true")
+	}
 	utils.SuccessResponse(c, http.StatusCreated, "Diagnosis created successfully", result)
 }
 
@@ -121,3 +143,221 @@ func (h *DiagnosisAdaptor) GetPatientDiagnoses(c *gin.Context) {
 
 	utils.SuccessResponse(c, http.StatusOK, "Patient diagnoses retrieved successfully", dto.ToDiagnosisResponseList(diagnoses))
 }
+
+// SimulateDiagnosis - hanya admin/dokter (enforced di route level)
+// Menjalankan skenario "what-if" dari diagnosis yang ada tanpa menyimpan hasilnya
+func (h *DiagnosisAdaptor) SimulateDiagnosis(c *gin.Context) {
+	userID := c.GetString(middleware.AuthUserIDKey)
+	role := c.GetString(middleware.AuthRoleCodeKey)
+
+	var req dto.SimulateDiagnosisRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequestResponse(c, "Format data tidak valid", err.Error())
+		return
+	}
+
+	result, err := h.diagnosisUsecase.SimulateDiagnosis(c.Request.Context(), userID, role, req)
+	if err != nil {
+		if handleInputError(c, err) {
+			return
+		}
+
+		switch err.Error() {
+		case "diagnosis not found":
+			utils.NotFoundResponse(c, err.Error())
+		case "invalid diagnosis ID":
+			utils.BadRequestResponse(c, err.Error(), nil)
+		case "gagal melakukan prediksi":
+			utils.ErrorResponse(c, http.StatusServiceUnavailable, err.Error(), nil)
+		default:
+			utils.InternalServerErrorResponse(c, err.Error(), nil)
+		}
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Simulation completed successfully", result)
+}
+
+// UpdateDiagnosis - hanya pembuat diagnosis atau admin
+// Prediksi dijalankan ulang, versi lama disimpan sebagai revisi
+func (h *DiagnosisAdaptor) UpdateDiagnosis(c *gin.Context) {
+	userID := c.GetString(middleware.AuthUserIDKey)
+	role := c.GetString(middleware.AuthRoleCodeKey)
+	diagnosisID := c.Param("id")
+
+	var req dto.UpdateDiagnosisRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequestResponse(c, "Format data tidak valid", err.Error())
+		return
+	}
+
+	result, err := h.diagnosisUsecase.UpdateDiagnosis(c.Request.Context(), userID, role, diagnosisID, req)
+	if err != nil {
+		if handleInputError(c, err) {
+			return
+		}
+		handleDiagnosisModifyError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Diagnosis updated successfully", result)
+}
+
+// DeleteDiagnosis - hanya pembuat diagnosis atau admin (soft delete)
+// Body opsional: {"reason": "..."}
+func (h *DiagnosisAdaptor) DeleteDiagnosis(c *gin.Context) {
+	userID := c.GetString(middleware.AuthUserIDKey)
+	role := c.GetString(middleware.AuthRoleCodeKey)
+	diagnosisID := c.Param("id")
+
+	var req dto.DeleteDiagnosisRequest
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		utils.BadRequestResponse(c, "Format data tidak valid", err.Error())
+		return
+	}
+
+	if err := h.diagnosisUsecase.DeleteDiagnosis(c.Request.Context(), userID, role, diagnosisID, req.Reason); err != nil {
+		handleDiagnosisModifyError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Diagnosis deleted successfully", nil)
+}
+
+// GetDiagnosisRevisions - semua user terauth, akses sama dengan GetDiagnosisByID
+func (h *DiagnosisAdaptor) GetDiagnosisRevisions(c *gin.Context) {
+	userID := c.GetString(middleware.AuthUserIDKey)
+	role := c.GetString(middleware.AuthRoleCodeKey)
+	diagnosisID := c.Param("id")
+
+	revisions, err := h.diagnosisUsecase.GetDiagnosisRevisions(c.Request.Context(), userID, role, diagnosisID)
+	if err != nil {
+		switch err.Error() {
+		case "diagnosis not found":
+			utils.NotFoundResponse(c, err.Error())
+		case "invalid diagnosis ID":
+			utils.BadRequestResponse(c, err.Error(), nil)
+		default:
+			utils.InternalServerErrorResponse(c, err.Error(), nil)
+		}
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Diagnosis revisions retrieved successfully", dto.ToDiagnosisRevisionResponseList(revisions))
+}
+
+// SubmitForReview - pembuat diagnosis atau admin, draft -> pending_review
+func (h *DiagnosisAdaptor) SubmitForReview(c *gin.Context) {
+	userID := c.GetString(middleware.AuthUserIDKey)
+	role := c.GetString(middleware.AuthRoleCodeKey)
+	diagnosisID := c.Param("id")
+
+	diagnosis, err := h.diagnosisUsecase.SubmitForReview(c.Request.Context(), userID, role, diagnosisID)
+	if err != nil {
+		handleDiagnosisModifyError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Diagnosis submitted for review", dto.ToDiagnosisResponse(*diagnosis))
+}
+
+// ReviewDiagnosis - dokter/admin selain pembuat diagnosis
+func (h *DiagnosisAdaptor) ReviewDiagnosis(c *gin.Context) {
+	userID := c.GetString(middleware.AuthUserIDKey)
+	role := c.GetString(middleware.AuthRoleCodeKey)
+	diagnosisID := c.Param("id")
+
+	var req dto.ReviewDiagnosisRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequestResponse(c, "Format data tidak valid", err.Error())
+		return
+	}
+
+	diagnosis, err := h.diagnosisUsecase.ReviewDiagnosis(c.Request.Context(), userID, role, diagnosisID, req)
+	if err != nil {
+		handleDiagnosisModifyError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Diagnosis reviewed successfully", dto.ToDiagnosisResponse(*diagnosis))
+}
+
+// GetPendingReview - antrean review untuk dokter/admin
+func (h *DiagnosisAdaptor) GetPendingReview(c *gin.Context) {
+	userID := c.GetString(middleware.AuthUserIDKey)
+
+	diagnoses, err := h.diagnosisUsecase.GetPendingReview(c.Request.Context(), userID)
+	if err != nil {
+		switch err.Error() {
+		case "invalid user ID":
+			utils.BadRequestResponse(c, err.Error(), nil)
+		default:
+			utils.InternalServerErrorResponse(c, err.Error(), nil)
+		}
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Pending review diagnoses retrieved successfully", dto.ToDiagnosisResponseList(diagnoses))
+}
+
+// GetDiagnosisReport - semua user terauth, akses sama dengan GetDiagnosisByID
+// Query param ?lang=id|en (default id)
+func (h *DiagnosisAdaptor) GetDiagnosisReport(c *gin.Context) {
+	userID := c.GetString(middleware.AuthUserIDKey)
+	role := c.GetString(middleware.AuthRoleCodeKey)
+	diagnosisID := c.Param("id")
+
+	lang, ok := report.ParseLanguage(c.Query("lang"))
+	if !ok {
+		utils.BadRequestResponse(c, "lang harus 'id' atau 'en'", nil)
+		return
+	}
+
+	pdf, err := h.diagnosisUsecase.GenerateDiagnosisReport(c.Request.Context(), userID, role, diagnosisID, lang)
+	if err != nil {
+		switch err.Error() {
+		case "diagnosis not found", "pasien tidak ditemukan":
+			utils.NotFoundResponse(c, err.Error())
+		case "invalid diagnosis ID":
+			utils.BadRequestResponse(c, err.Error(), nil)
+		default:
+			utils.InternalServerErrorResponse(c, err.Error(), nil)
+		}
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="diagnosis-%s.pdf"`, diagnosisID))
+	c.Data(http.StatusOK, "application/pdf", pdf)
+}
+
+// GetShadowReport - admin, perbandingan model utama dan shadow
+// Query param ?modelVersion= (default versi shadow yang aktif)
+func (h *DiagnosisAdaptor) GetShadowReport(c *gin.Context) {
+	result, err := h.diagnosisUsecase.GetShadowReport(c.Request.Context(), c.Query("modelVersion"))
+	if err != nil {
+		utils.InternalServerErrorResponse(c, err.Error(), nil)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Shadow model report retrieved successfully", result)
+}
+
+func handleDiagnosisModifyError(c *gin.Context, err error) {
+	switch err.Error() {
+	case "diagnosis not found":
+		utils.NotFoundResponse(c, err.Error())
+	case "invalid diagnosis ID", "invalid user ID":
+		utils.BadRequestResponse(c, err.Error(), nil)
+	case "hanya pembuat diagnosis atau admin yang dapat mengubah diagnosis ini",
+		"reviewer tidak boleh pembuat diagnosis":
+		utils.ForbiddenResponse(c, err.Error())
+	case "diagnosis telah diubah oleh pengguna lain",
+		"diagnosis sudah ditandatangani dan tidak dapat diubah",
+		"status diagnosis tidak valid untuk aksi ini":
+		utils.ErrorResponse(c, http.StatusConflict, err.Error(), nil)
+	case "gagal melakukan prediksi":
+		utils.ErrorResponse(c, http.StatusServiceUnavailable, err.Error(), nil)
+	default:
+		utils.InternalServerErrorResponse(c, err.Error(), nil)
+	}
+}
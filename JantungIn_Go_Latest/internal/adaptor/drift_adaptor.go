@@ -0,0 +1,67 @@
+package adaptor
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"jantungin-api-server/internal/usecase"
+	"jantungin-api-server/pkg/utils"
+)
+
+type DriftAdaptor struct {
+	driftUsecase usecase.DriftUsecase
+}
+
+func NewDriftAdaptor(driftUsecase usecase.DriftUsecase) *DriftAdaptor {
+	return &DriftAdaptor{driftUsecase: driftUsecase}
+}
+
+// GetCurrentDrift - drift per fitur untuk diagnosis ?days= hari terakhir (default DRIFT_WINDOW_DAYS)
+func (h *DriftAdaptor) GetCurrentDrift(c *gin.Context) {
+	days := 0
+	if raw := c.Query("days"); raw != "" {
+		parsed, err := strconv.Atoi(raw)
+		if err != nil || parsed <= 0 {
+			utils.BadRequestResponse(c, "days harus bilangan bulat positif", nil)
+			return
+		}
+		days = parsed
+	}
+
+	report, err := h.driftUsecase.GetCurrentDrift(c.Request.Context(), days)
+	if err != nil {
+		switch err.Error() {
+		case "days maksimal 365":
+			utils.BadRequestResponse(c, err.Error(), nil)
+		default:
+			utils.InternalServerErrorResponse(c, err.Error(), nil)
+		}
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Drift report retrieved successfully", report)
+}
+
+// GetDriftChecks - riwayat pengecekan drift berkala beserta alert
+func (h *DriftAdaptor) GetDriftChecks(c *gin.Context) {
+	checks, err := h.driftUsecase.GetDriftChecks(c.Request.Context())
+	if err != nil {
+		utils.InternalServerErrorResponse(c, err.Error(), nil)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Drift checks retrieved successfully", checks)
+}
+
+// RunDriftCheck - jalankan pengecekan drift sekarang dan simpan hasilnya
+func (h *DriftAdaptor) RunDriftCheck(c *gin.Context) {
+	check, err := h.driftUsecase.RunDriftCheck(c.Request.Context())
+	if err != nil {
+		utils.InternalServerErrorResponse(c, err.Error(), nil)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusCreated, "Drift check completed", check)
+}
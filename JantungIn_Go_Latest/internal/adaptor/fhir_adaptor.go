@@ -0,0 +1,179 @@
+package adaptor
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"jantungin-api-server/internal/fhir"
+	"jantungin-api-server/internal/usecase"
+	"jantungin-api-server/pkg/middleware"
+	"jantungin-api-server/pkg/utils"
+)
+
+// FHIRAdaptor melayani endpoint /fhir/r4. Response memakai format FHIR
+// (resource, Bundle, OperationOutcome), bukan utils.Response.
+type FHIRAdaptor struct {
+	fhirUsecase usecase.FHIRUsecase
+}
+
+func NewFHIRAdaptor(fhirUsecase usecase.FHIRUsecase) *FHIRAdaptor {
+	return &FHIRAdaptor{fhirUsecase: fhirUsecase}
+}
+
+func (h *FHIRAdaptor) GetPatient(c *gin.Context) {
+	userID := c.GetString(middleware.AuthUserIDKey)
+	role := c.GetString(middleware.AuthRoleCodeKey)
+
+	patient, err := h.fhirUsecase.GetPatient(c.Request.Context(), userID, role, c.Param("id"))
+	if err != nil {
+		writeFHIRError(c, err)
+		return
+	}
+
+	writeFHIR(c, http.StatusOK, patient)
+}
+
+// PatientEverything - GET /Patient/:id/$everything
+func (h *FHIRAdaptor) PatientEverything(c *gin.Context) {
+	userID := c.GetString(middleware.AuthUserIDKey)
+	role := c.GetString(middleware.AuthRoleCodeKey)
+
+	page, err := fhir.ParsePage(c.Request.URL.Query())
+	if err != nil {
+		writeFHIR(c, http.StatusBadRequest, fhir.NewOperationOutcome("invalid", err.Error()))
+		return
+	}
+
+	resources, err := h.fhirUsecase.PatientEverything(c.Request.Context(), userID, role, c.Param("id"))
+	if err != nil {
+		writeFHIRError(c, err)
+		return
+	}
+
+	writeFHIRBundle(c, resources, "Patient", page)
+}
+
+func (h *FHIRAdaptor) GetObservation(c *gin.Context) {
+	userID := c.GetString(middleware.AuthUserIDKey)
+	role := c.GetString(middleware.AuthRoleCodeKey)
+
+	observation, err := h.fhirUsecase.GetObservation(c.Request.Context(), userID, role, c.Param("id"))
+	if err != nil {
+		writeFHIRError(c, err)
+		return
+	}
+
+	writeFHIR(c, http.StatusOK, observation)
+}
+
+// SearchObservations - GET /Observation?patient=<id>&code=<code>&_count=&_offset=
+func (h *FHIRAdaptor) SearchObservations(c *gin.Context) {
+	userID := c.GetString(middleware.AuthUserIDKey)
+	role := c.GetString(middleware.AuthRoleCodeKey)
+
+	page, err := fhir.ParsePage(c.Request.URL.Query())
+	if err != nil {
+		writeFHIR(c, http.StatusBadRequest, fhir.NewOperationOutcome("invalid", err.Error()))
+		return
+	}
+
+	resources, err := h.fhirUsecase.SearchObservations(c.Request.Context(), userID, role, patientParam(c), c.Query("code"))
+	if err != nil {
+		writeFHIRError(c, err)
+		return
+	}
+
+	writeFHIRBundle(c, resources, "Observation", page)
+}
+
+func (h *FHIRAdaptor) GetRiskAssessment(c *gin.Context) {
+	userID := c.GetString(middleware.AuthUserIDKey)
+	role := c.GetString(middleware.AuthRoleCodeKey)
+
+	riskAssessment, err := h.fhirUsecase.GetRiskAssessment(c.Request.Context(), userID, role, c.Param("id"))
+	if err != nil {
+		writeFHIRError(c, err)
+		return
+	}
+
+	writeFHIR(c, http.StatusOK, riskAssessment)
+}
+
+// SearchRiskAssessments - GET /RiskAssessment?patient=<id>&_count=&_offset=
+func (h *FHIRAdaptor) SearchRiskAssessments(c *gin.Context) {
+	userID := c.GetString(middleware.AuthUserIDKey)
+	role := c.GetString(middleware.AuthRoleCodeKey)
+
+	page, err := fhir.ParsePage(c.Request.URL.Query())
+	if err != nil {
+		writeFHIR(c, http.StatusBadRequest, fhir.NewOperationOutcome("invalid", err.Error()))
+		return
+	}
+
+	resources, err := h.fhirUsecase.SearchRiskAssessments(c.Request.Context(), userID, role, patientParam(c))
+	if err != nil {
+		writeFHIRError(c, err)
+		return
+	}
+
+	writeFHIRBundle(c, resources, "RiskAssessment", page)
+}
+
+// patientParam membaca parameter pencarian patient/subject, menerima "<id>" atau "Patient/<id>"
+func patientParam(c *gin.Context) string {
+	patient := c.Query("patient")
+	if patient == "" {
+		patient = c.Query("subject")
+	}
+	return strings.TrimPrefix(patient, "Patient/")
+}
+
+func writeFHIR(c *gin.Context, status int, body any) {
+	c.Header("Content-Type", fhir.ContentType+"; charset=utf-8")
+	c.JSON(status, body)
+}
+
+func writeFHIRBundle(c *gin.Context, resources []fhir.Resource, matchType string, page fhir.Page) {
+	base := fhirBaseURL(c)
+	requestURL, err := url.Parse(base.Scheme + "://" + base.Host + c.Request.URL.RequestURI())
+	if err != nil {
+		requestURL = c.Request.URL
+	}
+
+	writeFHIR(c, http.StatusOK, fhir.NewSearchBundle(resources, matchType, page, requestURL, base.String()))
+}
+
+// fhirBaseURL membentuk base URL FHIR (…/api/v1/fhir/r4) dari request,
+// memperhatikan X-Forwarded-Proto jika berada di belakang reverse proxy
+func fhirBaseURL(c *gin.Context) *url.URL {
+	scheme := "http"
+	if c.Request.TLS != nil {
+		scheme = "https"
+	}
+	if proto := c.GetHeader("X-Forwarded-Proto"); proto != "" {
+		scheme = proto
+	}
+
+	path := c.FullPath()
+	if i := strings.Index(path, "/fhir/r4"); i >= 0 {
+		path = path[:i+len("/fhir/r4")]
+	}
+
+	return &url.URL{Scheme: scheme, Host: c.Request.Host, Path: path}
+}
+
+func writeFHIRError(c *gin.Context, err error) {
+	switch err.Error() {
+	case "resource not found":
+		writeFHIR(c, http.StatusNotFound, fhir.NewOperationOutcome("not-found", err.Error()))
+	case "parameter patient wajib diisi":
+		writeFHIR(c, http.StatusBadRequest, fhir.NewOperationOutcome("required", err.Error()))
+	default:
+		utils.Error("FHIR request failed", zap.Error(err))
+		writeFHIR(c, http.StatusInternalServerError, fhir.NewOperationOutcome("exception", "internal server error"))
+	}
+}
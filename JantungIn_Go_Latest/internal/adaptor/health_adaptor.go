@@ -0,0 +1,35 @@
+package adaptor
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"jantungin-api-server/pkg/health"
+	"jantungin-api-server/pkg/utils"
+)
+
+type HealthAdaptor struct {
+	checker *health.Checker
+}
+
+func NewHealthAdaptor(checker *health.Checker) *HealthAdaptor {
+	return &HealthAdaptor{checker: checker}
+}
+
+// Liveness GET /healthz
+// Hanya memastikan proses masih melayani request; dependensi tidak dicek
+func (h *HealthAdaptor) Liveness(c *gin.Context) {
+	utils.SuccessResponse(c, http.StatusOK, "Service is alive", gin.H{"status": health.StatusUp})
+}
+
+// Readiness GET /readyz
+// 503 jika dependensi critical down atau service sedang shutdown
+func (h *HealthAdaptor) Readiness(c *gin.Context) {
+	report := h.checker.Check(c.Request.Context())
+	if !report.Ready() {
+		utils.ErrorResponse(c, http.StatusServiceUnavailable, "Service not ready", report)
+		return
+	}
+	utils.SuccessResponse(c, http.StatusOK, "Service is ready", report)
+}
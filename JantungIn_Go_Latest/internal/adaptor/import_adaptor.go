@@ -0,0 +1,126 @@
+package adaptor
+
+import (
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"jantungin-api-server/internal/usecase"
+	"jantungin-api-server/pkg/middleware"
+	"jantungin-api-server/pkg/utils"
+)
+
+const maxImportFileSize = 5 << 20 // 5 MB
+
+type ImportAdaptor struct {
+	importUsecase usecase.ImportUsecase
+}
+
+func NewImportAdaptor(importUsecase usecase.ImportUsecase) *ImportAdaptor {
+	return &ImportAdaptor{importUsecase: importUsecase}
+}
+
+// StartImport - upload CSV/XLSX (multipart field "file"), diproses di background.
+// Response 202 berisi job; progres dipantau lewat GET /admin/diagnosis/imports/:id
+func (h *ImportAdaptor) StartImport(c *gin.Context) {
+	userID := c.GetString(middleware.AuthUserIDKey)
+
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		utils.BadRequestResponse(c, "File wajib diupload pada field 'file'", err.Error())
+		return
+	}
+	if fileHeader.Size > maxImportFileSize {
+		utils.BadRequestResponse(c, "Ukuran file maksimal 5 MB", nil)
+		return
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		utils.BadRequestResponse(c, "Gagal membaca file", err.Error())
+		return
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(io.LimitReader(file, maxImportFileSize+1))
+	if err != nil {
+		utils.BadRequestResponse(c, "Gagal membaca file", err.Error())
+		return
+	}
+	if len(data) > maxImportFileSize {
+		utils.BadRequestResponse(c, "Ukuran file maksimal 5 MB", nil)
+		return
+	}
+
+	job, err := h.importUsecase.StartImport(c.Request.Context(), userID, fileHeader.Filename, data)
+	if err != nil {
+		if handleInputError(c, err) {
+			return
+		}
+
+		switch err.Error() {
+		case "invalid user ID":
+			utils.BadRequestResponse(c, err.Error(), nil)
+		case "server sedang berhenti, silakan upload ulang":
+			utils.ErrorResponse(c, http.StatusServiceUnavailable, err.Error(), nil)
+		default:
+			utils.InternalServerErrorResponse(c, err.Error(), nil)
+		}
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusAccepted, "Import job queued", job)
+}
+
+// GetImportJobs - admin melihat semua job, dokter hanya job miliknya
+func (h *ImportAdaptor) GetImportJobs(c *gin.Context) {
+	userID := c.GetString(middleware.AuthUserIDKey)
+	role := c.GetString(middleware.AuthRoleCodeKey)
+
+	jobs, err := h.importUsecase.GetImportJobs(c.Request.Context(), userID, role)
+	if err != nil {
+		utils.InternalServerErrorResponse(c, err.Error(), nil)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Import jobs retrieved successfully", jobs)
+}
+
+func (h *ImportAdaptor) GetImportJob(c *gin.Context) {
+	userID := c.GetString(middleware.AuthUserIDKey)
+	role := c.GetString(middleware.AuthRoleCodeKey)
+
+	job, err := h.importUsecase.GetImportJob(c.Request.Context(), userID, role, c.Param("id"))
+	if err != nil {
+		handleImportError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Import job retrieved successfully", job)
+}
+
+// GetImportJobRows - laporan per baris, bisa difilter ?status=success|failed
+func (h *ImportAdaptor) GetImportJobRows(c *gin.Context) {
+	userID := c.GetString(middleware.AuthUserIDKey)
+	role := c.GetString(middleware.AuthRoleCodeKey)
+
+	rows, err := h.importUsecase.GetImportJobRows(c.Request.Context(), userID, role, c.Param("id"), c.Query("status"))
+	if err != nil {
+		handleImportError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Import job rows retrieved successfully", rows)
+}
+
+func handleImportError(c *gin.Context, err error) {
+	switch err.Error() {
+	case "import job not found":
+		utils.NotFoundResponse(c, err.Error())
+	case "invalid import job ID", "invalid row status":
+		utils.BadRequestResponse(c, err.Error(), nil)
+	default:
+		utils.InternalServerErrorResponse(c, err.Error(), nil)
+	}
+}
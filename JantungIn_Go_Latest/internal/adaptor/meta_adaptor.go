@@ -0,0 +1,22 @@
+package adaptor
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"jantungin-api-server/internal/vocabulary"
+	"jantungin-api-server/pkg/utils"
+)
+
+type MetaAdaptor struct{}
+
+func NewMetaAdaptor() *MetaAdaptor {
+	return &MetaAdaptor{}
+}
+
+// GetVocabulary GET /api/v1/meta/vocabulary
+// Nilai kanonik, label id/en, dan alias untuk setiap field kategorikal diagnosis
+func (h *MetaAdaptor) GetVocabulary(c *gin.Context) {
+	utils.SuccessResponse(c, http.StatusOK, "Vocabulary retrieved successfully", vocabulary.Fields())
+}
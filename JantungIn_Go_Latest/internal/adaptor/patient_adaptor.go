@@ -49,7 +49,25 @@ func (h *PatientAdaptor) GetPatientByID(c *gin.Context) {
 	utils.SuccessResponse(c, http.StatusOK, "Patient retrieved successfully", dto.ToPatientResponse(*patient))
 }
 
-// SearchPatients GET /api/v1/admin/patients/search?query=...
+// GetPatientTrend GET /api/v1/admin/patients/:id/trend
+func (h *PatientAdaptor) GetPatientTrend(c *gin.Context) {
+	trend, err := h.patientUsecase.GetPatientTrend(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		switch err.Error() {
+		case "invalid patient ID":
+			utils.BadRequestResponse(c, err.Error(), nil)
+		case "patient not found":
+			utils.NotFoundResponse(c, err.Error())
+		default:
+			utils.InternalServerErrorResponse(c, err.Error(), nil)
+		}
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Patient trend retrieved successfully", trend)
+}
+
+// SearchPatients GET /api/v1/admin/patients/search?query=... (nama atau NIK 16 digit)
 func (h *PatientAdaptor) SearchPatients(c *gin.Context) {
 	query := c.Query("query")
 
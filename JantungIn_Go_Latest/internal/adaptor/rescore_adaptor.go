@@ -0,0 +1,87 @@
+package adaptor
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"jantungin-api-server/internal/usecase"
+	"jantungin-api-server/pkg/middleware"
+	"jantungin-api-server/pkg/utils"
+)
+
+type RescoreAdaptor struct {
+	rescoreUsecase usecase.RescoreUsecase
+}
+
+func NewRescoreAdaptor(rescoreUsecase usecase.RescoreUsecase) *RescoreAdaptor {
+	return &RescoreAdaptor{rescoreUsecase: rescoreUsecase}
+}
+
+// StartRescore - admin memicu prediksi ulang semua diagnosis dengan model saat ini.
+// Response 202 berisi job; progres dan laporan lewat GET /admin/diagnosis/rescore/:id
+func (h *RescoreAdaptor) StartRescore(c *gin.Context) {
+	userID := c.GetString(middleware.AuthUserIDKey)
+
+	job, err := h.rescoreUsecase.StartRescore(c.Request.Context(), userID)
+	if err != nil {
+		switch err.Error() {
+		case "invalid user ID":
+			utils.BadRequestResponse(c, err.Error(), nil)
+		case "rescore job lain masih berjalan":
+			utils.ErrorResponse(c, http.StatusConflict, err.Error(), nil)
+		case "server sedang berhenti, silakan coba lagi":
+			utils.ErrorResponse(c, http.StatusServiceUnavailable, err.Error(), nil)
+		default:
+			utils.InternalServerErrorResponse(c, err.Error(), nil)
+		}
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusAccepted, "Rescore job queued", job)
+}
+
+func (h *RescoreAdaptor) GetRescoreJobs(c *gin.Context) {
+	jobs, err := h.rescoreUsecase.GetRescoreJobs(c.Request.Context())
+	if err != nil {
+		utils.InternalServerErrorResponse(c, err.Error(), nil)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Rescore jobs retrieved successfully", jobs)
+}
+
+// GetRescoreReport - status job beserta ringkasan perpindahan kategori risiko
+func (h *RescoreAdaptor) GetRescoreReport(c *gin.Context) {
+	report, err := h.rescoreUsecase.GetRescoreReport(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		handleRescoreError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Rescore report retrieved successfully", report)
+}
+
+// GetRescoreScores - hasil per diagnosis, ?changed=true hanya yang kategorinya berubah
+func (h *RescoreAdaptor) GetRescoreScores(c *gin.Context) {
+	changedOnly := c.Query("changed") == "true"
+
+	scores, err := h.rescoreUsecase.GetRescoreScores(c.Request.Context(), c.Param("id"), changedOnly)
+	if err != nil {
+		handleRescoreError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Rescore scores retrieved successfully", scores)
+}
+
+func handleRescoreError(c *gin.Context, err error) {
+	switch err.Error() {
+	case "rescore job not found":
+		utils.NotFoundResponse(c, err.Error())
+	case "invalid rescore job ID":
+		utils.BadRequestResponse(c, err.Error(), nil)
+	default:
+		utils.InternalServerErrorResponse(c, err.Error(), nil)
+	}
+}
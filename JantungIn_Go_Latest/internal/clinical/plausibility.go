@@ -0,0 +1,202 @@
+// Package clinical berisi pemeriksaan kewajaran nilai klinis dan konversi
+// satuan sebelum input dikirim ke model.
+package clinical
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+type Severity string
+
+const (
+	// SeverityWarning: nilai tidak lazim tetapi masih mungkin, diagnosis tetap diproses
+	SeverityWarning Severity = "warning"
+	// SeverityError: nilai tidak mungkin secara fisiologis, input ditolak
+	SeverityError Severity = "error"
+)
+
+// Finding adalah satu hasil pemeriksaan kewajaran untuk sebuah field
+type Finding struct {
+	Field    string   `json:"field"`
+	Value    float64  `json:"value"`
+	Unit     string   `json:"unit,omitempty"`
+	Severity Severity `json:"severity"`
+	Message  string   `json:"message"`
+}
+
+// Range mendefinisikan batas keras (ditolak) dan batas lunak (peringatan)
+// dalam satuan yang dipakai model.
+type Range struct {
+	Field   string
+	Label   string
+	Unit    string
+	HardMin float64
+	HardMax float64
+	SoftMin float64
+	SoftMax float64
+}
+
+var (
+	AgeRange = Range{Field: "age", Label: "Usia", Unit: "tahun",
+		HardMin: 1, HardMax: 120, SoftMin: 18, SoftMax: 90}
+	RestingBloodPressureRange = Range{Field: "restingBloodPressure", Label: "Tekanan darah istirahat", Unit: "mmHg",
+		HardMin: 50, HardMax: 300, SoftMin: 90, SoftMax: 200}
+	SerumCholesterolRange = Range{Field: "serumCholesterol", Label: "Kolesterol serum", Unit: UnitMgDL,
+		HardMin: 50, HardMax: 1000, SoftMin: 100, SoftMax: 400}
+	FastingBloodSugarRange = Range{Field: "fastingBloodSugar", Label: "Gula darah puasa", Unit: UnitMgDL,
+		HardMin: 20, HardMax: 1000, SoftMin: 60, SoftMax: 300}
+	MaximumHeartRateRange = Range{Field: "maximumHeartRate", Label: "Denyut jantung maksimum", Unit: "bpm",
+		HardMin: 40, HardMax: 250, SoftMin: 60, SoftMax: 220}
+	StDepressionRange = Range{Field: "stDepression", Label: "Depresi ST", Unit: "mm",
+		HardMin: 0, HardMax: 10, SoftMin: 0, SoftMax: 6}
+)
+
+// Ranges mengembalikan semua rentang yang diperiksa, sesuai urutan form diagnosis
+func Ranges() []Range {
+	return []Range{
+		AgeRange,
+		RestingBloodPressureRange,
+		SerumCholesterolRange,
+		FastingBloodSugarRange,
+		MaximumHeartRateRange,
+		StDepressionRange,
+	}
+}
+
+func (r Range) check(value float64) *Finding {
+	switch {
+	case value < r.HardMin || value > r.HardMax:
+		return &Finding{
+			Field:    r.Field,
+			Value:    value,
+			Unit:     r.Unit,
+			Severity: SeverityError,
+			Message: fmt.Sprintf("%s %s %s di luar rentang fisiologis (%s–%s %s)",
+				r.Label, formatNumber(value), r.Unit, formatNumber(r.HardMin), formatNumber(r.HardMax), r.Unit),
+		}
+	case value < r.SoftMin || value > r.SoftMax:
+		return &Finding{
+			Field:    r.Field,
+			Value:    value,
+			Unit:     r.Unit,
+			Severity: SeverityWarning,
+			Message: fmt.Sprintf("%s %s %s di luar rentang umum (%s–%s %s), periksa kembali input",
+				r.Label, formatNumber(value), r.Unit, formatNumber(r.SoftMin), formatNumber(r.SoftMax), r.Unit),
+		}
+	}
+	return nil
+}
+
+// Measurements adalah nilai numerik input diagnosis beserta satuan laboratorium
+type Measurements struct {
+	Age                  float64
+	RestingBloodPressure float64
+	SerumCholesterol     float64
+	CholesterolUnit      string
+	FastingBloodSugar    float64
+	GlucoseUnit          string
+	MaximumHeartRate     float64
+	StDepression         float64
+}
+
+// PlausibilityError dikembalikan jika ada nilai yang melanggar batas keras
+type PlausibilityError struct {
+	Findings []Finding
+}
+
+func (e *PlausibilityError) Error() string {
+	messages := make([]string, len(e.Findings))
+	for i, f := range e.Findings {
+		messages[i] = f.Message
+	}
+	return strings.Join(messages, "; ")
+}
+
+// Assess mengonversi kolesterol dan gula darah ke mg/dL lalu memeriksa semua
+// nilai terhadap Ranges. Nilai hasil konversi dikembalikan bersama peringatan.
+// Jika ada pelanggaran batas keras, error berupa *PlausibilityError.
+func Assess(m Measurements) (Measurements, []Finding, error) {
+	var errs []Finding
+
+	cholesterol, err := ToMgDL(m.SerumCholesterol, m.CholesterolUnit, CholesterolMmolToMgDL)
+	if err != nil {
+		errs = append(errs, unitFinding(SerumCholesterolRange.Field, m.SerumCholesterol, m.CholesterolUnit))
+	}
+	glucose, err := ToMgDL(m.FastingBloodSugar, m.GlucoseUnit, GlucoseMmolToMgDL)
+	if err != nil {
+		errs = append(errs, unitFinding(FastingBloodSugarRange.Field, m.FastingBloodSugar, m.GlucoseUnit))
+	}
+	if len(errs) > 0 {
+		return m, nil, &PlausibilityError{Findings: errs}
+	}
+
+	m.SerumCholesterol, m.CholesterolUnit = cholesterol, UnitMgDL
+	m.FastingBloodSugar, m.GlucoseUnit = glucose, UnitMgDL
+
+	values := []float64{
+		m.Age,
+		m.RestingBloodPressure,
+		m.SerumCholesterol,
+		m.FastingBloodSugar,
+		m.MaximumHeartRate,
+		m.StDepression,
+	}
+
+	warnings := []Finding{}
+	for i, r := range Ranges() {
+		finding := r.check(values[i])
+		if finding == nil {
+			continue
+		}
+		if finding.Severity == SeverityError {
+			errs = append(errs, withUnitHint(*finding, r))
+		} else {
+			warnings = append(warnings, *finding)
+		}
+	}
+
+	if len(errs) > 0 {
+		return m, warnings, &PlausibilityError{Findings: errs}
+	}
+	return m, warnings, nil
+}
+
+// withUnitHint menambahkan petunjuk jika nilai mg/dL yang terlalu kecil
+// kemungkinan besar sebenarnya dalam mmol/L.
+func withUnitHint(f Finding, r Range) Finding {
+	var factor float64
+	var unitField string
+	switch r.Field {
+	case SerumCholesterolRange.Field:
+		factor, unitField = CholesterolMmolToMgDL, "cholesterolUnit"
+	case FastingBloodSugarRange.Field:
+		factor, unitField = GlucoseMmolToMgDL, "glucoseUnit"
+	default:
+		return f
+	}
+
+	converted := f.Value * factor
+	if f.Value < r.HardMin && converted >= r.HardMin && converted <= r.HardMax {
+		f.Message += fmt.Sprintf(", jika nilai dalam mmol/L kirim %s=%q", unitField, UnitMmolL)
+	}
+	return f
+}
+
+func unitFinding(field string, value float64, unit string) Finding {
+	return Finding{
+		Field:    field,
+		Value:    value,
+		Unit:     unit,
+		Severity: SeverityError,
+		Message:  fmt.Sprintf("satuan %q tidak dikenali untuk %s, gunakan %q atau %q", unit, field, UnitMgDL, UnitMmolL),
+	}
+}
+
+func formatNumber(v float64) string {
+	if v == math.Trunc(v) {
+		return fmt.Sprintf("%.0f", v)
+	}
+	return fmt.Sprintf("%.1f", v)
+}
@@ -0,0 +1,125 @@
+package clinical
+
+import (
+	"errors"
+	"testing"
+)
+
+// normal adalah input yang berada di dalam semua rentang lunak
+var normal = Measurements{
+	Age:                  54,
+	RestingBloodPressure: 130,
+	SerumCholesterol:     240,
+	FastingBloodSugar:    100,
+	MaximumHeartRate:     150,
+	StDepression:         1,
+}
+
+func TestRangeBoundaries(t *testing.T) {
+	for _, r := range Ranges() {
+		tests := []struct {
+			name  string
+			value float64
+			want  Severity // "" berarti tidak ada finding
+		}{
+			{name: "di bawah batas keras", value: r.HardMin - 0.1, want: SeverityError},
+			{name: "tepat batas keras bawah", value: r.HardMin, want: lowerSoft(r)},
+			{name: "tepat batas lunak bawah", value: r.SoftMin},
+			{name: "tepat batas lunak atas", value: r.SoftMax},
+			{name: "di atas batas lunak", value: r.SoftMax + 0.1, want: SeverityWarning},
+			{name: "tepat batas keras atas", value: r.HardMax, want: SeverityWarning},
+			{name: "di atas batas keras", value: r.HardMax + 0.1, want: SeverityError},
+		}
+
+		for _, tt := range tests {
+			t.Run(r.Field+"/"+tt.name, func(t *testing.T) {
+				finding := r.check(tt.value)
+				var got Severity
+				if finding != nil {
+					got = finding.Severity
+				}
+				if got != tt.want {
+					t.Errorf("check(%v) severity = %q, want %q", tt.value, got, tt.want)
+				}
+			})
+		}
+	}
+}
+
+// lowerSoft mengembalikan severity nilai HardMin: peringatan kecuali batas
+// keras dan lunak bawah sama (mis. depresi ST).
+func lowerSoft(r Range) Severity {
+	if r.HardMin < r.SoftMin {
+		return SeverityWarning
+	}
+	return ""
+}
+
+func TestAssess(t *testing.T) {
+	tests := []struct {
+		name         string
+		modify       func(m *Measurements)
+		wantErr      bool
+		wantWarnings int
+		wantChol     float64
+		wantGlucose  float64
+	}{
+		{name: "normal", modify: func(m *Measurements) {}, wantChol: 240, wantGlucose: 100},
+		{
+			name: "mmol/L dikonversi ke mg/dL",
+			modify: func(m *Measurements) {
+				m.SerumCholesterol, m.CholesterolUnit = 5.2, UnitMmolL
+				m.FastingBloodSugar, m.GlucoseUnit = 5.5, "mmol/l"
+			},
+			wantChol: 201.1, wantGlucose: 99.1,
+		},
+		{
+			name:    "mmol/L tanpa satuan ditolak",
+			modify:  func(m *Measurements) { m.SerumCholesterol = 5.2 },
+			wantErr: true,
+		},
+		{
+			name:    "batas keras dicek setelah konversi",
+			modify:  func(m *Measurements) { m.FastingBloodSugar, m.GlucoseUnit = 60, UnitMmolL },
+			wantErr: true,
+		},
+		{
+			name:         "peringatan tidak menolak input",
+			modify:       func(m *Measurements) { m.Age, m.RestingBloodPressure = 95, 210 },
+			wantWarnings: 2, wantChol: 240, wantGlucose: 100,
+		},
+		{
+			name:    "satuan tidak dikenal",
+			modify:  func(m *Measurements) { m.CholesterolUnit = "mEq/L" },
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := normal
+			tt.modify(&m)
+
+			got, warnings, err := Assess(m)
+			if tt.wantErr {
+				var plausibilityErr *PlausibilityError
+				if !errors.As(err, &plausibilityErr) {
+					t.Fatalf("Assess error = %v, want *PlausibilityError", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Assess error = %v", err)
+			}
+			if len(warnings) != tt.wantWarnings {
+				t.Errorf("warnings = %d, want %d", len(warnings), tt.wantWarnings)
+			}
+			if got.SerumCholesterol != tt.wantChol || got.CholesterolUnit != UnitMgDL {
+				t.Errorf("cholesterol = %v %s, want %v %s", got.SerumCholesterol, got.CholesterolUnit, tt.wantChol, UnitMgDL)
+			}
+			if got.FastingBloodSugar != tt.wantGlucose || got.GlucoseUnit != UnitMgDL {
+				t.Errorf("glucose = %v %s, want %v %s", got.FastingBloodSugar, got.GlucoseUnit, tt.wantGlucose, UnitMgDL)
+			}
+		})
+	}
+}
@@ -0,0 +1,42 @@
+package clinical
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+const (
+	UnitMgDL  = "mg/dL"
+	UnitMmolL = "mmol/L"
+)
+
+// Faktor konversi mmol/L ke mg/dL
+const (
+	CholesterolMmolToMgDL = 38.67
+	GlucoseMmolToMgDL     = 18.016
+)
+
+// ParseUnit menerima variasi penulisan satuan. String kosong dianggap mg/dL
+// karena itu satuan yang dipakai model.
+func ParseUnit(unit string) (string, error) {
+	switch strings.ToLower(strings.ReplaceAll(strings.TrimSpace(unit), " ", "")) {
+	case "", "mg/dl", "mgdl", "mg":
+		return UnitMgDL, nil
+	case "mmol/l", "mmoll", "mmol":
+		return UnitMmolL, nil
+	}
+	return "", fmt.Errorf("satuan %q tidak dikenali", unit)
+}
+
+// ToMgDL mengonversi nilai ke mg/dL, dibulatkan 1 desimal
+func ToMgDL(value float64, unit string, factor float64) (float64, error) {
+	parsed, err := ParseUnit(unit)
+	if err != nil {
+		return 0, err
+	}
+	if parsed == UnitMgDL {
+		return value, nil
+	}
+	return math.Round(value*factor*10) / 10, nil
+}
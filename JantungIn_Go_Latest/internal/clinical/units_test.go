@@ -0,0 +1,39 @@
+package clinical
+
+import "testing"
+
+func TestToMgDL(t *testing.T) {
+	tests := []struct {
+		name   string
+		value  float64
+		unit   string
+		factor float64
+		want   float64
+	}{
+		{name: "kolesterol mmol/L", value: 5.2, unit: UnitMmolL, factor: CholesterolMmolToMgDL, want: 201.1},
+		{name: "kolesterol 1 mmol/L", value: 1, unit: UnitMmolL, factor: CholesterolMmolToMgDL, want: 38.7},
+		{name: "glukosa mmol/L", value: 5.5, unit: UnitMmolL, factor: GlucoseMmolToMgDL, want: 99.1},
+		{name: "glukosa 7 mmol/L (batas diabetes)", value: 7, unit: "mmol", factor: GlucoseMmolToMgDL, want: 126.1},
+		{name: "mg/dL tidak dikonversi", value: 240, unit: UnitMgDL, factor: CholesterolMmolToMgDL, want: 240},
+		{name: "satuan kosong dianggap mg/dL", value: 240, unit: "", factor: CholesterolMmolToMgDL, want: 240},
+		{name: "variasi penulisan", value: 5.2, unit: " MMOL / L ", factor: CholesterolMmolToMgDL, want: 201.1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ToMgDL(tt.value, tt.unit, tt.factor)
+			if err != nil {
+				t.Fatalf("ToMgDL error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("ToMgDL(%v, %q) = %v, want %v", tt.value, tt.unit, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestToMgDLUnknownUnit(t *testing.T) {
+	if _, err := ToMgDL(5, "mEq/L", GlucoseMmolToMgDL); err == nil {
+		t.Fatal("ToMgDL dengan satuan tidak dikenal harus error")
+	}
+}
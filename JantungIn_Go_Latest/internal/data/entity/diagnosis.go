@@ -4,32 +4,63 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"gorm.io/gorm"
 )
 
+// Status review diagnosis: draft -> pending_review -> reviewed -> signed.
+// Diagnosis yang sudah signed tidak bisa diubah atau dihapus.
+const (
+	DiagnosisStatusDraft         = "draft"
+	DiagnosisStatusPendingReview = "pending_review"
+	DiagnosisStatusReviewed      = "reviewed"
+	DiagnosisStatusSigned        = "signed"
+)
+
+// Diagnosis menyimpan input klinis dan catatan terenkripsi (serializer encrypted, pkg/fieldcrypt).
+// Hasil prediksi tetap plaintext untuk statistik dan laporan.
 type Diagnosis struct {
 	ID                    uuid.UUID  `gorm:"type:uuid;default:gen_random_uuid();primaryKey" json:"id"`
 	UserID                uuid.UUID  `gorm:"type:uuid;not null" json:"userId"`
 	CreatedBy             *uuid.UUID `gorm:"type:uuid" json:"createdBy"` // Bisa null
-	Age                   int        `gorm:"not null" json:"age"`
-	Sex                   string     `gorm:"not null" json:"sex"`
-	ChestPainType         string     `gorm:"not null" json:"chestPainType"`
-	RestingEcgResults     string     `gorm:"not null" json:"restingEcgResults"`
-	FastingBloodSugar     float64    `gorm:"not null" json:"fastingBloodSugar"`
-	RestingBloodPressure  float64    `gorm:"not null" json:"restingBloodPressure"`
-	MaximumHeartRate      int        `gorm:"not null" json:"maximumHeartRate"`
-	ExerciseInducedAngina string     `gorm:"not null" json:"exerciseInducedAngina"`
-	StSegment             string     `gorm:"not null" json:"stSegment"`
-	MajorVessels          int        `gorm:"not null" json:"majorVessels"`
-	Thalassemia           string     `gorm:"not null" json:"thalassemia"`
-	SerumCholesterol      float64    `gorm:"not null" json:"serumCholesterol"`
-	StDepression          float64    `gorm:"not null" json:"stDepression"`
+	Age                   int        `gorm:"type:text;not null;serializer:encrypted" json:"age"`
+	Sex                   string     `gorm:"type:text;not null;serializer:encrypted" json:"sex"`
+	ChestPainType         string     `gorm:"type:text;not null;serializer:encrypted" json:"chestPainType"`
+	RestingEcgResults     string     `gorm:"type:text;not null;serializer:encrypted" json:"restingEcgResults"`
+	FastingBloodSugar     float64    `gorm:"type:text;not null;serializer:encrypted" json:"fastingBloodSugar"`
+	RestingBloodPressure  float64    `gorm:"type:text;not null;serializer:encrypted" json:"restingBloodPressure"`
+	MaximumHeartRate      int        `gorm:"type:text;not null;serializer:encrypted" json:"maximumHeartRate"`
+	ExerciseInducedAngina string     `gorm:"type:text;not null;serializer:encrypted" json:"exerciseInducedAngina"`
+	StSegment             string     `gorm:"type:text;not null;serializer:encrypted" json:"stSegment"`
+	MajorVessels          int        `gorm:"type:text;not null;serializer:encrypted" json:"majorVessels"`
+	Thalassemia           string     `gorm:"type:text;not null;serializer:encrypted" json:"thalassemia"`
+	SerumCholesterol      float64    `gorm:"type:text;not null;serializer:encrypted" json:"serumCholesterol"`
+	StDepression          float64    `gorm:"type:text;not null;serializer:encrypted" json:"stDepression"`
 	ResultPercentage      float64    `gorm:"not null" json:"resultPercentage"`
 	CardiovascularRisk    string     `gorm:"not null" json:"cardiovascularRisk"`
 	Prediction            string     `gorm:"default:'Berisiko';not null" json:"prediction"`
+	ModelVersion          string     `gorm:"type:varchar(50);not null;default:''" json:"modelVersion"` // versi model ML yang menghasilkan prediksi
+	Version               int        `gorm:"not null;default:1" json:"version"`                        // naik setiap kali diubah, versi lama ada di diagnosis_revisions
+	ClinicalNotes         string     `gorm:"type:text;not null;default:'';serializer:encrypted" json:"clinicalNotes"`
 	CreatedAt             time.Time  `json:"createdAt"`
 	UpdatedAt             time.Time  `json:"updatedAt"`
 
+	// Review / sign-off
+	Status      string     `gorm:"type:varchar(20);not null;default:draft;index" json:"status"`
+	ReviewedBy  *uuid.UUID `gorm:"type:uuid" json:"reviewedBy"`
+	ReviewedAt  *time.Time `json:"reviewedAt"`
+	ReviewNotes string     `gorm:"type:text;not null;default:'';serializer:encrypted" json:"reviewNotes"`
+
+	// Soft delete
+	DeletedAt gorm.DeletedAt `gorm:"index" json:"-"`
+	DeletedBy *uuid.UUID     `gorm:"type:uuid" json:"-"`
+
 	// Relasi
-	Patient User `gorm:"foreignKey:UserID" json:"patient,omitzero"`
-	Creator User `gorm:"foreignKey:CreatedBy" json:"creator,omitzero"`
+	Patient  User `gorm:"foreignKey:UserID" json:"patient,omitzero"`
+	Creator  User `gorm:"foreignKey:CreatedBy" json:"creator,omitzero"`
+	Reviewer User `gorm:"foreignKey:ReviewedBy" json:"reviewer,omitzero"`
+}
+
+// IsSigned true jika diagnosis sudah ditandatangani dan menjadi read-only
+func (d Diagnosis) IsSigned() bool {
+	return d.Status == DiagnosisStatusSigned
 }
@@ -0,0 +1,83 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	RevisionActionUpdate = "update"
+	RevisionActionDelete = "delete"
+)
+
+// DiagnosisRevision menyimpan snapshot diagnosis sebelum diubah atau dihapus,
+// beserta siapa yang mengubah dan alasannya.
+type DiagnosisRevision struct {
+	ID          uuid.UUID `gorm:"type:uuid;default:gen_random_uuid();primaryKey" json:"id"`
+	DiagnosisID uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_diagnosis_revisions_version" json:"diagnosisId"`
+	Version     int       `gorm:"not null;uniqueIndex:idx_diagnosis_revisions_version" json:"version"`
+	Action      string    `gorm:"type:varchar(20);not null" json:"action"`
+	EditedBy    uuid.UUID `gorm:"type:uuid;not null" json:"editedBy"`
+	Reason      string    `gorm:"type:text;not null;default:''" json:"reason"`
+
+	// Snapshot nilai diagnosis pada versi ini
+	Age                   int     `gorm:"type:text;not null;serializer:encrypted" json:"age"`
+	Sex                   string  `gorm:"type:text;not null;serializer:encrypted" json:"sex"`
+	ChestPainType         string  `gorm:"type:text;not null;serializer:encrypted" json:"chestPainType"`
+	RestingEcgResults     string  `gorm:"type:text;not null;serializer:encrypted" json:"restingEcgResults"`
+	FastingBloodSugar     float64 `gorm:"type:text;not null;serializer:encrypted" json:"fastingBloodSugar"`
+	RestingBloodPressure  float64 `gorm:"type:text;not null;serializer:encrypted" json:"restingBloodPressure"`
+	MaximumHeartRate      int     `gorm:"type:text;not null;serializer:encrypted" json:"maximumHeartRate"`
+	ExerciseInducedAngina string  `gorm:"type:text;not null;serializer:encrypted" json:"exerciseInducedAngina"`
+	StSegment             string  `gorm:"type:text;not null;serializer:encrypted" json:"stSegment"`
+	MajorVessels          int     `gorm:"type:text;not null;serializer:encrypted" json:"majorVessels"`
+	Thalassemia           string  `gorm:"type:text;not null;serializer:encrypted" json:"thalassemia"`
+	SerumCholesterol      float64 `gorm:"type:text;not null;serializer:encrypted" json:"serumCholesterol"`
+	StDepression          float64 `gorm:"type:text;not null;serializer:encrypted" json:"stDepression"`
+	ResultPercentage      float64 `gorm:"not null" json:"resultPercentage"`
+	CardiovascularRisk    string  `gorm:"not null" json:"cardiovascularRisk"`
+	Prediction            string  `gorm:"not null" json:"prediction"`
+	ModelVersion          string  `gorm:"type:varchar(50);not null;default:''" json:"modelVersion"`
+	ClinicalNotes         string  `gorm:"type:text;not null;default:'';serializer:encrypted" json:"clinicalNotes"`
+	Status                string  `gorm:"type:varchar(20);not null;default:''" json:"status"` // status review saat snapshot diambil
+
+	CreatedAt time.Time `json:"createdAt"`
+
+	// Relasi
+	Editor User `gorm:"foreignKey:EditedBy" json:"editor,omitzero"`
+}
+
+func (DiagnosisRevision) TableName() string {
+	return "diagnosis_revisions"
+}
+
+// NewDiagnosisRevision membuat snapshot dari kondisi diagnosis saat ini
+func NewDiagnosisRevision(d Diagnosis, action string, editedBy uuid.UUID, reason string) DiagnosisRevision {
+	return DiagnosisRevision{
+		DiagnosisID:           d.ID,
+		Version:               d.Version,
+		Action:                action,
+		EditedBy:              editedBy,
+		Reason:                reason,
+		Age:                   d.Age,
+		Sex:                   d.Sex,
+		ChestPainType:         d.ChestPainType,
+		RestingEcgResults:     d.RestingEcgResults,
+		FastingBloodSugar:     d.FastingBloodSugar,
+		RestingBloodPressure:  d.RestingBloodPressure,
+		MaximumHeartRate:      d.MaximumHeartRate,
+		ExerciseInducedAngina: d.ExerciseInducedAngina,
+		StSegment:             d.StSegment,
+		MajorVessels:          d.MajorVessels,
+		Thalassemia:           d.Thalassemia,
+		SerumCholesterol:      d.SerumCholesterol,
+		StDepression:          d.StDepression,
+		ResultPercentage:      d.ResultPercentage,
+		CardiovascularRisk:    d.CardiovascularRisk,
+		Prediction:            d.Prediction,
+		ModelVersion:          d.ModelVersion,
+		ClinicalNotes:         d.ClinicalNotes,
+		Status:                d.Status,
+	}
+}
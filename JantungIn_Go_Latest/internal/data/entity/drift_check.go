@@ -0,0 +1,37 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// DriftFeature adalah hasil perbandingan satu fitur input dengan statistik data training
+type DriftFeature struct {
+	Feature       string  `json:"feature"`
+	SampleSize    int     `json:"sampleSize"`
+	TrainingMean  float64 `json:"trainingMean"`
+	TrainingScale float64 `json:"trainingScale"`
+	SampleMean    float64 `json:"sampleMean"`
+	SampleStdDev  float64 `json:"sampleStdDev"`
+	MeanShift     float64 `json:"meanShift"` // (sampleMean - trainingMean) / trainingScale
+	ZScore        float64 `json:"zScore"`    // z-score rata-rata sampel terhadap rata-rata training
+	PSI           float64 `json:"psi"`       // population stability index
+	Drifted       bool    `json:"drifted"`
+}
+
+// DriftCheck adalah hasil satu pengecekan drift berkala atas diagnosis dalam satu jendela waktu
+type DriftCheck struct {
+	ID              uuid.UUID      `gorm:"type:uuid;default:gen_random_uuid();primaryKey" json:"id"`
+	WindowStart     time.Time      `gorm:"not null" json:"windowStart"`
+	WindowEnd       time.Time      `gorm:"not null" json:"windowEnd"`
+	SampleSize      int            `gorm:"not null;default:0" json:"sampleSize"`
+	Alert           bool           `gorm:"not null;default:false;index" json:"alert"`
+	DriftedFeatures []string       `gorm:"type:jsonb;serializer:json;not null;default:'[]'" json:"driftedFeatures"`
+	Features        []DriftFeature `gorm:"type:jsonb;serializer:json;not null;default:'[]'" json:"features"`
+	CreatedAt       time.Time      `gorm:"index" json:"createdAt"`
+}
+
+func (DriftCheck) TableName() string {
+	return "drift_checks"
+}
@@ -0,0 +1,20 @@
+package entity
+
+import "time"
+
+// EncryptionKey adalah data key enkripsi kolom sensitif yang disimpan terbungkus
+// (wrapped) oleh master key. Paling banyak satu key aktif untuk enkripsi baru;
+// key lain tetap disimpan untuk mendekripsi data lama.
+type EncryptionKey struct {
+	ID          string    `gorm:"type:varchar(50);primaryKey" json:"id"`
+	Version     int       `gorm:"not null" json:"version"`
+	MasterKeyID string    `gorm:"type:varchar(50);not null" json:"masterKeyId"`
+	WrappedKey  []byte    `gorm:"type:bytea;not null" json:"-"`
+	Active      bool      `gorm:"not null;default:false;uniqueIndex:idx_encryption_keys_active,where:active" json:"active"`
+	CreatedAt   time.Time `json:"createdAt"`
+	UpdatedAt   time.Time `json:"updatedAt"`
+}
+
+func (EncryptionKey) TableName() string {
+	return "encryption_keys"
+}
@@ -0,0 +1,30 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Endpoint yang mendukung header Idempotency-Key
+const (
+	IdempotencyEndpointCreateDiagnosis = "diagnosis.create"
+)
+
+// IdempotencyKey menyimpan hasil request yang dikirim dengan header Idempotency-Key,
+// sehingga retry dengan key dan body yang sama mendapat response asli tanpa diproses ulang.
+// ResponseBody nil berarti request pertama masih diproses.
+type IdempotencyKey struct {
+	ID           uuid.UUID `gorm:"type:uuid;default:gen_random_uuid();primaryKey" json:"id"`
+	UserID       uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_idempotency_keys_user_endpoint_key" json:"userId"`
+	Endpoint     string    `gorm:"type:varchar(50);not null;uniqueIndex:idx_idempotency_keys_user_endpoint_key" json:"endpoint"`
+	Key          string    `gorm:"type:varchar(255);not null;uniqueIndex:idx_idempotency_keys_user_endpoint_key" json:"key"`
+	RequestHash  string    `gorm:"type:char(64);not null" json:"requestHash"` // sha256 hex body request
+	ResponseBody *string   `gorm:"type:jsonb" json:"responseBody"`
+	CreatedAt    time.Time `json:"createdAt"`
+	ExpiresAt    time.Time `gorm:"not null;index" json:"expiresAt"`
+}
+
+func (IdempotencyKey) TableName() string {
+	return "idempotency_keys"
+}
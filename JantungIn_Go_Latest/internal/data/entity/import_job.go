@@ -0,0 +1,55 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	ImportJobStatusQueued     = "queued"
+	ImportJobStatusProcessing = "processing"
+	ImportJobStatusCompleted  = "completed"
+	ImportJobStatusFailed     = "failed"
+
+	ImportRowStatusSuccess = "success"
+	ImportRowStatusFailed  = "failed"
+)
+
+// ImportJob adalah satu upload file CSV/XLSX berisi banyak diagnosis
+type ImportJob struct {
+	ID            uuid.UUID  `gorm:"type:uuid;default:gen_random_uuid();primaryKey" json:"id"`
+	CreatedBy     uuid.UUID  `gorm:"type:uuid;not null;index" json:"createdBy"`
+	FileName      string     `gorm:"type:varchar(255);not null" json:"fileName"`
+	Format        string     `gorm:"type:varchar(10);not null" json:"format"`
+	Status        string     `gorm:"type:varchar(20);not null;default:queued;index" json:"status"`
+	TotalRows     int        `gorm:"not null;default:0" json:"totalRows"`
+	ProcessedRows int        `gorm:"not null;default:0" json:"processedRows"`
+	SucceededRows int        `gorm:"not null;default:0" json:"succeededRows"`
+	FailedRows    int        `gorm:"not null;default:0" json:"failedRows"`
+	Error         string     `gorm:"type:text;not null;default:''" json:"error"`
+	StartedAt     *time.Time `json:"startedAt"`
+	FinishedAt    *time.Time `json:"finishedAt"`
+	CreatedAt     time.Time  `json:"createdAt"`
+	UpdatedAt     time.Time  `json:"updatedAt"`
+}
+
+func (ImportJob) TableName() string {
+	return "import_jobs"
+}
+
+// ImportJobRow adalah hasil pemrosesan satu baris file import
+type ImportJobRow struct {
+	ID          uuid.UUID  `gorm:"type:uuid;default:gen_random_uuid();primaryKey" json:"id"`
+	JobID       uuid.UUID  `gorm:"type:uuid;not null;uniqueIndex:idx_import_job_rows_row" json:"jobId"`
+	RowNumber   int        `gorm:"not null;uniqueIndex:idx_import_job_rows_row" json:"rowNumber"` // nomor baris di file (header = 1)
+	Status      string     `gorm:"type:varchar(20);not null" json:"status"`
+	PatientID   *uuid.UUID `gorm:"type:uuid" json:"patientId"`
+	DiagnosisID *uuid.UUID `gorm:"type:uuid" json:"diagnosisId"`
+	Errors      []string   `gorm:"type:jsonb;serializer:json;not null;default:'[]'" json:"errors"`
+	CreatedAt   time.Time  `json:"createdAt"`
+}
+
+func (ImportJobRow) TableName() string {
+	return "import_job_rows"
+}
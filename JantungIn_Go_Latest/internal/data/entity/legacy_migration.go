@@ -0,0 +1,35 @@
+package entity
+
+import "time"
+
+// Tabel sumber di database API lama yang dimigrasikan
+const (
+	LegacySourceUsers     = "users"
+	LegacySourceDiagnoses = "diagnoses"
+)
+
+// LegacyMigrationCheckpoint menyimpan ID legacy terakhir yang sudah diproses per tabel
+// sumber, sehingga migrate-legacy yang terhenti bisa dilanjutkan
+type LegacyMigrationCheckpoint struct {
+	Source    string    `gorm:"type:varchar(50);primaryKey" json:"source"`
+	LastID    string    `gorm:"type:varchar(36);not null" json:"lastId"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+func (LegacyMigrationCheckpoint) TableName() string {
+	return "legacy_migration_checkpoints"
+}
+
+// LegacyMigrationIssue mencatat baris legacy yang dilewati (Skipped) atau dimigrasikan
+// dengan catatan, untuk laporan rekonsiliasi. Satu baris per (source, legacy_id).
+type LegacyMigrationIssue struct {
+	Source    string    `gorm:"type:varchar(50);primaryKey" json:"source"`
+	LegacyID  string    `gorm:"type:varchar(36);primaryKey" json:"legacyId"`
+	Skipped   bool      `gorm:"not null;default:false" json:"skipped"`
+	Reason    string    `gorm:"type:text;not null" json:"reason"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+func (LegacyMigrationIssue) TableName() string {
+	return "legacy_migration_issues"
+}
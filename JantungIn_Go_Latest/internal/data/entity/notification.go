@@ -0,0 +1,64 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Jenis aturan alert diagnosis
+const (
+	// AlertRuleRiskThreshold terpicu jika resultPercentage >= Threshold
+	AlertRuleRiskThreshold = "risk_threshold"
+	// AlertRuleCategoryJump terpicu jika kategori risiko naik menjadi High Risk
+	// dibanding diagnosis pasien sebelumnya
+	AlertRuleCategoryJump = "category_jump"
+)
+
+// Status pengiriman email notifikasi
+const (
+	NotificationEmailPending = "pending"
+	NotificationEmailSent    = "sent"
+	NotificationEmailFailed  = "failed"
+	NotificationEmailSkipped = "skipped" // rule tanpa email, SMTP nonaktif, atau penerima tanpa email
+)
+
+// AlertRule menentukan kapan diagnosis baru memicu notifikasi ke dokter
+type AlertRule struct {
+	ID           uuid.UUID `gorm:"type:uuid;default:gen_random_uuid();primaryKey" json:"id"`
+	Name         string    `gorm:"type:varchar(100);not null" json:"name"`
+	Type         string    `gorm:"type:varchar(30);not null" json:"type"`
+	Threshold    *float64  `json:"threshold"` // persen 0-100, hanya untuk risk_threshold
+	Enabled      bool      `gorm:"not null;default:true" json:"enabled"`
+	EmailEnabled bool      `gorm:"not null;default:true" json:"emailEnabled"`
+	CreatedAt    time.Time `json:"createdAt"`
+	UpdatedAt    time.Time `json:"updatedAt"`
+}
+
+func (AlertRule) TableName() string {
+	return "alert_rules"
+}
+
+// Notification adalah alert in-app untuk satu dokter atas satu diagnosis dan satu rule.
+// Satu diagnosis hanya menghasilkan satu notifikasi per rule per penerima.
+type Notification struct {
+	ID                  uuid.UUID  `gorm:"type:uuid;default:gen_random_uuid();primaryKey" json:"id"`
+	RecipientID         uuid.UUID  `gorm:"type:uuid;not null;index;uniqueIndex:idx_notifications_diagnosis_rule_recipient" json:"recipientId"`
+	DiagnosisID         uuid.UUID  `gorm:"type:uuid;not null;uniqueIndex:idx_notifications_diagnosis_rule_recipient" json:"diagnosisId"`
+	PatientID           uuid.UUID  `gorm:"type:uuid;not null" json:"patientId"`
+	RuleID              uuid.UUID  `gorm:"type:uuid;not null;uniqueIndex:idx_notifications_diagnosis_rule_recipient" json:"ruleId"`
+	Type                string     `gorm:"type:varchar(30);not null" json:"type"`
+	Title               string     `gorm:"type:varchar(255);not null" json:"title"`
+	Message             string     `gorm:"type:text;not null" json:"message"`
+	ResultPercentage    float64    `gorm:"not null" json:"resultPercentage"`
+	CardiovascularRisk  string     `gorm:"type:varchar(255);not null" json:"cardiovascularRisk"`
+	EmailStatus         string     `gorm:"type:varchar(20);not null;default:pending" json:"emailStatus"`
+	EmailError          string     `gorm:"type:text;not null;default:''" json:"emailError"`
+	AcknowledgedAt      *time.Time `gorm:"index" json:"acknowledgedAt"`
+	AcknowledgementNote string     `gorm:"type:text;not null;default:''" json:"acknowledgementNote"`
+	CreatedAt           time.Time  `json:"createdAt"`
+}
+
+func (Notification) TableName() string {
+	return "notifications"
+}
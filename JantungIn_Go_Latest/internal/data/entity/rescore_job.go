@@ -0,0 +1,63 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	RescoreJobStatusQueued     = "queued"
+	RescoreJobStatusProcessing = "processing"
+	RescoreJobStatusCompleted  = "completed"
+	RescoreJobStatusFailed     = "failed"
+)
+
+// RescoreJob menjalankan ulang model ML saat ini atas input semua diagnosis historis.
+// Hasilnya disimpan di diagnosis_scores, nilai asli di diagnoses tidak diubah.
+type RescoreJob struct {
+	ID                 uuid.UUID  `gorm:"type:uuid;default:gen_random_uuid();primaryKey" json:"id"`
+	CreatedBy          uuid.UUID  `gorm:"type:uuid;not null;index" json:"createdBy"`
+	ModelVersion       string     `gorm:"type:varchar(50);not null" json:"modelVersion"`
+	Status             string     `gorm:"type:varchar(20);not null;default:queued;index" json:"status"`
+	TotalDiagnoses     int        `gorm:"not null;default:0" json:"totalDiagnoses"`
+	ProcessedDiagnoses int        `gorm:"not null;default:0" json:"processedDiagnoses"`
+	FailedDiagnoses    int        `gorm:"not null;default:0" json:"failedDiagnoses"`
+	ChangedDiagnoses   int        `gorm:"not null;default:0" json:"changedDiagnoses"` // kategori risiko berubah
+	Error              string     `gorm:"type:text;not null;default:''" json:"error"`
+	StartedAt          *time.Time `json:"startedAt"`
+	FinishedAt         *time.Time `json:"finishedAt"`
+	CreatedAt          time.Time  `json:"createdAt"`
+	UpdatedAt          time.Time  `json:"updatedAt"`
+}
+
+func (RescoreJob) TableName() string {
+	return "rescore_jobs"
+}
+
+// IsActive melaporkan apakah job masih menunggu atau sedang diproses
+func (j RescoreJob) IsActive() bool {
+	return j.Status == RescoreJobStatusQueued || j.Status == RescoreJobStatusProcessing
+}
+
+// DiagnosisScore adalah hasil prediksi ulang satu diagnosis oleh satu rescore job,
+// disimpan berdampingan dengan nilai asli saat job dijalankan untuk perbandingan.
+type DiagnosisScore struct {
+	ID                         uuid.UUID `gorm:"type:uuid;default:gen_random_uuid();primaryKey" json:"id"`
+	JobID                      uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_diagnosis_scores_job_diagnosis" json:"jobId"`
+	DiagnosisID                uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_diagnosis_scores_job_diagnosis;index" json:"diagnosisId"`
+	UserID                     uuid.UUID `gorm:"type:uuid;not null" json:"userId"`
+	ModelVersion               string    `gorm:"type:varchar(50);not null" json:"modelVersion"`
+	ResultPercentage           float64   `gorm:"not null" json:"resultPercentage"`
+	CardiovascularRisk         string    `gorm:"type:varchar(255);not null" json:"cardiovascularRisk"`
+	Prediction                 string    `gorm:"type:varchar(255);not null" json:"prediction"`
+	PreviousModelVersion       string    `gorm:"type:varchar(50);not null;default:''" json:"previousModelVersion"`
+	PreviousResultPercentage   float64   `gorm:"not null" json:"previousResultPercentage"`
+	PreviousCardiovascularRisk string    `gorm:"type:varchar(255);not null" json:"previousCardiovascularRisk"`
+	CategoryChanged            bool      `gorm:"not null;default:false;index" json:"categoryChanged"`
+	CreatedAt                  time.Time `json:"createdAt"`
+}
+
+func (DiagnosisScore) TableName() string {
+	return "diagnosis_scores"
+}
@@ -0,0 +1,28 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ShadowPrediction adalah hasil model kandidat (shadow) untuk satu diagnosis.
+// Tidak pernah ditampilkan ke pasien; hanya untuk membandingkan dengan model utama.
+// Hasil model utama disalin saat prediksi agar perbandingan tidak terpengaruh koreksi diagnosis.
+type ShadowPrediction struct {
+	ID                        uuid.UUID `gorm:"type:uuid;default:gen_random_uuid();primaryKey" json:"id"`
+	DiagnosisID               uuid.UUID `gorm:"type:uuid;not null;uniqueIndex" json:"diagnosisId"`
+	ModelVersion              string    `gorm:"type:varchar(50);not null;index" json:"modelVersion"`
+	ResultPercentage          float64   `gorm:"not null" json:"resultPercentage"`
+	CardiovascularRisk        string    `gorm:"type:varchar(255);not null" json:"cardiovascularRisk"`
+	Prediction                string    `gorm:"type:varchar(255);not null" json:"prediction"`
+	PrimaryModelVersion       string    `gorm:"type:varchar(50);not null;default:''" json:"primaryModelVersion"`
+	PrimaryResultPercentage   float64   `gorm:"not null" json:"primaryResultPercentage"`
+	PrimaryCardiovascularRisk string    `gorm:"type:varchar(255);not null" json:"primaryCardiovascularRisk"`
+	LatencyMs                 int64     `gorm:"not null;default:0" json:"latencyMs"`
+	CreatedAt                 time.Time `json:"createdAt"`
+}
+
+func (ShadowPrediction) TableName() string {
+	return "shadow_predictions"
+}
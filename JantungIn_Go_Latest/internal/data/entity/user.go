@@ -3,14 +3,20 @@ package entity
 import (
 	"time"
 
+	"jantungin-api-server/pkg/fieldcrypt"
+
 	"github.com/google/uuid"
+	"gorm.io/gorm"
 )
 
 type User struct {
 	ID          uuid.UUID  `gorm:"type:uuid;default:gen_random_uuid();primaryKey" json:"id"`
 	Name        string     `gorm:"not null" json:"name"`
 	Username    *string    `gorm:"unique;not null" json:"username"`
-	Email       *string    `gorm:"unique" json:"email"`
+	Email       *string    `gorm:"type:text;serializer:encrypted" json:"email"`        // terenkripsi, cari lewat EmailIndex
+	EmailIndex  *string    `gorm:"type:char(64);uniqueIndex" json:"-"`                 // blind index email, lihat fieldcrypt.BlindIndex
+	NIK         *string    `gorm:"column:nik;type:text;serializer:encrypted" json:"-"` // NIK 16 digit, terenkripsi
+	NIKIndex    *string    `gorm:"column:nik_index;type:char(64);uniqueIndex:idx_users_nik_index" json:"-"`
 	Password    string     `gorm:"not null" json:"-"`
 	DateOfBirth *time.Time `gorm:"type:date" json:"dateOfBirth"`
 	Role        string     `gorm:"type:user_role;default:'user'" json:"role"`
@@ -22,3 +28,10 @@ type User struct {
 	CreatedDiagnoses []Diagnosis  `gorm:"foreignKey:CreatedBy" json:"createdDiagnoses,omitempty"`
 	UserDevices      []UserDevice `gorm:"foreignKey:UserID" json:"userDevices,omitempty"`
 }
+
+// BeforeSave menghitung ulang blind index email dan NIK setiap kali user disimpan
+func (u *User) BeforeSave(tx *gorm.DB) error {
+	u.EmailIndex = fieldcrypt.BlindIndex(u.Email)
+	u.NIKIndex = fieldcrypt.BlindIndex(u.NIK)
+	return nil
+}
@@ -0,0 +1,531 @@
+// Package legacy memigrasikan user dan diagnosis dari database API Node/Sequelize lama
+// (tabel "Users" dan "Diagnoses") ke skema Go.
+//
+// ID dan timestamp dipertahankan, hash password bcrypt dipakai apa adanya, dan NIK
+// didekripsi dari format lama lalu disimpan ulang lewat fieldcrypt. Baris diproses
+// berurutan menurut ID dengan checkpoint per batch, sehingga migrasi yang terhenti bisa
+// dilanjutkan. Insert memakai ON CONFLICT DO NOTHING, jadi menjalankan ulang dari awal
+// (Options.Restart) aman dan mengambil baris legacy baru yang ID-nya lebih kecil dari
+// checkpoint.
+package legacy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"jantungin-api-server/internal/data/entity"
+	"jantungin-api-server/internal/identity"
+	"jantungin-api-server/internal/vocabulary"
+	"jantungin-api-server/pkg/utils"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/logger"
+)
+
+const (
+	batchSize = 200
+
+	// Nama tabel default Sequelize (model di-pluralize, case-sensitive)
+	usersTable     = `"Users"`
+	diagnosesTable = `"Diagnoses"`
+
+	// ModelVersion untuk diagnosis hasil migrasi; versi model API lama tidak tercatat
+	legacyModelVersion = "legacy"
+)
+
+var nonAlphaNum = regexp.MustCompile(`[^a-z0-9]+`)
+
+type Options struct {
+	EncryptionKey string // ENCRYPTION_KEY API lama, untuk nik_encrypted
+	Restart       bool   // abaikan checkpoint dan proses ulang semua baris
+}
+
+type Migrator struct {
+	source *gorm.DB
+	target *gorm.DB
+	opts   Options
+}
+
+// Open membuka koneksi ke database API lama dari DATABASE_URL-nya
+func Open(databaseURL string) (*gorm.DB, error) {
+	if databaseURL == "" {
+		return nil, errors.New("LEGACY_DATABASE_URL belum diisi")
+	}
+
+	db, err := gorm.Open(postgres.Open(databaseURL), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Warn),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to legacy database: %w", err)
+	}
+	return db, nil
+}
+
+func NewMigrator(source *gorm.DB, target *gorm.DB, opts Options) *Migrator {
+	return &Migrator{
+		source: source,
+		target: target,
+		opts:   opts,
+	}
+}
+
+type legacyUser struct {
+	ID           string     `gorm:"column:id"`
+	Name         string     `gorm:"column:name"`
+	Email        *string    `gorm:"column:email"`
+	NIKEncrypted *string    `gorm:"column:nik_encrypted"`
+	Password     string     `gorm:"column:password"`
+	DateOfBirth  *time.Time `gorm:"column:dateOfBirth"`
+	Role         *string    `gorm:"column:role"`
+	CreatedAt    time.Time  `gorm:"column:createdAt"`
+	UpdatedAt    time.Time  `gorm:"column:updatedAt"`
+}
+
+type legacyDiagnosis struct {
+	ID                    string    `gorm:"column:id"`
+	UserID                string    `gorm:"column:userId"`
+	CreatedBy             *string   `gorm:"column:createdBy"`
+	Age                   int       `gorm:"column:age"`
+	Sex                   string    `gorm:"column:sex"`
+	ChestPainType         string    `gorm:"column:chestPainType"`
+	RestingEcgResults     string    `gorm:"column:restingEcgResults"`
+	FastingBloodSugar     float64   `gorm:"column:fastingBloodSugar"`
+	RestingBloodPressure  float64   `gorm:"column:restingBloodPressure"`
+	MaximumHeartRate      int       `gorm:"column:maximumHeartRate"`
+	ExerciseInducedAngina string    `gorm:"column:exerciseInducedAngina"`
+	StSegment             string    `gorm:"column:stSegment"`
+	MajorVessels          int       `gorm:"column:majorVessels"`
+	Thalassemia           string    `gorm:"column:thalassemia"`
+	SerumCholesterol      float64   `gorm:"column:serumCholesterol"`
+	StDepression          float64   `gorm:"column:stDepression"`
+	ResultPercentage      float64   `gorm:"column:resultPercentage"`
+	CardiovascularRisk    string    `gorm:"column:cardiovascularRisk"`
+	Prediction            string    `gorm:"column:prediction"`
+	CreatedAt             time.Time `gorm:"column:createdAt"`
+	UpdatedAt             time.Time `gorm:"column:updatedAt"`
+}
+
+type outcome int
+
+const (
+	outcomeMigrated outcome = iota
+	outcomeExisting
+	outcomeSkipped
+)
+
+// rowResult: reason berisi alasan dilewati (outcomeSkipped) atau catatan migrasi
+type rowResult struct {
+	outcome outcome
+	reason  string
+}
+
+// Run memigrasikan user lalu diagnosis, kemudian menyusun laporan rekonsiliasi
+func (m *Migrator) Run(ctx context.Context) (*Report, error) {
+	report := &Report{StartedAt: time.Now()}
+
+	if m.opts.Restart {
+		if err := m.target.WithContext(ctx).Where("1 = 1").Delete(&entity.LegacyMigrationCheckpoint{}).Error; err != nil {
+			return nil, err
+		}
+	}
+
+	users := TableReport{Source: entity.LegacySourceUsers}
+	if err := migrateTable(ctx, m, usersTable, &users, func(row legacyUser) string { return row.ID }, m.migrateUser); err != nil {
+		return nil, err
+	}
+
+	// Cache ID user yang ada di target, dipakai untuk cek pasien/pembuat diagnosis
+	knownUsers := make(map[uuid.UUID]bool)
+	diagnoses := TableReport{Source: entity.LegacySourceDiagnoses}
+	migrateDiagnosis := func(ctx context.Context, row legacyDiagnosis) (rowResult, error) {
+		return m.migrateDiagnosis(ctx, row, knownUsers)
+	}
+	if err := migrateTable(ctx, m, diagnosesTable, &diagnoses, func(row legacyDiagnosis) string { return row.ID }, migrateDiagnosis); err != nil {
+		return nil, err
+	}
+
+	if err := m.reconcile(ctx, usersTable, &entity.User{}, &users); err != nil {
+		return nil, err
+	}
+	if err := m.reconcile(ctx, diagnosesTable, &entity.Diagnosis{}, &diagnoses); err != nil {
+		return nil, err
+	}
+
+	report.Tables = []TableReport{users, diagnoses}
+	report.FinishedAt = time.Now()
+	return report, nil
+}
+
+// migrateTable membaca tabel legacy per batch urut ID mulai dari checkpoint,
+// memproses setiap baris, lalu menyimpan checkpoint setelah batch selesai
+func migrateTable[T any](ctx context.Context, m *Migrator, table string, stats *TableReport, rowID func(T) string, migrate func(context.Context, T) (rowResult, error)) error {
+	var checkpoint entity.LegacyMigrationCheckpoint
+	err := m.target.WithContext(ctx).Where("source = ?", stats.Source).First(&checkpoint).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+	if checkpoint.LastID != "" {
+		utils.Info("Resuming legacy migration", zap.String("source", stats.Source), zap.String("after_id", checkpoint.LastID))
+	}
+
+	lastID := checkpoint.LastID
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		query := m.source.WithContext(ctx).Table(table).Order("id ASC").Limit(batchSize)
+		if lastID != "" {
+			query = query.Where("id > ?", lastID)
+		}
+		var rows []T
+		if err := query.Find(&rows).Error; err != nil {
+			return fmt.Errorf("read legacy %s: %w", stats.Source, err)
+		}
+		if len(rows) == 0 {
+			return nil
+		}
+
+		for _, row := range rows {
+			id := rowID(row)
+			result, err := migrate(ctx, row)
+			if err != nil {
+				return fmt.Errorf("migrate legacy %s %s: %w", stats.Source, id, err)
+			}
+			if err := m.recordResult(ctx, stats, id, result); err != nil {
+				return err
+			}
+		}
+
+		lastID = rowID(rows[len(rows)-1])
+		err := m.target.WithContext(ctx).
+			Clauses(clause.OnConflict{UpdateAll: true}).
+			Create(&entity.LegacyMigrationCheckpoint{Source: stats.Source, LastID: lastID}).Error
+		if err != nil {
+			return err
+		}
+
+		utils.Info("Legacy migration batch completed",
+			zap.String("source", stats.Source),
+			zap.Int("processed", stats.Processed),
+			zap.String("last_id", lastID),
+		)
+	}
+}
+
+// recordResult memperbarui statistik run dan tabel legacy_migration_issues.
+// Catatan lama dihapus jika baris kini berhasil dimigrasikan tanpa catatan.
+func (m *Migrator) recordResult(ctx context.Context, stats *TableReport, legacyID string, result rowResult) error {
+	stats.Processed++
+	switch result.outcome {
+	case outcomeMigrated:
+		stats.Migrated++
+	case outcomeExisting:
+		stats.AlreadyPresent++
+		return nil
+	case outcomeSkipped:
+		stats.Skipped++
+		utils.Warn("Legacy row skipped",
+			zap.String("source", stats.Source),
+			zap.String("legacy_id", legacyID),
+			zap.String("reason", result.reason),
+		)
+	}
+
+	db := m.target.WithContext(ctx)
+	if result.reason == "" {
+		return db.Where("source = ? AND legacy_id = ?", stats.Source, legacyID).
+			Delete(&entity.LegacyMigrationIssue{}).Error
+	}
+	return db.Clauses(clause.OnConflict{UpdateAll: true}).
+		Create(&entity.LegacyMigrationIssue{
+			Source:   stats.Source,
+			LegacyID: legacyID,
+			Skipped:  result.outcome == outcomeSkipped,
+			Reason:   result.reason,
+		}).Error
+}
+
+func (m *Migrator) migrateUser(ctx context.Context, row legacyUser) (rowResult, error) {
+	id, err := uuid.Parse(row.ID)
+	if err != nil {
+		return rowResult{outcomeSkipped, "ID bukan UUID"}, nil
+	}
+
+	var notes []string
+
+	role := "user"
+	if row.Role != nil {
+		switch *row.Role {
+		case "user", "admin", "dokter":
+			role = *row.Role
+		default:
+			notes = append(notes, fmt.Sprintf("role %q tidak dikenal, dimigrasikan sebagai user", *row.Role))
+		}
+	}
+
+	name := strings.TrimSpace(row.Name)
+	username := legacyUsername(name, id)
+	user := entity.User{
+		ID:          id,
+		Name:        name,
+		Username:    &username,
+		Password:    row.Password,
+		DateOfBirth: row.DateOfBirth,
+		Role:        role,
+		CreatedAt:   row.CreatedAt,
+		UpdatedAt:   row.UpdatedAt,
+	}
+	if row.Email != nil {
+		if email := strings.ToLower(strings.TrimSpace(*row.Email)); email != "" {
+			user.Email = &email
+		}
+	}
+
+	if row.NIKEncrypted != nil && *row.NIKEncrypted != "" {
+		nik, note := m.decryptNIK(*row.NIKEncrypted)
+		if nik != "" {
+			user.NIK = &nik
+		}
+		if note != "" {
+			notes = append(notes, note)
+		}
+	}
+
+	result := m.target.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(&user)
+	if result.Error != nil {
+		return rowResult{}, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return m.conflictResult(ctx, &entity.User{}, id, "bentrok dengan user yang sudah ada (email, NIK, atau username sama)")
+	}
+
+	return rowResult{outcomeMigrated, strings.Join(notes, "; ")}, nil
+}
+
+// decryptNIK mengembalikan NIK ternormalisasi (kosong jika tidak bisa dipakai) beserta catatan.
+// NIK 16 digit yang tidak lolos validasi struktur tetap dimigrasikan agar data tidak hilang.
+func (m *Migrator) decryptNIK(encrypted string) (string, string) {
+	plaintext, err := DecryptNIK(encrypted, m.opts.EncryptionKey)
+	if err != nil {
+		return "", "NIK tidak dimigrasikan: " + err.Error()
+	}
+
+	nik := identity.NormalizeNIK(plaintext)
+	if !identity.LooksLikeNIK(nik) {
+		return "", "NIK tidak dimigrasikan: " + identity.ErrNIKFormat.Error()
+	}
+	if _, err := identity.ParseNIK(nik, time.Now()); err != nil {
+		return nik, "NIK dimigrasikan tetapi tidak valid: " + err.Error()
+	}
+	return nik, ""
+}
+
+func (m *Migrator) migrateDiagnosis(ctx context.Context, row legacyDiagnosis, knownUsers map[uuid.UUID]bool) (rowResult, error) {
+	id, err := uuid.Parse(row.ID)
+	if err != nil {
+		return rowResult{outcomeSkipped, "ID bukan UUID"}, nil
+	}
+	patientID, err := uuid.Parse(row.UserID)
+	if err != nil {
+		return rowResult{outcomeSkipped, "userId bukan UUID"}, nil
+	}
+
+	var notes []string
+
+	exists, err := m.userExists(ctx, patientID, knownUsers)
+	if err != nil {
+		return rowResult{}, err
+	}
+	if !exists {
+		return rowResult{outcomeSkipped, "pasien " + row.UserID + " tidak ada di database tujuan"}, nil
+	}
+
+	var createdBy *uuid.UUID
+	if row.CreatedBy != nil {
+		if creatorID, err := uuid.Parse(*row.CreatedBy); err == nil {
+			exists, err := m.userExists(ctx, creatorID, knownUsers)
+			if err != nil {
+				return rowResult{}, err
+			}
+			if exists {
+				createdBy = &creatorID
+			} else {
+				notes = append(notes, "pembuat "+*row.CreatedBy+" tidak ada di database tujuan, createdBy dikosongkan")
+			}
+		}
+	}
+
+	categorical := []struct {
+		field *vocabulary.Field
+		value *string
+	}{
+		{vocabulary.SexField, &row.Sex},
+		{vocabulary.ChestPainTypeField, &row.ChestPainType},
+		{vocabulary.RestingEcgField, &row.RestingEcgResults},
+		{vocabulary.ExerciseAnginaField, &row.ExerciseInducedAngina},
+		{vocabulary.StSlopeField, &row.StSegment},
+		{vocabulary.ThalassemiaField, &row.Thalassemia},
+	}
+	for _, c := range categorical {
+		if canonical, ok := c.field.Lookup(*c.value); ok {
+			*c.value = canonical
+		} else {
+			notes = append(notes, fmt.Sprintf("%s %q tidak dikenali, disimpan apa adanya", c.field.Name, *c.value))
+		}
+	}
+
+	prediction := row.Prediction
+	if prediction == "" {
+		prediction = "Berisiko"
+	}
+
+	diagnosis := entity.Diagnosis{
+		ID:                    id,
+		UserID:                patientID,
+		CreatedBy:             createdBy,
+		Age:                   row.Age,
+		Sex:                   row.Sex,
+		ChestPainType:         row.ChestPainType,
+		RestingEcgResults:     row.RestingEcgResults,
+		FastingBloodSugar:     row.FastingBloodSugar,
+		RestingBloodPressure:  row.RestingBloodPressure,
+		MaximumHeartRate:      row.MaximumHeartRate,
+		ExerciseInducedAngina: row.ExerciseInducedAngina,
+		StSegment:             row.StSegment,
+		MajorVessels:          row.MajorVessels,
+		Thalassemia:           row.Thalassemia,
+		SerumCholesterol:      row.SerumCholesterol,
+		StDepression:          row.StDepression,
+		ResultPercentage:      row.ResultPercentage,
+		CardiovascularRisk:    row.CardiovascularRisk,
+		Prediction:            prediction,
+		ModelVersion:          legacyModelVersion,
+		Status:                entity.DiagnosisStatusPendingReview, // sama dengan backfill diagnosis historis di migrasi 000008
+		CreatedAt:             row.CreatedAt,
+		UpdatedAt:             row.UpdatedAt,
+	}
+
+	result := m.target.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(&diagnosis)
+	if result.Error != nil {
+		return rowResult{}, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return m.conflictResult(ctx, &entity.Diagnosis{}, id, "bentrok dengan diagnosis yang sudah ada")
+	}
+
+	return rowResult{outcomeMigrated, strings.Join(notes, "; ")}, nil
+}
+
+// conflictResult membedakan baris yang sudah dimigrasikan sebelumnya (ID sama)
+// dari baris yang bentrok dengan data lain di target
+func (m *Migrator) conflictResult(ctx context.Context, model any, id uuid.UUID, reason string) (rowResult, error) {
+	var count int64
+	if err := m.target.WithContext(ctx).Unscoped().Model(model).Where("id = ?", id).Count(&count).Error; err != nil {
+		return rowResult{}, err
+	}
+	if count > 0 {
+		return rowResult{outcome: outcomeExisting}, nil
+	}
+	return rowResult{outcomeSkipped, reason}, nil
+}
+
+func (m *Migrator) userExists(ctx context.Context, id uuid.UUID, known map[uuid.UUID]bool) (bool, error) {
+	if exists, ok := known[id]; ok {
+		return exists, nil
+	}
+
+	var count int64
+	if err := m.target.WithContext(ctx).Model(&entity.User{}).Where("id = ?", id).Count(&count).Error; err != nil {
+		return false, err
+	}
+	known[id] = count > 0
+	return count > 0, nil
+}
+
+// reconcile menghitung baris legacy, berapa yang ada di target (berdasarkan ID), dan
+// daftar yang belum ada beserta alasannya dari legacy_migration_issues
+func (m *Migrator) reconcile(ctx context.Context, table string, model any, stats *TableReport) error {
+	var issues []entity.LegacyMigrationIssue
+	if err := m.target.WithContext(ctx).Where("source = ?", stats.Source).Order("legacy_id ASC").Find(&issues).Error; err != nil {
+		return err
+	}
+	reasons := make(map[string]string, len(issues))
+	for _, issue := range issues {
+		reasons[issue.LegacyID] = issue.Reason
+	}
+
+	stats.Missing = []Issue{}
+	stats.Warnings = []Issue{}
+	present := make(map[string]bool)
+
+	lastID := ""
+	for {
+		query := m.source.WithContext(ctx).Table(table).Order("id ASC").Limit(batchSize)
+		if lastID != "" {
+			query = query.Where("id > ?", lastID)
+		}
+		var ids []string
+		if err := query.Pluck("id", &ids).Error; err != nil {
+			return fmt.Errorf("read legacy %s: %w", stats.Source, err)
+		}
+		if len(ids) == 0 {
+			break
+		}
+		stats.LegacyCount += int64(len(ids))
+
+		var found []uuid.UUID
+		err := m.target.WithContext(ctx).Unscoped().Model(model).
+			Where("id IN ?", ids).
+			Pluck("id", &found).Error
+		if err != nil {
+			return err
+		}
+		for _, id := range found {
+			present[id.String()] = true
+		}
+		stats.PresentInTarget += int64(len(found))
+
+		for _, id := range ids {
+			if present[id] {
+				continue
+			}
+			reason, ok := reasons[id]
+			if !ok {
+				reason = "belum diproses, jalankan ulang dengan restart"
+			}
+			stats.Missing = append(stats.Missing, Issue{LegacyID: id, Reason: reason})
+		}
+
+		lastID = ids[len(ids)-1]
+	}
+
+	for _, issue := range issues {
+		if present[issue.LegacyID] && !issue.Skipped {
+			stats.Warnings = append(stats.Warnings, Issue{LegacyID: issue.LegacyID, Reason: issue.Reason})
+		}
+	}
+	return nil
+}
+
+// legacyUsername membuat username deterministik dari nama dan ID legacy, misalnya
+// "budi_santoso_3fa9c1d2". User API lama login dengan NIK, yang tidak dipakai sebagai
+// username karena NIK disimpan terenkripsi.
+func legacyUsername(name string, id uuid.UUID) string {
+	base := strings.Trim(nonAlphaNum.ReplaceAllString(strings.ToLower(name), "_"), "_")
+	if len(base) > 40 {
+		base = base[:40]
+	}
+	if base == "" {
+		base = "pasien"
+	}
+	return base + "_" + strings.ReplaceAll(id.String(), "-", "")[:8]
+}
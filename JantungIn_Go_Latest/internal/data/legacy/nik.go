@@ -0,0 +1,55 @@
+package legacy
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"encoding/hex"
+	"errors"
+	"strings"
+)
+
+var errInvalidCiphertext = errors.New("format nik_encrypted tidak valid")
+
+// legacyKey meniru API lama: kunci dipotong atau di-pad dengan '0' sampai 32 karakter
+func legacyKey(key string) []byte {
+	if len(key) > 32 {
+		return []byte(key[:32])
+	}
+	return []byte(key + strings.Repeat("0", 32-len(key)))
+}
+
+// DecryptNIK membuka nik_encrypted API lama: AES-256-CBC dengan padding PKCS#7,
+// disimpan sebagai "<iv hex>:<ciphertext hex>"
+func DecryptNIK(value string, key string) (string, error) {
+	ivHex, ctHex, ok := strings.Cut(value, ":")
+	if !ok {
+		return "", errInvalidCiphertext
+	}
+	iv, err := hex.DecodeString(ivHex)
+	if err != nil || len(iv) != aes.BlockSize {
+		return "", errInvalidCiphertext
+	}
+	ciphertext, err := hex.DecodeString(ctHex)
+	if err != nil || len(ciphertext) == 0 || len(ciphertext)%aes.BlockSize != 0 {
+		return "", errInvalidCiphertext
+	}
+
+	block, err := aes.NewCipher(legacyKey(key))
+	if err != nil {
+		return "", err
+	}
+	plaintext := make([]byte, len(ciphertext))
+	cipher.NewCBCDecrypter(block, iv).CryptBlocks(plaintext, ciphertext)
+
+	// Padding yang tidak valid hampir selalu berarti LEGACY_ENCRYPTION_KEY salah
+	pad := int(plaintext[len(plaintext)-1])
+	if pad == 0 || pad > aes.BlockSize {
+		return "", errors.New("gagal mendekripsi NIK, periksa LEGACY_ENCRYPTION_KEY")
+	}
+	for _, b := range plaintext[len(plaintext)-pad:] {
+		if int(b) != pad {
+			return "", errors.New("gagal mendekripsi NIK, periksa LEGACY_ENCRYPTION_KEY")
+		}
+	}
+	return string(plaintext[:len(plaintext)-pad]), nil
+}
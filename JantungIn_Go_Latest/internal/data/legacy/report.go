@@ -0,0 +1,76 @@
+package legacy
+
+import (
+	"encoding/json"
+	"os"
+	"time"
+
+	"jantungin-api-server/pkg/utils"
+
+	"go.uber.org/zap"
+)
+
+// Report adalah laporan rekonsiliasi migrate-legacy. Angka Migrated/AlreadyPresent/Skipped
+// hanya untuk run ini; LegacyCount, PresentInTarget, dan Missing dihitung ulang dari
+// kedua database sehingga tetap akurat setelah beberapa kali resume.
+type Report struct {
+	StartedAt  time.Time     `json:"startedAt"`
+	FinishedAt time.Time     `json:"finishedAt"`
+	Tables     []TableReport `json:"tables"`
+}
+
+type TableReport struct {
+	Source string `json:"source"`
+
+	// Run ini
+	Processed      int `json:"processed"`
+	Migrated       int `json:"migrated"`
+	AlreadyPresent int `json:"alreadyPresent"`
+	Skipped        int `json:"skipped"`
+
+	// Rekonsiliasi
+	LegacyCount     int64   `json:"legacyCount"`
+	PresentInTarget int64   `json:"presentInTarget"`
+	Missing         []Issue `json:"missing"`  // ada di legacy, tidak ada di target
+	Warnings        []Issue `json:"warnings"` // sudah dimigrasikan dengan catatan
+}
+
+type Issue struct {
+	LegacyID string `json:"legacyId"`
+	Reason   string `json:"reason"`
+}
+
+// Complete melaporkan apakah semua baris legacy sudah ada di target
+func (r *Report) Complete() bool {
+	for _, t := range r.Tables {
+		if len(t.Missing) > 0 {
+			return false
+		}
+	}
+	return true
+}
+
+func (r *Report) WriteFile(path string) error {
+	content, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, content, 0o600)
+}
+
+// Log menulis ringkasan per tabel ke logger aplikasi
+func (r *Report) Log() {
+	for _, t := range r.Tables {
+		utils.Info("Legacy migration summary",
+			zap.String("source", t.Source),
+			zap.Int("processed", t.Processed),
+			zap.Int("migrated", t.Migrated),
+			zap.Int("already_present", t.AlreadyPresent),
+			zap.Int("skipped", t.Skipped),
+			zap.Int64("legacy_count", t.LegacyCount),
+			zap.Int64("present_in_target", t.PresentInTarget),
+			zap.Int("missing", len(t.Missing)),
+			zap.Int("warnings", len(t.Warnings)),
+		)
+	}
+}
@@ -0,0 +1 @@
+-- Normalisasi vokabulari tidak dapat dibalik: nilai asli sebelum normalisasi tidak disimpan.
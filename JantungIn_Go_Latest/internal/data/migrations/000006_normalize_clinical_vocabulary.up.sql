@@ -0,0 +1,63 @@
+-- Normalisasi nilai kategorikal diagnosis ke vokabulari kanonik
+-- (internal/vocabulary). Data lama dari seeder dan input bebas memakai
+-- variasi seperti 'Ya'/'Tidak', 'Laki-laki', 'Typical Angina', 'Reversable Defect'.
+-- Nilai yang tidak dikenali dibiarkan apa adanya.
+
+-- sex
+UPDATE diagnoses
+SET sex = CASE LOWER(REGEXP_REPLACE(BTRIM(sex), '\s+', ' ', 'g'))
+    WHEN 'l', 'laki laki', 'laki-laki', 'lakilaki', 'm', 'male', 'pria' THEN 'Male'
+    WHEN 'f', 'female', 'p', 'perempuan', 'wanita' THEN 'Female'
+    ELSE sex
+END
+WHERE sex NOT IN ('Male', 'Female');
+
+-- chestPainType
+UPDATE diagnoses
+SET chest_pain_type = CASE LOWER(REGEXP_REPLACE(BTRIM(chest_pain_type), '\s+', ' ', 'g'))
+    WHEN '1', 'angina tipikal', 'ta', 'typical angina', 'typical' THEN 'Typical angina'
+    WHEN '2', 'angina atipikal', 'ata', 'atypical angina', 'atypical' THEN 'Atypical angina'
+    WHEN '3', 'nap', 'non anginal pain', 'non-anginal pain', 'non-anginal', 'nyeri non angina', 'nyeri non-anginal' THEN 'Non-anginal pain'
+    WHEN '4', 'asimptomatik', 'asy', 'asymptomatic', 'tanpa gejala' THEN 'Asymptomatic'
+    ELSE chest_pain_type
+END
+WHERE chest_pain_type NOT IN ('Typical angina', 'Atypical angina', 'Non-anginal pain', 'Asymptomatic');
+
+-- restingEcgResults
+UPDATE diagnoses
+SET resting_ecg_results = CASE LOWER(REGEXP_REPLACE(BTRIM(resting_ecg_results), '\s+', ' ', 'g'))
+    WHEN '0', 'normal' THEN 'Normal'
+    WHEN '1', 'abnormalitas gelombang st-t', 'kelainan gelombang st-t', 'st', 'st-t abnormality', 'st-t wave abnormality' THEN 'ST-T wave abnormality'
+    WHEN '2', 'hipertrofi ventrikel kiri', 'left ventricular hypertrophy', 'lvh' THEN 'Left ventricular hypertrophy'
+    ELSE resting_ecg_results
+END
+WHERE resting_ecg_results NOT IN ('Normal', 'ST-T wave abnormality', 'Left ventricular hypertrophy');
+
+-- exerciseInducedAngina
+UPDATE diagnoses
+SET exercise_induced_angina = CASE LOWER(REGEXP_REPLACE(BTRIM(exercise_induced_angina), '\s+', ' ', 'g'))
+    WHEN '1', 'true', 'y', 'ya', 'yes' THEN 'Yes'
+    WHEN '0', 'false', 'n', 'no', 'tidak' THEN 'No'
+    ELSE exercise_induced_angina
+END
+WHERE exercise_induced_angina NOT IN ('Yes', 'No');
+
+-- stSegment
+UPDATE diagnoses
+SET st_segment = CASE LOWER(REGEXP_REPLACE(BTRIM(st_segment), '\s+', ' ', 'g'))
+    WHEN '1', 'menanjak', 'naik', 'up', 'upsloping' THEN 'Upsloping'
+    WHEN '2', 'datar', 'flat' THEN 'Flat'
+    WHEN '3', 'down', 'downsloping', 'menurun', 'turun' THEN 'Downsloping'
+    ELSE st_segment
+END
+WHERE st_segment NOT IN ('Upsloping', 'Flat', 'Downsloping');
+
+-- thalassemia
+UPDATE diagnoses
+SET thalassemia = CASE LOWER(REGEXP_REPLACE(BTRIM(thalassemia), '\s+', ' ', 'g'))
+    WHEN '3', 'normal' THEN 'Normal'
+    WHEN '6', 'cacat tetap', 'fixed defect', 'fixed' THEN 'Fixed defect'
+    WHEN '7', 'cacat dapat dibalik', 'reversable defect', 'reversable', 'reversible defect', 'reversible' THEN 'Reversible defect'
+    ELSE thalassemia
+END
+WHERE thalassemia NOT IN ('Normal', 'Fixed defect', 'Reversible defect');
@@ -0,0 +1,8 @@
+DROP TABLE IF EXISTS diagnosis_revisions CASCADE;
+
+DROP INDEX IF EXISTS idx_diagnoses_deleted_at;
+
+ALTER TABLE diagnoses
+DROP COLUMN IF EXISTS deleted_by,
+DROP COLUMN IF EXISTS deleted_at,
+DROP COLUMN IF EXISTS version;
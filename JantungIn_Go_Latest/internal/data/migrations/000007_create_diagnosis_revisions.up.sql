@@ -0,0 +1,37 @@
+-- Versi & soft delete untuk diagnoses
+ALTER TABLE diagnoses
+ADD COLUMN IF NOT EXISTS version INT NOT NULL DEFAULT 1,
+ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMP WITH TIME ZONE,
+ADD COLUMN IF NOT EXISTS deleted_by UUID REFERENCES users(id) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS idx_diagnoses_deleted_at ON diagnoses(deleted_at);
+
+-- Snapshot diagnosis sebelum diubah/dihapus
+CREATE TABLE diagnosis_revisions (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    diagnosis_id UUID NOT NULL REFERENCES diagnoses(id) ON DELETE CASCADE,
+    version INT NOT NULL,
+    action VARCHAR(20) NOT NULL,
+    edited_by UUID NOT NULL REFERENCES users(id),
+    reason TEXT NOT NULL DEFAULT '',
+    age INT NOT NULL,
+    sex VARCHAR(50) NOT NULL,
+    chest_pain_type VARCHAR(255) NOT NULL,
+    resting_ecg_results VARCHAR(255) NOT NULL,
+    fasting_blood_sugar FLOAT NOT NULL,
+    resting_blood_pressure FLOAT NOT NULL,
+    maximum_heart_rate INT NOT NULL,
+    exercise_induced_angina VARCHAR(255) NOT NULL,
+    st_segment VARCHAR(255) NOT NULL,
+    major_vessels INT NOT NULL,
+    thalassemia VARCHAR(255) NOT NULL,
+    serum_cholesterol FLOAT NOT NULL,
+    st_depression FLOAT NOT NULL,
+    result_percentage FLOAT NOT NULL,
+    cardiovascular_risk VARCHAR(255) NOT NULL,
+    prediction VARCHAR(255) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Satu snapshot per versi diagnosis
+CREATE UNIQUE INDEX idx_diagnosis_revisions_version ON diagnosis_revisions(diagnosis_id, version);
@@ -0,0 +1,12 @@
+ALTER TABLE diagnosis_revisions
+DROP COLUMN IF EXISTS status,
+DROP COLUMN IF EXISTS clinical_notes;
+
+DROP INDEX IF EXISTS idx_diagnoses_status;
+
+ALTER TABLE diagnoses
+DROP COLUMN IF EXISTS review_notes,
+DROP COLUMN IF EXISTS reviewed_at,
+DROP COLUMN IF EXISTS reviewed_by,
+DROP COLUMN IF EXISTS status,
+DROP COLUMN IF EXISTS clinical_notes;
@@ -0,0 +1,19 @@
+-- Status review & catatan klinis untuk diagnoses.
+-- Diagnosis lama belum pernah di-review, jadi masuk ke antrean pending_review;
+-- diagnosis baru dimulai dari draft.
+ALTER TABLE diagnoses
+ADD COLUMN IF NOT EXISTS clinical_notes TEXT NOT NULL DEFAULT '',
+ADD COLUMN IF NOT EXISTS status VARCHAR(20) NOT NULL DEFAULT 'pending_review',
+ADD COLUMN IF NOT EXISTS reviewed_by UUID REFERENCES users(id) ON DELETE SET NULL,
+ADD COLUMN IF NOT EXISTS reviewed_at TIMESTAMP WITH TIME ZONE,
+ADD COLUMN IF NOT EXISTS review_notes TEXT NOT NULL DEFAULT '';
+
+ALTER TABLE diagnoses
+ALTER COLUMN status SET DEFAULT 'draft';
+
+CREATE INDEX IF NOT EXISTS idx_diagnoses_status ON diagnoses(status);
+
+-- Snapshot revisi ikut menyimpan catatan klinis dan status saat itu
+ALTER TABLE diagnosis_revisions
+ADD COLUMN IF NOT EXISTS clinical_notes TEXT NOT NULL DEFAULT '',
+ADD COLUMN IF NOT EXISTS status VARCHAR(20) NOT NULL DEFAULT '';
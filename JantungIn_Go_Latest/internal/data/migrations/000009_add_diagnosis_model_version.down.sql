@@ -0,0 +1,5 @@
+ALTER TABLE diagnosis_revisions
+DROP COLUMN IF EXISTS model_version;
+
+ALTER TABLE diagnoses
+DROP COLUMN IF EXISTS model_version;
@@ -0,0 +1,6 @@
+-- Versi model ML yang menghasilkan prediksi (ditampilkan di laporan PDF)
+ALTER TABLE diagnoses
+ADD COLUMN IF NOT EXISTS model_version VARCHAR(50) NOT NULL DEFAULT '';
+
+ALTER TABLE diagnosis_revisions
+ADD COLUMN IF NOT EXISTS model_version VARCHAR(50) NOT NULL DEFAULT '';
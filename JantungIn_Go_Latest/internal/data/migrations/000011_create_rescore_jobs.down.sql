@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS diagnosis_scores;
+DROP TABLE IF EXISTS rescore_jobs;
@@ -0,0 +1,40 @@
+-- Job prediksi ulang diagnosis historis dengan model terbaru
+CREATE TABLE rescore_jobs (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    created_by UUID NOT NULL REFERENCES users(id),
+    model_version VARCHAR(50) NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'queued',
+    total_diagnoses INT NOT NULL DEFAULT 0,
+    processed_diagnoses INT NOT NULL DEFAULT 0,
+    failed_diagnoses INT NOT NULL DEFAULT 0,
+    changed_diagnoses INT NOT NULL DEFAULT 0,
+    error TEXT NOT NULL DEFAULT '',
+    started_at TIMESTAMP WITH TIME ZONE,
+    finished_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_rescore_jobs_created_by ON rescore_jobs(created_by);
+CREATE INDEX idx_rescore_jobs_status ON rescore_jobs(status);
+
+-- Hasil rescore per diagnosis, nilai asli di diagnoses tidak diubah
+CREATE TABLE diagnosis_scores (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    job_id UUID NOT NULL REFERENCES rescore_jobs(id) ON DELETE CASCADE,
+    diagnosis_id UUID NOT NULL REFERENCES diagnoses(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    model_version VARCHAR(50) NOT NULL,
+    result_percentage FLOAT NOT NULL,
+    cardiovascular_risk VARCHAR(255) NOT NULL,
+    prediction VARCHAR(255) NOT NULL,
+    previous_model_version VARCHAR(50) NOT NULL DEFAULT '',
+    previous_result_percentage FLOAT NOT NULL,
+    previous_cardiovascular_risk VARCHAR(255) NOT NULL,
+    category_changed BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX idx_diagnosis_scores_job_diagnosis ON diagnosis_scores(job_id, diagnosis_id);
+CREATE INDEX idx_diagnosis_scores_diagnosis_id ON diagnosis_scores(diagnosis_id);
+CREATE INDEX idx_diagnosis_scores_category_changed ON diagnosis_scores(category_changed);
@@ -0,0 +1,17 @@
+-- Hasil model kandidat (shadow) per diagnosis, untuk evaluasi sebelum dipakai
+CREATE TABLE shadow_predictions (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    diagnosis_id UUID NOT NULL REFERENCES diagnoses(id) ON DELETE CASCADE,
+    model_version VARCHAR(50) NOT NULL,
+    result_percentage FLOAT NOT NULL,
+    cardiovascular_risk VARCHAR(255) NOT NULL,
+    prediction VARCHAR(255) NOT NULL,
+    primary_model_version VARCHAR(50) NOT NULL DEFAULT '',
+    primary_result_percentage FLOAT NOT NULL,
+    primary_cardiovascular_risk VARCHAR(255) NOT NULL,
+    latency_ms BIGINT NOT NULL DEFAULT 0,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX idx_shadow_predictions_diagnosis_id ON shadow_predictions(diagnosis_id);
+CREATE INDEX idx_shadow_predictions_model_version ON shadow_predictions(model_version);
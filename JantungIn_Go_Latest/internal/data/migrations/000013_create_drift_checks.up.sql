@@ -0,0 +1,14 @@
+-- Hasil pengecekan drift input diagnosis terhadap distribusi data training
+CREATE TABLE drift_checks (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    window_start TIMESTAMP WITH TIME ZONE NOT NULL,
+    window_end TIMESTAMP WITH TIME ZONE NOT NULL,
+    sample_size INT NOT NULL DEFAULT 0,
+    alert BOOLEAN NOT NULL DEFAULT FALSE,
+    drifted_features JSONB NOT NULL DEFAULT '[]',
+    features JSONB NOT NULL DEFAULT '[]',
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_drift_checks_alert ON drift_checks(alert);
+CREATE INDEX idx_drift_checks_created_at ON drift_checks(created_at);
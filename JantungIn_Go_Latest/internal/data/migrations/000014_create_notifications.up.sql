@@ -0,0 +1,39 @@
+-- Aturan kapan diagnosis baru memicu alert ke dokter
+CREATE TABLE alert_rules (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    name VARCHAR(100) NOT NULL,
+    type VARCHAR(30) NOT NULL,
+    threshold FLOAT,
+    enabled BOOLEAN NOT NULL DEFAULT TRUE,
+    email_enabled BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Aturan bawaan: risiko >= 70% dan kenaikan kategori menjadi High Risk
+INSERT INTO alert_rules (name, type, threshold) VALUES
+    ('Risiko kardiovaskular >= 70%', 'risk_threshold', 70),
+    ('Kategori naik menjadi High Risk', 'category_jump', NULL);
+
+-- Alert in-app per dokter, satu per diagnosis per rule per penerima
+CREATE TABLE notifications (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    recipient_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    diagnosis_id UUID NOT NULL REFERENCES diagnoses(id) ON DELETE CASCADE,
+    patient_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    rule_id UUID NOT NULL REFERENCES alert_rules(id) ON DELETE CASCADE,
+    type VARCHAR(30) NOT NULL,
+    title VARCHAR(255) NOT NULL,
+    message TEXT NOT NULL,
+    result_percentage FLOAT NOT NULL,
+    cardiovascular_risk VARCHAR(255) NOT NULL,
+    email_status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    email_error TEXT NOT NULL DEFAULT '',
+    acknowledged_at TIMESTAMP WITH TIME ZONE,
+    acknowledgement_note TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX idx_notifications_diagnosis_rule_recipient ON notifications(recipient_id, diagnosis_id, rule_id);
+CREATE INDEX idx_notifications_recipient_id ON notifications(recipient_id);
+CREATE INDEX idx_notifications_acknowledged_at ON notifications(acknowledged_at);
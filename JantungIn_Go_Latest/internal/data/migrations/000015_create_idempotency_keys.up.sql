@@ -0,0 +1,14 @@
+-- Hasil request dengan header Idempotency-Key, untuk replay saat client retry
+CREATE TABLE idempotency_keys (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    endpoint VARCHAR(50) NOT NULL,
+    key VARCHAR(255) NOT NULL,
+    request_hash CHAR(64) NOT NULL,
+    response_body JSONB,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL
+);
+
+CREATE UNIQUE INDEX idx_idempotency_keys_user_endpoint_key ON idempotency_keys(user_id, endpoint, key);
+CREATE INDEX idx_idempotency_keys_expires_at ON idempotency_keys(expires_at);
@@ -0,0 +1,37 @@
+-- Hanya bisa dijalankan selama data masih plaintext. Setelah `encryption reencrypt`, dekripsi data
+-- terlebih dahulu; cast dari ciphertext akan gagal.
+
+DROP INDEX IF EXISTS idx_users_email_index;
+ALTER TABLE users DROP COLUMN IF EXISTS email_index;
+ALTER TABLE users ALTER COLUMN email TYPE VARCHAR(255);
+ALTER TABLE users ADD CONSTRAINT users_email_key UNIQUE (email);
+
+ALTER TABLE diagnoses
+    ALTER COLUMN age TYPE INT USING age::INT,
+    ALTER COLUMN maximum_heart_rate TYPE INT USING maximum_heart_rate::INT,
+    ALTER COLUMN major_vessels TYPE INT USING major_vessels::INT,
+    ALTER COLUMN fasting_blood_sugar TYPE FLOAT USING fasting_blood_sugar::FLOAT,
+    ALTER COLUMN resting_blood_pressure TYPE FLOAT USING resting_blood_pressure::FLOAT,
+    ALTER COLUMN serum_cholesterol TYPE FLOAT USING serum_cholesterol::FLOAT,
+    ALTER COLUMN st_depression TYPE FLOAT USING st_depression::FLOAT,
+    ALTER COLUMN sex TYPE VARCHAR(50) USING sex::VARCHAR,
+    ALTER COLUMN chest_pain_type TYPE VARCHAR(255) USING chest_pain_type::VARCHAR,
+    ALTER COLUMN resting_ecg_results TYPE VARCHAR(255) USING resting_ecg_results::VARCHAR,
+    ALTER COLUMN exercise_induced_angina TYPE VARCHAR(255) USING exercise_induced_angina::VARCHAR,
+    ALTER COLUMN st_segment TYPE VARCHAR(255) USING st_segment::VARCHAR,
+    ALTER COLUMN thalassemia TYPE VARCHAR(255) USING thalassemia::VARCHAR;
+
+ALTER TABLE diagnosis_revisions
+    ALTER COLUMN age TYPE INT USING age::INT,
+    ALTER COLUMN maximum_heart_rate TYPE INT USING maximum_heart_rate::INT,
+    ALTER COLUMN major_vessels TYPE INT USING major_vessels::INT,
+    ALTER COLUMN fasting_blood_sugar TYPE FLOAT USING fasting_blood_sugar::FLOAT,
+    ALTER COLUMN resting_blood_pressure TYPE FLOAT USING resting_blood_pressure::FLOAT,
+    ALTER COLUMN serum_cholesterol TYPE FLOAT USING serum_cholesterol::FLOAT,
+    ALTER COLUMN st_depression TYPE FLOAT USING st_depression::FLOAT,
+    ALTER COLUMN sex TYPE VARCHAR(50) USING sex::VARCHAR,
+    ALTER COLUMN chest_pain_type TYPE VARCHAR(255) USING chest_pain_type::VARCHAR,
+    ALTER COLUMN resting_ecg_results TYPE VARCHAR(255) USING resting_ecg_results::VARCHAR,
+    ALTER COLUMN exercise_induced_angina TYPE VARCHAR(255) USING exercise_induced_angina::VARCHAR,
+    ALTER COLUMN st_segment TYPE VARCHAR(255) USING st_segment::VARCHAR,
+    ALTER COLUMN thalassemia TYPE VARCHAR(255) USING thalassemia::VARCHAR;
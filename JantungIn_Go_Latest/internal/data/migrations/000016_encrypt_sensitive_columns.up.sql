@@ -0,0 +1,43 @@
+-- Kolom sensitif disimpan sebagai ciphertext fieldcrypt ("enc:v1:<keyID>:<base64>"),
+-- sehingga semua kolom terenkripsi menjadi TEXT. Data lama tetap plaintext sampai
+-- `go run . encryption reencrypt` dijalankan; aplikasi membaca keduanya.
+
+ALTER TABLE diagnoses
+    ALTER COLUMN age TYPE TEXT USING age::TEXT,
+    ALTER COLUMN sex TYPE TEXT USING sex::TEXT,
+    ALTER COLUMN chest_pain_type TYPE TEXT USING chest_pain_type::TEXT,
+    ALTER COLUMN resting_ecg_results TYPE TEXT USING resting_ecg_results::TEXT,
+    ALTER COLUMN fasting_blood_sugar TYPE TEXT USING fasting_blood_sugar::TEXT,
+    ALTER COLUMN resting_blood_pressure TYPE TEXT USING resting_blood_pressure::TEXT,
+    ALTER COLUMN maximum_heart_rate TYPE TEXT USING maximum_heart_rate::TEXT,
+    ALTER COLUMN exercise_induced_angina TYPE TEXT USING exercise_induced_angina::TEXT,
+    ALTER COLUMN st_segment TYPE TEXT USING st_segment::TEXT,
+    ALTER COLUMN major_vessels TYPE TEXT USING major_vessels::TEXT,
+    ALTER COLUMN thalassemia TYPE TEXT USING thalassemia::TEXT,
+    ALTER COLUMN serum_cholesterol TYPE TEXT USING serum_cholesterol::TEXT,
+    ALTER COLUMN st_depression TYPE TEXT USING st_depression::TEXT,
+    ALTER COLUMN clinical_notes TYPE TEXT USING clinical_notes::TEXT,
+    ALTER COLUMN review_notes TYPE TEXT USING review_notes::TEXT;
+
+ALTER TABLE diagnosis_revisions
+    ALTER COLUMN age TYPE TEXT USING age::TEXT,
+    ALTER COLUMN sex TYPE TEXT USING sex::TEXT,
+    ALTER COLUMN chest_pain_type TYPE TEXT USING chest_pain_type::TEXT,
+    ALTER COLUMN resting_ecg_results TYPE TEXT USING resting_ecg_results::TEXT,
+    ALTER COLUMN fasting_blood_sugar TYPE TEXT USING fasting_blood_sugar::TEXT,
+    ALTER COLUMN resting_blood_pressure TYPE TEXT USING resting_blood_pressure::TEXT,
+    ALTER COLUMN maximum_heart_rate TYPE TEXT USING maximum_heart_rate::TEXT,
+    ALTER COLUMN exercise_induced_angina TYPE TEXT USING exercise_induced_angina::TEXT,
+    ALTER COLUMN st_segment TYPE TEXT USING st_segment::TEXT,
+    ALTER COLUMN major_vessels TYPE TEXT USING major_vessels::TEXT,
+    ALTER COLUMN thalassemia TYPE TEXT USING thalassemia::TEXT,
+    ALTER COLUMN serum_cholesterol TYPE TEXT USING serum_cholesterol::TEXT,
+    ALTER COLUMN st_depression TYPE TEXT USING st_depression::TEXT,
+    ALTER COLUMN clinical_notes TYPE TEXT USING clinical_notes::TEXT;
+
+-- Email terenkripsi acak tidak bisa di-unique/dicari langsung: keunikan dan pencarian
+-- pindah ke email_index (HMAC-SHA256 email lowercase), diisi oleh `encryption reencrypt`
+ALTER TABLE users DROP CONSTRAINT IF EXISTS users_email_key;
+ALTER TABLE users ALTER COLUMN email TYPE TEXT;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS email_index CHAR(64);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_users_email_index ON users(email_index);
@@ -0,0 +1,2 @@
+-- Peringatan: data yang dienkripsi dengan data key di tabel ini tidak bisa dibaca lagi
+DROP TABLE IF EXISTS encryption_keys;
@@ -0,0 +1,13 @@
+-- Data key enkripsi kolom sensitif, terbungkus master key (envelope encryption)
+CREATE TABLE encryption_keys (
+    id VARCHAR(50) PRIMARY KEY,
+    version INTEGER NOT NULL,
+    master_key_id VARCHAR(50) NOT NULL,
+    wrapped_key BYTEA NOT NULL,
+    active BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Paling banyak satu data key aktif
+CREATE UNIQUE INDEX idx_encryption_keys_active ON encryption_keys(active) WHERE active;
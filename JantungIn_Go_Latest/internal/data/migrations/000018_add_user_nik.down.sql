@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_users_nik_index;
+ALTER TABLE users DROP COLUMN IF EXISTS nik_index;
+ALTER TABLE users DROP COLUMN IF EXISTS nik;
@@ -0,0 +1,4 @@
+-- NIK pasien, dienkripsi oleh aplikasi (fieldcrypt); keunikan & pencarian lewat blind index
+ALTER TABLE users ADD COLUMN IF NOT EXISTS nik TEXT;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS nik_index CHAR(64);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_users_nik_index ON users(nik_index);
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS legacy_migration_issues;
+DROP TABLE IF EXISTS legacy_migration_checkpoints;
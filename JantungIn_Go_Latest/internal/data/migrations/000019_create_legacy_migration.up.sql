@@ -0,0 +1,15 @@
+-- Progres dan catatan perintah migrate-legacy (migrasi dari database API Node lama)
+CREATE TABLE legacy_migration_checkpoints (
+    source VARCHAR(50) PRIMARY KEY,
+    last_id VARCHAR(36) NOT NULL,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE legacy_migration_issues (
+    source VARCHAR(50) NOT NULL,
+    legacy_id VARCHAR(36) NOT NULL,
+    skipped BOOLEAN NOT NULL DEFAULT FALSE,
+    reason TEXT NOT NULL,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (source, legacy_id)
+);
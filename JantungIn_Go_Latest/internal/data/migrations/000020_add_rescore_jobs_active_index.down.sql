@@ -0,0 +1 @@
+DROP INDEX IF EXISTS idx_rescore_jobs_active;
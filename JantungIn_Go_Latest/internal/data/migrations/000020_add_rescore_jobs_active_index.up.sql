@@ -0,0 +1,18 @@
+-- Hanya satu job yang boleh queued/processing, walaupun dua admin memulai bersamaan.
+-- Job aktif ganda yang sudah terlanjur ada (selain yang terbaru) digagalkan dulu
+-- agar unique index bisa dibuat.
+UPDATE rescore_jobs
+SET status = 'failed',
+    error = 'dibatalkan: lebih dari satu job aktif',
+    finished_at = CURRENT_TIMESTAMP,
+    updated_at = CURRENT_TIMESTAMP
+WHERE status IN ('queued', 'processing')
+  AND id <> (
+    SELECT id FROM rescore_jobs
+    WHERE status IN ('queued', 'processing')
+    ORDER BY created_at DESC, id DESC
+    LIMIT 1
+  );
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_rescore_jobs_active ON rescore_jobs((status IN ('queued', 'processing')))
+    WHERE status IN ('queued', 'processing');
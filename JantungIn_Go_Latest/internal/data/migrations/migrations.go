@@ -0,0 +1,8 @@
+// Package migrations menyematkan file migrasi SQL ke dalam binary.
+// Buat file baru dengan `make db-go name=...`; nomor versi harus berurutan.
+package migrations
+
+import "embed"
+
+//go:embed *.sql
+var FS embed.FS
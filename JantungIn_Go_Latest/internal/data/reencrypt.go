@@ -0,0 +1,164 @@
+package data
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"jantungin-api-server/internal/data/entity"
+	"jantungin-api-server/pkg/background"
+	"jantungin-api-server/pkg/fieldcrypt"
+	"jantungin-api-server/pkg/utils"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const reencryptBatchSize = 200
+
+// reencryptLockKey adalah key pg_advisory_lock agar hanya satu proses yang
+// menulis ulang kolom terenkripsi pada satu waktu
+const reencryptLockKey int64 = 7_315_302_452_110_046
+
+// ErrReencryptInProgress dikembalikan jika proses lain sedang menjalankan re-encrypt
+var ErrReencryptInProgress = errors.New("re-encryption is already running on another instance")
+
+// ReencryptSensitiveData menulis ulang kolom terenkripsi yang masih plaintext atau
+// dienkripsi dengan data key lama memakai data key aktif, sekaligus menghitung ulang
+// blind index email dan NIK. Aman dijalankan berulang; updated_at tidak diubah.
+func ReencryptSensitiveData(ctx context.Context, db *gorm.DB) error {
+	keyring := fieldcrypt.Default()
+	if keyring == nil {
+		return fieldcrypt.ErrNoKeyring
+	}
+
+	sqlDB, err := db.DB()
+	if err != nil {
+		return err
+	}
+	conn, err := sqlDB.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	var locked bool
+	if err := conn.QueryRowContext(ctx, "SELECT pg_try_advisory_lock($1)", reencryptLockKey).Scan(&locked); err != nil {
+		return fmt.Errorf("failed to acquire re-encryption lock: %w", err)
+	}
+	if !locked {
+		return ErrReencryptInProgress
+	}
+	defer func() {
+		if _, err := conn.ExecContext(context.WithoutCancel(ctx), "SELECT pg_advisory_unlock($1)", reencryptLockKey); err != nil {
+			utils.Warn("Failed to release re-encryption lock", zap.Error(err))
+		}
+	}()
+
+	utils.Info("Re-encrypting sensitive data", zap.String("active_key_id", keyring.ActiveKeyID()))
+
+	db = db.WithContext(ctx)
+	if err := reencryptTable[entity.User](db, keyring, "users", "email_index", "nik_index"); err != nil {
+		return err
+	}
+	if err := reencryptTable[entity.Diagnosis](db, keyring, "diagnoses"); err != nil {
+		return err
+	}
+	return reencryptTable[entity.DiagnosisRevision](db, keyring, "diagnosis_revisions")
+}
+
+func reencryptTable[T any](db *gorm.DB, keyring *fieldcrypt.Keyring, table string, extraColumns ...string) error {
+	columns, err := fieldcrypt.Columns(db, new(T))
+	if err != nil {
+		return err
+	}
+
+	// Hanya baris yang punya kolom belum terenkripsi dengan kunci aktif
+	activePrefix := fieldcrypt.Prefix(keyring.ActiveKeyID()) + "%"
+	conditions := make([]string, 0, len(columns))
+	args := make([]interface{}, 0, len(columns))
+	for _, column := range columns {
+		conditions = append(conditions, "("+column+" <> '' AND "+column+" NOT LIKE ?)")
+		args = append(args, activePrefix)
+	}
+	pending := strings.Join(conditions, " OR ")
+	updateColumns := append(columns, extraColumns...)
+
+	total, skipped := 0, 0
+	lastID := ""
+	for {
+		var ids []string
+		query := db.Unscoped().Model(new(T)).Where(pending, args...).Order("id").Limit(reencryptBatchSize)
+		if lastID != "" {
+			query = query.Where("id > ?", lastID)
+		}
+		if err := query.Pluck("id", &ids).Error; err != nil {
+			return err
+		}
+		if len(ids) == 0 {
+			break
+		}
+		lastID = ids[len(ids)-1]
+
+		// Baris dibaca ulang dengan FOR UPDATE di transaksi yang sama dengan penulisannya,
+		// sehingga perubahan dari request lain (mis. Amend diagnosis) tidak tertimpa nilai lama
+		err := db.Transaction(func(tx *gorm.DB) error {
+			var rows []T
+			if err := tx.Unscoped().Clauses(clause.Locking{Strength: "UPDATE"}).Where("id IN ?", ids).Find(&rows).Error; err != nil {
+				return err
+			}
+			for i := range rows {
+				row := &rows[i]
+				// UpdateColumns tidak menjalankan hook, jadi BeforeSave (blind index) dipanggil manual
+				if hook, ok := any(row).(interface{ BeforeSave(*gorm.DB) error }); ok {
+					if err := hook.BeforeSave(tx); err != nil {
+						return err
+					}
+				}
+				update := tx.Unscoped().Model(row).Select(updateColumns)
+				if diagnosis, ok := any(row).(*entity.Diagnosis); ok {
+					update = update.Where("version = ?", diagnosis.Version)
+				}
+				result := update.UpdateColumns(row)
+				if result.Error != nil {
+					return result.Error
+				}
+				if result.RowsAffected == 0 {
+					skipped++
+					continue
+				}
+				total++
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	if total > 0 || skipped > 0 {
+		utils.Info("Sensitive data re-encrypted", zap.String("table", table), zap.Int("rows", total), zap.Int("skipped", skipped))
+	}
+	return nil
+}
+
+// StartEncryptionMaintenance membungkus ulang data key dengan master key aktif di
+// background setelah server start. Penulisan ulang data lama tidak dijalankan di sini
+// karena menyentuh seluruh tabel; jalankan `encryption reencrypt` secara eksplisit.
+func StartEncryptionMaintenance(runner *background.Runner, envelope *fieldcrypt.Envelope) {
+	err := runner.Go("encryption-maintenance", func(ctx context.Context) {
+		rewrapped, err := envelope.Rewrap(ctx)
+		if err != nil {
+			utils.Error("Failed to rewrap data keys", zap.Error(err))
+			return
+		}
+		if rewrapped > 0 {
+			utils.Info("Data keys rewrapped with active master key", zap.Int("count", rewrapped))
+		}
+	})
+	if err != nil {
+		utils.Warn("Encryption maintenance not started", zap.Error(err))
+	}
+}
@@ -4,9 +4,11 @@ import (
 	"context"
 	"errors"
 	"jantungin-api-server/internal/data/entity"
+	"time"
 
 	"github.com/google/uuid"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type DiagnosisRepository interface {
@@ -14,8 +16,22 @@ type DiagnosisRepository interface {
 	FindByID(ctx context.Context, id uuid.UUID) (*entity.Diagnosis, error)
 	FindAll(ctx context.Context) ([]entity.Diagnosis, error)
 	FindByPatientID(ctx context.Context, patientID uuid.UUID) ([]entity.Diagnosis, error)
+	FindByIDWithDeleted(ctx context.Context, id uuid.UUID) (*entity.Diagnosis, error)
+	Amend(ctx context.Context, diagnosis *entity.Diagnosis, revision *entity.DiagnosisRevision) error
+	SoftDelete(ctx context.Context, diagnosis *entity.Diagnosis, revision *entity.DiagnosisRevision) error
+	FindRevisions(ctx context.Context, diagnosisID uuid.UUID) ([]entity.DiagnosisRevision, error)
+	UpdateReviewStatus(ctx context.Context, diagnosis *entity.Diagnosis, fromStatus string, version int) error
+	FindPendingReview(ctx context.Context, excludeCreator uuid.UUID) ([]entity.Diagnosis, error)
+	CountAll(ctx context.Context) (int64, error)
+	FindBatchAfter(ctx context.Context, afterID uuid.UUID, limit int) ([]entity.Diagnosis, error)
+	CountCreatedBetween(ctx context.Context, from, to time.Time) (int64, error)
+	SampleCreatedBetween(ctx context.Context, from, to time.Time, limit int) ([]entity.Diagnosis, error)
 }
 
+// ErrDiagnosisVersionConflict dikembalikan jika diagnosis sudah diubah request lain
+// sejak dibaca (versi atau status review di database tidak lagi sama).
+var ErrDiagnosisVersionConflict = errors.New("diagnosis version conflict")
+
 type diagnosisRepository struct {
 	db *gorm.DB
 }
@@ -39,6 +55,9 @@ func (r *diagnosisRepository) FindByID(ctx context.Context, id uuid.UUID) (*enti
 		Preload("Creator", func(db *gorm.DB) *gorm.DB {
 			return db.Select("id, name, role")
 		}).
+		Preload("Reviewer", func(db *gorm.DB) *gorm.DB {
+			return db.Select("id, name, role")
+		}).
 		Where("id = ?", id).
 		First(&diagnosis).Error
 	if err != nil {
@@ -59,6 +78,9 @@ func (r *diagnosisRepository) FindAll(ctx context.Context) ([]entity.Diagnosis,
 		Preload("Creator", func(db *gorm.DB) *gorm.DB {
 			return db.Select("id, name, role")
 		}).
+		Preload("Reviewer", func(db *gorm.DB) *gorm.DB {
+			return db.Select("id, name, role")
+		}).
 		Order("created_at DESC").
 		Find(&diagnoses).Error
 	if err != nil {
@@ -76,6 +98,9 @@ func (r *diagnosisRepository) FindByPatientID(ctx context.Context, patientID uui
 		Preload("Creator", func(db *gorm.DB) *gorm.DB {
 			return db.Select("id, name, role")
 		}).
+		Preload("Reviewer", func(db *gorm.DB) *gorm.DB {
+			return db.Select("id, name, role")
+		}).
 		Where("user_id = ?", patientID).
 		Order("created_at DESC").
 		Find(&diagnoses).Error
@@ -84,3 +109,180 @@ func (r *diagnosisRepository) FindByPatientID(ctx context.Context, patientID uui
 	}
 	return diagnoses, nil
 }
+
+// FindByIDWithDeleted sama seperti FindByID tetapi ikut mengambil diagnosis yang sudah di-soft delete
+func (r *diagnosisRepository) FindByIDWithDeleted(ctx context.Context, id uuid.UUID) (*entity.Diagnosis, error) {
+	var diagnosis entity.Diagnosis
+	err := r.db.WithContext(ctx).
+		Unscoped().
+		Where("id = ?", id).
+		First(&diagnosis).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &diagnosis, nil
+}
+
+// Amend menulis nilai baru lalu menyimpan snapshot versi lama ke diagnosis_revisions
+// dalam satu transaksi. diagnosis.Version harus masih berisi versi yang dibaca;
+// versi dinaikkan di sini. UPDATE berversi dijalankan lebih dulu agar amend bersamaan
+// pada versi yang sama berakhir dengan ErrDiagnosisVersionConflict, bukan unique
+// violation pada idx_diagnosis_revisions_version.
+func (r *diagnosisRepository) Amend(ctx context.Context, diagnosis *entity.Diagnosis, revision *entity.DiagnosisRevision) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		currentVersion := diagnosis.Version
+		diagnosis.Version++
+
+		result := tx.Model(&entity.Diagnosis{}).
+			Where("id = ? AND version = ? AND status = ?", diagnosis.ID, currentVersion, revision.Status).
+			Select("*").
+			Omit("id", "user_id", "created_by", "created_at", "deleted_at", "deleted_by", clause.Associations).
+			Updates(diagnosis)
+		if result.Error != nil {
+			diagnosis.Version = currentVersion
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			diagnosis.Version = currentVersion
+			return ErrDiagnosisVersionConflict
+		}
+
+		if err := tx.Create(revision).Error; err != nil {
+			diagnosis.Version = currentVersion
+			return err
+		}
+		return nil
+	})
+}
+
+// SoftDelete menandai diagnosis sebagai terhapus lalu menyimpan snapshot terakhir.
+// Urutannya sama dengan Amend agar penghapusan bersamaan berakhir dengan konflik versi.
+func (r *diagnosisRepository) SoftDelete(ctx context.Context, diagnosis *entity.Diagnosis, revision *entity.DiagnosisRevision) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&entity.Diagnosis{}).
+			Where("id = ? AND version = ? AND status = ?", diagnosis.ID, diagnosis.Version, revision.Status).
+			Updates(map[string]any{
+				"deleted_at": gorm.Expr("CURRENT_TIMESTAMP"),
+				"deleted_by": revision.EditedBy,
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrDiagnosisVersionConflict
+		}
+		return tx.Create(revision).Error
+	})
+}
+
+// FindRevisions mengambil riwayat versi diagnosis, terbaru di atas
+func (r *diagnosisRepository) FindRevisions(ctx context.Context, diagnosisID uuid.UUID) ([]entity.DiagnosisRevision, error) {
+	var revisions []entity.DiagnosisRevision
+	err := r.db.WithContext(ctx).
+		Preload("Editor", func(db *gorm.DB) *gorm.DB {
+			return db.Select("id, name, role")
+		}).
+		Where("diagnosis_id = ?", diagnosisID).
+		Order("version DESC").
+		Find(&revisions).Error
+	if err != nil {
+		return nil, err
+	}
+	return revisions, nil
+}
+
+// UpdateReviewStatus menyimpan status review baru beserta reviewer-nya.
+// Update hanya berhasil jika status di database masih fromStatus dan versinya
+// masih version, sehingga reviewer tidak menandatangani nilai yang belum dilihatnya.
+func (r *diagnosisRepository) UpdateReviewStatus(ctx context.Context, diagnosis *entity.Diagnosis, fromStatus string, version int) error {
+	result := r.db.WithContext(ctx).
+		Model(&entity.Diagnosis{}).
+		Where("id = ? AND status = ? AND version = ?", diagnosis.ID, fromStatus, version).
+		Updates(map[string]any{
+			"status":       diagnosis.Status,
+			"reviewed_by":  diagnosis.ReviewedBy,
+			"reviewed_at":  diagnosis.ReviewedAt,
+			"review_notes": diagnosis.ReviewNotes,
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrDiagnosisVersionConflict
+	}
+	return nil
+}
+
+// FindPendingReview mengambil antrean diagnosis yang menunggu review, terlama di atas.
+// Diagnosis buatan excludeCreator tidak diikutkan karena tidak boleh di-review sendiri.
+func (r *diagnosisRepository) FindPendingReview(ctx context.Context, excludeCreator uuid.UUID) ([]entity.Diagnosis, error) {
+	var diagnoses []entity.Diagnosis
+	err := r.db.WithContext(ctx).
+		Preload("Patient", func(db *gorm.DB) *gorm.DB {
+			return db.Select("id, name, role")
+		}).
+		Preload("Creator", func(db *gorm.DB) *gorm.DB {
+			return db.Select("id, name, role")
+		}).
+		Where("status = ?", entity.DiagnosisStatusPendingReview).
+		Where("created_by IS NULL OR created_by <> ?", excludeCreator).
+		Order("updated_at ASC").
+		Find(&diagnoses).Error
+	if err != nil {
+		return nil, err
+	}
+	return diagnoses, nil
+}
+
+func (r *diagnosisRepository) CountAll(ctx context.Context) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&entity.Diagnosis{}).Count(&count).Error
+	return count, err
+}
+
+// FindBatchAfter mengambil diagnosis berurutan berdasarkan id (keyset pagination)
+// untuk memproses seluruh tabel per halaman. Gunakan uuid.Nil untuk halaman pertama.
+func (r *diagnosisRepository) FindBatchAfter(ctx context.Context, afterID uuid.UUID, limit int) ([]entity.Diagnosis, error) {
+	var diagnoses []entity.Diagnosis
+	err := r.db.WithContext(ctx).
+		Where("id > ?", afterID).
+		Order("id ASC").
+		Limit(limit).
+		Find(&diagnoses).Error
+	if err != nil {
+		return nil, err
+	}
+	return diagnoses, nil
+}
+
+// CountCreatedBetween menghitung diagnosis yang dibuat dalam rentang [from, to)
+func (r *diagnosisRepository) CountCreatedBetween(ctx context.Context, from, to time.Time) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).
+		Model(&entity.Diagnosis{}).
+		Where("created_at >= ? AND created_at < ?", from, to).
+		Count(&count).Error
+	return count, err
+}
+
+// SampleCreatedBetween mengambil paling banyak limit diagnosis acak yang dibuat dalam
+// rentang [from, to), hanya kolom input klinis. Kolom terenkripsi tidak bisa diagregasi
+// di SQL, jadi statistik dihitung dari sampel agar memori tetap terbatas.
+func (r *diagnosisRepository) SampleCreatedBetween(ctx context.Context, from, to time.Time, limit int) ([]entity.Diagnosis, error) {
+	var diagnoses []entity.Diagnosis
+	err := r.db.WithContext(ctx).
+		Select("id", "age", "sex", "chest_pain_type", "resting_blood_pressure", "serum_cholesterol",
+			"fasting_blood_sugar", "resting_ecg_results", "maximum_heart_rate", "exercise_induced_angina",
+			"st_depression", "st_segment", "major_vessels", "thalassemia").
+		Where("created_at >= ? AND created_at < ?", from, to).
+		Order("random()").
+		Limit(limit).
+		Find(&diagnoses).Error
+	if err != nil {
+		return nil, err
+	}
+	return diagnoses, nil
+}
@@ -0,0 +1,39 @@
+package repository
+
+import (
+	"context"
+	"jantungin-api-server/internal/data/entity"
+
+	"gorm.io/gorm"
+)
+
+type DriftRepository interface {
+	Create(ctx context.Context, check *entity.DriftCheck) error
+	FindRecent(ctx context.Context, limit int) ([]entity.DriftCheck, error)
+}
+
+type driftRepository struct {
+	db *gorm.DB
+}
+
+func NewDriftRepository(db *gorm.DB) DriftRepository {
+	return &driftRepository{
+		db: db,
+	}
+}
+
+func (r *driftRepository) Create(ctx context.Context, check *entity.DriftCheck) error {
+	return r.db.WithContext(ctx).Create(check).Error
+}
+
+func (r *driftRepository) FindRecent(ctx context.Context, limit int) ([]entity.DriftCheck, error) {
+	var checks []entity.DriftCheck
+	err := r.db.WithContext(ctx).
+		Order("created_at DESC").
+		Limit(limit).
+		Find(&checks).Error
+	if err != nil {
+		return nil, err
+	}
+	return checks, nil
+}
@@ -0,0 +1,105 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"jantungin-api-server/internal/data/entity"
+	"jantungin-api-server/pkg/fieldcrypt"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// EncryptionKeyRepository adalah KeyStore fieldcrypt berbasis tabel encryption_keys
+type EncryptionKeyRepository interface {
+	fieldcrypt.KeyStore
+}
+
+type encryptionKeyRepository struct {
+	db *gorm.DB
+}
+
+func NewEncryptionKeyRepository(db *gorm.DB) EncryptionKeyRepository {
+	return &encryptionKeyRepository{
+		db: db,
+	}
+}
+
+func (r *encryptionKeyRepository) FindKeys(ctx context.Context) ([]fieldcrypt.WrappedKey, error) {
+	var records []entity.EncryptionKey
+	if err := r.db.WithContext(ctx).Order("version ASC").Find(&records).Error; err != nil {
+		return nil, err
+	}
+
+	keys := make([]fieldcrypt.WrappedKey, 0, len(records))
+	for _, record := range records {
+		keys = append(keys, toWrappedKey(record))
+	}
+	return keys, nil
+}
+
+func (r *encryptionKeyRepository) FindKey(ctx context.Context, id string) (*fieldcrypt.WrappedKey, error) {
+	var record entity.EncryptionKey
+	err := r.db.WithContext(ctx).Where("id = ?", id).First(&record).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	key := toWrappedKey(record)
+	return &key, nil
+}
+
+// CreateKey tidak menimpa key yang sudah ada, sehingga aman jika beberapa instance
+// start bersamaan dan membuat key dengan ID yang sama
+func (r *encryptionKeyRepository) CreateKey(ctx context.Context, key fieldcrypt.WrappedKey) error {
+	return r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&entity.EncryptionKey{
+			ID:          key.ID,
+			Version:     key.Version,
+			MasterKeyID: key.MasterKeyID,
+			WrappedKey:  key.Ciphertext,
+		}).Error
+}
+
+// ActivateKey menonaktifkan key aktif sebelumnya dan mengaktifkan key id dalam satu transaksi
+func (r *encryptionKeyRepository) ActivateKey(ctx context.Context, id string) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&entity.EncryptionKey{}).
+			Where("active AND id <> ?", id).
+			Update("active", false).Error; err != nil {
+			return err
+		}
+
+		result := tx.Model(&entity.EncryptionKey{}).Where("id = ?", id).Update("active", true)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return errors.New("encryption key not found")
+		}
+		return nil
+	})
+}
+
+func (r *encryptionKeyRepository) RewrapKey(ctx context.Context, id string, masterKeyID string, ciphertext []byte) error {
+	return r.db.WithContext(ctx).
+		Model(&entity.EncryptionKey{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"master_key_id": masterKeyID,
+			"wrapped_key":   ciphertext,
+		}).Error
+}
+
+func toWrappedKey(record entity.EncryptionKey) fieldcrypt.WrappedKey {
+	return fieldcrypt.WrappedKey{
+		ID:          record.ID,
+		Version:     record.Version,
+		MasterKeyID: record.MasterKeyID,
+		Ciphertext:  record.WrappedKey,
+		Active:      record.Active,
+	}
+}
@@ -0,0 +1,74 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"jantungin-api-server/internal/data/entity"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type IdempotencyRepository interface {
+	Reserve(ctx context.Context, record *entity.IdempotencyKey) (bool, error)
+	Find(ctx context.Context, userID uuid.UUID, endpoint string, key string) (*entity.IdempotencyKey, error)
+	Complete(ctx context.Context, id uuid.UUID, responseBody string) error
+	Delete(ctx context.Context, id uuid.UUID) error
+	DeleteExpired(ctx context.Context, now time.Time) (int64, error)
+}
+
+type idempotencyRepository struct {
+	db *gorm.DB
+}
+
+func NewIdempotencyRepository(db *gorm.DB) IdempotencyRepository {
+	return &idempotencyRepository{
+		db: db,
+	}
+}
+
+// Reserve menyimpan key baru dengan status sedang diproses. Mengembalikan false tanpa
+// error jika key yang sama untuk user dan endpoint ini sudah ada.
+func (r *idempotencyRepository) Reserve(ctx context.Context, record *entity.IdempotencyKey) (bool, error) {
+	result := r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(record)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+func (r *idempotencyRepository) Find(ctx context.Context, userID uuid.UUID, endpoint string, key string) (*entity.IdempotencyKey, error) {
+	var record entity.IdempotencyKey
+	err := r.db.WithContext(ctx).
+		Where("user_id = ? AND endpoint = ? AND key = ?", userID, endpoint, key).
+		First(&record).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &record, nil
+}
+
+// Complete menyimpan response request pertama untuk dikembalikan saat replay
+func (r *idempotencyRepository) Complete(ctx context.Context, id uuid.UUID, responseBody string) error {
+	return r.db.WithContext(ctx).
+		Model(&entity.IdempotencyKey{}).
+		Where("id = ?", id).
+		Update("response_body", responseBody).Error
+}
+
+func (r *idempotencyRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	return r.db.WithContext(ctx).Where("id = ?", id).Delete(&entity.IdempotencyKey{}).Error
+}
+
+// DeleteExpired menghapus key yang sudah melewati masa retensi
+func (r *idempotencyRepository) DeleteExpired(ctx context.Context, now time.Time) (int64, error) {
+	result := r.db.WithContext(ctx).Where("expires_at < ?", now).Delete(&entity.IdempotencyKey{})
+	return result.RowsAffected, result.Error
+}
@@ -0,0 +1,119 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"jantungin-api-server/internal/data/entity"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type ImportJobRepository interface {
+	Create(ctx context.Context, job *entity.ImportJob) error
+	FindByID(ctx context.Context, id uuid.UUID) (*entity.ImportJob, error)
+	FindByCreator(ctx context.Context, creatorID uuid.UUID) ([]entity.ImportJob, error)
+	FindAll(ctx context.Context) ([]entity.ImportJob, error)
+	UpdateStatus(ctx context.Context, job *entity.ImportJob) error
+	SaveRowResult(ctx context.Context, row *entity.ImportJobRow) error
+	FindRows(ctx context.Context, jobID uuid.UUID, status string) ([]entity.ImportJobRow, error)
+}
+
+type importJobRepository struct {
+	db *gorm.DB
+}
+
+func NewImportJobRepository(db *gorm.DB) ImportJobRepository {
+	return &importJobRepository{
+		db: db,
+	}
+}
+
+func (r *importJobRepository) Create(ctx context.Context, job *entity.ImportJob) error {
+	return r.db.WithContext(ctx).Create(job).Error
+}
+
+func (r *importJobRepository) FindByID(ctx context.Context, id uuid.UUID) (*entity.ImportJob, error) {
+	var job entity.ImportJob
+	err := r.db.WithContext(ctx).Where("id = ?", id).First(&job).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &job, nil
+}
+
+func (r *importJobRepository) FindByCreator(ctx context.Context, creatorID uuid.UUID) ([]entity.ImportJob, error) {
+	var jobs []entity.ImportJob
+	err := r.db.WithContext(ctx).
+		Where("created_by = ?", creatorID).
+		Order("created_at DESC").
+		Find(&jobs).Error
+	if err != nil {
+		return nil, err
+	}
+	return jobs, nil
+}
+
+func (r *importJobRepository) FindAll(ctx context.Context) ([]entity.ImportJob, error) {
+	var jobs []entity.ImportJob
+	err := r.db.WithContext(ctx).
+		Order("created_at DESC").
+		Find(&jobs).Error
+	if err != nil {
+		return nil, err
+	}
+	return jobs, nil
+}
+
+// UpdateStatus menyimpan status, error, dan waktu mulai/selesai job
+func (r *importJobRepository) UpdateStatus(ctx context.Context, job *entity.ImportJob) error {
+	return r.db.WithContext(ctx).
+		Model(&entity.ImportJob{}).
+		Where("id = ?", job.ID).
+		Updates(map[string]any{
+			"status":      job.Status,
+			"error":       job.Error,
+			"started_at":  job.StartedAt,
+			"finished_at": job.FinishedAt,
+		}).Error
+}
+
+// SaveRowResult menyimpan hasil satu baris dan menaikkan counter progres job
+// dalam satu transaksi, sehingga counter selalu sama dengan isi import_job_rows.
+func (r *importJobRepository) SaveRowResult(ctx context.Context, row *entity.ImportJobRow) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(row).Error; err != nil {
+			return err
+		}
+
+		counter := "failed_rows"
+		if row.Status == entity.ImportRowStatusSuccess {
+			counter = "succeeded_rows"
+		}
+
+		return tx.Model(&entity.ImportJob{}).
+			Where("id = ?", row.JobID).
+			Updates(map[string]any{
+				"processed_rows": gorm.Expr("processed_rows + 1"),
+				counter:          gorm.Expr(counter + " + 1"),
+			}).Error
+	})
+}
+
+// FindRows mengambil hasil per baris; status kosong berarti semua baris
+func (r *importJobRepository) FindRows(ctx context.Context, jobID uuid.UUID, status string) ([]entity.ImportJobRow, error) {
+	var rows []entity.ImportJobRow
+	query := r.db.WithContext(ctx).Where("job_id = ?", jobID)
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+
+	err := query.Order("row_number ASC").Find(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
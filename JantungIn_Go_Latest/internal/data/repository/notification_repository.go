@@ -0,0 +1,140 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"jantungin-api-server/internal/data/entity"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type NotificationRepository interface {
+	FindRules(ctx context.Context) ([]entity.AlertRule, error)
+	FindEnabledRules(ctx context.Context) ([]entity.AlertRule, error)
+	FindRuleByID(ctx context.Context, id uuid.UUID) (*entity.AlertRule, error)
+	CreateRule(ctx context.Context, rule *entity.AlertRule) error
+	UpdateRule(ctx context.Context, rule *entity.AlertRule) error
+	DeleteRule(ctx context.Context, id uuid.UUID) error
+	Create(ctx context.Context, notification *entity.Notification) (bool, error)
+	FindByRecipient(ctx context.Context, recipientID uuid.UUID, unacknowledgedOnly bool) ([]entity.Notification, error)
+	FindByID(ctx context.Context, id uuid.UUID) (*entity.Notification, error)
+	Acknowledge(ctx context.Context, id uuid.UUID, at time.Time, note string) error
+	UpdateEmailStatus(ctx context.Context, id uuid.UUID, status string, emailErr string) error
+}
+
+type notificationRepository struct {
+	db *gorm.DB
+}
+
+func NewNotificationRepository(db *gorm.DB) NotificationRepository {
+	return &notificationRepository{
+		db: db,
+	}
+}
+
+func (r *notificationRepository) FindRules(ctx context.Context) ([]entity.AlertRule, error) {
+	var rules []entity.AlertRule
+	err := r.db.WithContext(ctx).Order("created_at ASC").Find(&rules).Error
+	if err != nil {
+		return nil, err
+	}
+	return rules, nil
+}
+
+func (r *notificationRepository) FindEnabledRules(ctx context.Context) ([]entity.AlertRule, error) {
+	var rules []entity.AlertRule
+	err := r.db.WithContext(ctx).
+		Where("enabled = ?", true).
+		Order("created_at ASC").
+		Find(&rules).Error
+	if err != nil {
+		return nil, err
+	}
+	return rules, nil
+}
+
+func (r *notificationRepository) FindRuleByID(ctx context.Context, id uuid.UUID) (*entity.AlertRule, error) {
+	var rule entity.AlertRule
+	err := r.db.WithContext(ctx).Where("id = ?", id).First(&rule).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &rule, nil
+}
+
+func (r *notificationRepository) CreateRule(ctx context.Context, rule *entity.AlertRule) error {
+	return r.db.WithContext(ctx).Create(rule).Error
+}
+
+// UpdateRule menyimpan semua kolom rule, termasuk nilai false/nil
+func (r *notificationRepository) UpdateRule(ctx context.Context, rule *entity.AlertRule) error {
+	return r.db.WithContext(ctx).Save(rule).Error
+}
+
+func (r *notificationRepository) DeleteRule(ctx context.Context, id uuid.UUID) error {
+	return r.db.WithContext(ctx).Where("id = ?", id).Delete(&entity.AlertRule{}).Error
+}
+
+// Create menyimpan notifikasi baru. Mengembalikan false tanpa error jika notifikasi
+// untuk diagnosis, rule, dan penerima yang sama sudah ada.
+func (r *notificationRepository) Create(ctx context.Context, notification *entity.Notification) (bool, error) {
+	result := r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(notification)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+func (r *notificationRepository) FindByRecipient(ctx context.Context, recipientID uuid.UUID, unacknowledgedOnly bool) ([]entity.Notification, error) {
+	var notifications []entity.Notification
+	query := r.db.WithContext(ctx).Where("recipient_id = ?", recipientID)
+	if unacknowledgedOnly {
+		query = query.Where("acknowledged_at IS NULL")
+	}
+
+	err := query.Order("created_at DESC").Limit(200).Find(&notifications).Error
+	if err != nil {
+		return nil, err
+	}
+	return notifications, nil
+}
+
+func (r *notificationRepository) FindByID(ctx context.Context, id uuid.UUID) (*entity.Notification, error) {
+	var notification entity.Notification
+	err := r.db.WithContext(ctx).Where("id = ?", id).First(&notification).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &notification, nil
+}
+
+func (r *notificationRepository) Acknowledge(ctx context.Context, id uuid.UUID, at time.Time, note string) error {
+	return r.db.WithContext(ctx).
+		Model(&entity.Notification{}).
+		Where("id = ? AND acknowledged_at IS NULL", id).
+		Updates(map[string]any{
+			"acknowledged_at":      at,
+			"acknowledgement_note": note,
+		}).Error
+}
+
+func (r *notificationRepository) UpdateEmailStatus(ctx context.Context, id uuid.UUID, status string, emailErr string) error {
+	return r.db.WithContext(ctx).
+		Model(&entity.Notification{}).
+		Where("id = ?", id).
+		Updates(map[string]any{
+			"email_status": status,
+			"email_error":  emailErr,
+		}).Error
+}
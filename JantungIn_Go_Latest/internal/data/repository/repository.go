@@ -3,17 +3,29 @@ package repository
 import "gorm.io/gorm"
 
 type Repository struct {
-	UserRepo       UserRepository
-	DiagnosisRepo  DiagnosisRepository
-	StatsRepo      StatsRepository
-	UserDeviceRepo UserDeviceRepository
+	UserRepo         UserRepository
+	DiagnosisRepo    DiagnosisRepository
+	StatsRepo        StatsRepository
+	UserDeviceRepo   UserDeviceRepository
+	ImportJobRepo    ImportJobRepository
+	RescoreRepo      RescoreRepository
+	ShadowRepo       ShadowRepository
+	DriftRepo        DriftRepository
+	NotificationRepo NotificationRepository
+	IdempotencyRepo  IdempotencyRepository
 }
 
 func NewRepository(db *gorm.DB) *Repository {
 	return &Repository{
-		UserRepo:       NewUserRepository(db),
-		DiagnosisRepo:  NewDiagnosisRepository(db),
-		StatsRepo:      NewStatsRepository(db),
-		UserDeviceRepo: NewUserDeviceRepository(db),
+		UserRepo:         NewUserRepository(db),
+		DiagnosisRepo:    NewDiagnosisRepository(db),
+		StatsRepo:        NewStatsRepository(db),
+		UserDeviceRepo:   NewUserDeviceRepository(db),
+		ImportJobRepo:    NewImportJobRepository(db),
+		RescoreRepo:      NewRescoreRepository(db),
+		ShadowRepo:       NewShadowRepository(db),
+		DriftRepo:        NewDriftRepository(db),
+		NotificationRepo: NewNotificationRepository(db),
+		IdempotencyRepo:  NewIdempotencyRepository(db),
 	}
 }
@@ -0,0 +1,239 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"jantungin-api-server/internal/data/entity"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ErrRescoreJobActive dikembalikan CreateJob jika sudah ada job queued/processing
+// (dijaga unique index idx_rescore_jobs_active)
+var ErrRescoreJobActive = errors.New("another rescore job is active")
+
+// rescoreLockKey adalah key pg_advisory_lock yang dipegang selama job diproses,
+// sehingga job aktif tanpa pemegang lock bisa dikenali sebagai sisa proses yang mati
+const rescoreLockKey int64 = 7_315_302_452_110_047
+
+// RiskTransition adalah jumlah diagnosis dan pasien untuk satu pasangan
+// kategori risiko lama -> kategori risiko hasil model baru
+type RiskTransition struct {
+	PreviousCardiovascularRisk string `json:"previousCardiovascularRisk"`
+	CardiovascularRisk         string `json:"cardiovascularRisk"`
+	Diagnoses                  int64  `json:"diagnoses"`
+	Patients                   int64  `json:"patients"`
+}
+
+// RescoreSummary adalah ringkasan perbandingan hasil rescore job dengan nilai asli.
+// Pasien dihitung berubah jika minimal satu diagnosisnya berpindah kategori.
+type RescoreSummary struct {
+	ScoredDiagnoses   int64            `json:"scoredDiagnoses"`
+	ChangedDiagnoses  int64            `json:"changedDiagnoses"`
+	ScoredPatients    int64            `json:"scoredPatients"`
+	ChangedPatients   int64            `json:"changedPatients"`
+	MeanAbsoluteDelta float64          `json:"meanAbsoluteDelta"` // rata-rata |resultPercentage baru - lama|
+	Transitions       []RiskTransition `gorm:"-" json:"transitions"`
+}
+
+type RescoreRepository interface {
+	CreateJob(ctx context.Context, job *entity.RescoreJob) error
+	FindJobByID(ctx context.Context, id uuid.UUID) (*entity.RescoreJob, error)
+	FindJobs(ctx context.Context) ([]entity.RescoreJob, error)
+	FindActiveJob(ctx context.Context) (*entity.RescoreJob, error)
+	FailActiveJobs(ctx context.Context, reason string) (int64, error)
+	WithProcessingLock(ctx context.Context, fn func() error) (bool, error)
+	UpdateJobStatus(ctx context.Context, job *entity.RescoreJob) error
+	SaveScores(ctx context.Context, jobID uuid.UUID, scores []entity.DiagnosisScore, failed int) error
+	FindScores(ctx context.Context, jobID uuid.UUID, changedOnly bool) ([]entity.DiagnosisScore, error)
+	Summarize(ctx context.Context, jobID uuid.UUID) (*RescoreSummary, error)
+}
+
+type rescoreRepository struct {
+	db *gorm.DB
+}
+
+func NewRescoreRepository(db *gorm.DB) RescoreRepository {
+	return &rescoreRepository{
+		db: db,
+	}
+}
+
+func (r *rescoreRepository) CreateJob(ctx context.Context, job *entity.RescoreJob) error {
+	result := r.db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(job)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrRescoreJobActive
+	}
+	return nil
+}
+
+func (r *rescoreRepository) FindJobByID(ctx context.Context, id uuid.UUID) (*entity.RescoreJob, error) {
+	var job entity.RescoreJob
+	err := r.db.WithContext(ctx).Where("id = ?", id).First(&job).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &job, nil
+}
+
+func (r *rescoreRepository) FindJobs(ctx context.Context) ([]entity.RescoreJob, error) {
+	var jobs []entity.RescoreJob
+	err := r.db.WithContext(ctx).
+		Order("created_at DESC").
+		Find(&jobs).Error
+	if err != nil {
+		return nil, err
+	}
+	return jobs, nil
+}
+
+// FindActiveJob mengambil job yang masih queued/processing, nil jika tidak ada
+func (r *rescoreRepository) FindActiveJob(ctx context.Context) (*entity.RescoreJob, error) {
+	var job entity.RescoreJob
+	err := r.db.WithContext(ctx).
+		Where("status IN ?", []string{entity.RescoreJobStatusQueued, entity.RescoreJobStatusProcessing}).
+		Order("created_at ASC").
+		First(&job).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &job, nil
+}
+
+// FailActiveJobs menandai semua job queued/processing sebagai failed dengan alasan reason.
+// Hanya dipanggil sambil memegang WithProcessingLock, saat tidak ada job yang benar-benar berjalan.
+func (r *rescoreRepository) FailActiveJobs(ctx context.Context, reason string) (int64, error) {
+	result := r.db.WithContext(ctx).
+		Model(&entity.RescoreJob{}).
+		Where("status IN ?", []string{entity.RescoreJobStatusQueued, entity.RescoreJobStatusProcessing}).
+		Updates(map[string]any{
+			"status":      entity.RescoreJobStatusFailed,
+			"error":       reason,
+			"finished_at": time.Now(),
+		})
+	return result.RowsAffected, result.Error
+}
+
+// WithProcessingLock menjalankan fn sambil memegang advisory lock rescore pada satu koneksi.
+// Mengembalikan false tanpa menjalankan fn jika lock dipegang proses lain.
+func (r *rescoreRepository) WithProcessingLock(ctx context.Context, fn func() error) (bool, error) {
+	sqlDB, err := r.db.DB()
+	if err != nil {
+		return false, err
+	}
+	conn, err := sqlDB.Conn(ctx)
+	if err != nil {
+		return false, err
+	}
+	defer conn.Close()
+
+	var locked bool
+	if err := conn.QueryRowContext(ctx, "SELECT pg_try_advisory_lock($1)", rescoreLockKey).Scan(&locked); err != nil {
+		return false, err
+	}
+	if !locked {
+		return false, nil
+	}
+	defer conn.ExecContext(context.WithoutCancel(ctx), "SELECT pg_advisory_unlock($1)", rescoreLockKey)
+
+	return true, fn()
+}
+
+// UpdateJobStatus menyimpan status, total, error, dan waktu mulai/selesai job
+func (r *rescoreRepository) UpdateJobStatus(ctx context.Context, job *entity.RescoreJob) error {
+	return r.db.WithContext(ctx).
+		Model(&entity.RescoreJob{}).
+		Where("id = ?", job.ID).
+		Updates(map[string]any{
+			"status":          job.Status,
+			"total_diagnoses": job.TotalDiagnoses,
+			"error":           job.Error,
+			"started_at":      job.StartedAt,
+			"finished_at":     job.FinishedAt,
+		}).Error
+}
+
+// SaveScores menyimpan satu halaman hasil rescore dan menaikkan counter progres job
+// dalam satu transaksi. failed adalah jumlah diagnosis di halaman ini yang gagal diprediksi.
+func (r *rescoreRepository) SaveScores(ctx context.Context, jobID uuid.UUID, scores []entity.DiagnosisScore, failed int) error {
+	changed := 0
+	for _, s := range scores {
+		if s.CategoryChanged {
+			changed++
+		}
+	}
+
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if len(scores) > 0 {
+			if err := tx.CreateInBatches(scores, 100).Error; err != nil {
+				return err
+			}
+		}
+
+		return tx.Model(&entity.RescoreJob{}).
+			Where("id = ?", jobID).
+			Updates(map[string]any{
+				"processed_diagnoses": gorm.Expr("processed_diagnoses + ?", len(scores)+failed),
+				"failed_diagnoses":    gorm.Expr("failed_diagnoses + ?", failed),
+				"changed_diagnoses":   gorm.Expr("changed_diagnoses + ?", changed),
+			}).Error
+	})
+}
+
+// FindScores mengambil hasil rescore per diagnosis; changedOnly hanya yang kategorinya berubah
+func (r *rescoreRepository) FindScores(ctx context.Context, jobID uuid.UUID, changedOnly bool) ([]entity.DiagnosisScore, error) {
+	var scores []entity.DiagnosisScore
+	query := r.db.WithContext(ctx).Where("job_id = ?", jobID)
+	if changedOnly {
+		query = query.Where("category_changed = ?", true)
+	}
+
+	err := query.Order("created_at ASC, diagnosis_id ASC").Find(&scores).Error
+	if err != nil {
+		return nil, err
+	}
+	return scores, nil
+}
+
+func (r *rescoreRepository) Summarize(ctx context.Context, jobID uuid.UUID) (*RescoreSummary, error) {
+	var summary RescoreSummary
+	err := r.db.WithContext(ctx).
+		Model(&entity.DiagnosisScore{}).
+		Select(`COUNT(*) AS scored_diagnoses,
+			COUNT(*) FILTER (WHERE category_changed) AS changed_diagnoses,
+			COUNT(DISTINCT user_id) AS scored_patients,
+			COUNT(DISTINCT user_id) FILTER (WHERE category_changed) AS changed_patients,
+			COALESCE(AVG(ABS(result_percentage - previous_result_percentage)), 0) AS mean_absolute_delta`).
+		Where("job_id = ?", jobID).
+		Scan(&summary).Error
+	if err != nil {
+		return nil, err
+	}
+
+	err = r.db.WithContext(ctx).
+		Model(&entity.DiagnosisScore{}).
+		Select(`previous_cardiovascular_risk, cardiovascular_risk,
+			COUNT(*) AS diagnoses,
+			COUNT(DISTINCT user_id) AS patients`).
+		Where("job_id = ?", jobID).
+		Group("previous_cardiovascular_risk, cardiovascular_risk").
+		Order("previous_cardiovascular_risk, cardiovascular_risk").
+		Scan(&summary.Transitions).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return &summary, nil
+}
@@ -0,0 +1,79 @@
+package repository
+
+import (
+	"context"
+	"jantungin-api-server/internal/data/entity"
+
+	"gorm.io/gorm"
+)
+
+// ShadowConfusionCell adalah jumlah diagnosis untuk satu pasangan kategori risiko
+// model utama (baris) dan model shadow (kolom)
+type ShadowConfusionCell struct {
+	PrimaryCardiovascularRisk string `json:"primaryCardiovascularRisk"`
+	ShadowCardiovascularRisk  string `json:"shadowCardiovascularRisk"`
+	Count                     int64  `json:"count"`
+}
+
+// ShadowSummary adalah perbandingan model utama dan shadow untuk satu versi shadow
+type ShadowSummary struct {
+	Compared               int64                 `json:"compared"`
+	Agreements             int64                 `json:"agreements"` // kategori risiko sama
+	MeanAbsoluteDifference float64               `json:"meanAbsoluteDifference"`
+	ConfusionMatrix        []ShadowConfusionCell `gorm:"-" json:"confusionMatrix"`
+}
+
+type ShadowRepository interface {
+	Create(ctx context.Context, prediction *entity.ShadowPrediction) error
+	Summarize(ctx context.Context, modelVersion string) (*ShadowSummary, error)
+}
+
+type shadowRepository struct {
+	db *gorm.DB
+}
+
+func NewShadowRepository(db *gorm.DB) ShadowRepository {
+	return &shadowRepository{
+		db: db,
+	}
+}
+
+func (r *shadowRepository) Create(ctx context.Context, prediction *entity.ShadowPrediction) error {
+	return r.db.WithContext(ctx).Create(prediction).Error
+}
+
+// Summarize menghitung ringkasan perbandingan; modelVersion kosong berarti semua versi shadow
+func (r *shadowRepository) Summarize(ctx context.Context, modelVersion string) (*ShadowSummary, error) {
+	scope := func(db *gorm.DB) *gorm.DB {
+		db = db.Model(&entity.ShadowPrediction{})
+		if modelVersion != "" {
+			db = db.Where("model_version = ?", modelVersion)
+		}
+		return db
+	}
+
+	var summary ShadowSummary
+	err := r.db.WithContext(ctx).
+		Scopes(scope).
+		Select(`COUNT(*) AS compared,
+			COUNT(*) FILTER (WHERE cardiovascular_risk = primary_cardiovascular_risk) AS agreements,
+			COALESCE(AVG(ABS(result_percentage - primary_result_percentage)), 0) AS mean_absolute_difference`).
+		Scan(&summary).Error
+	if err != nil {
+		return nil, err
+	}
+
+	err = r.db.WithContext(ctx).
+		Scopes(scope).
+		Select(`primary_cardiovascular_risk,
+			cardiovascular_risk AS shadow_cardiovascular_risk,
+			COUNT(*) AS count`).
+		Group("primary_cardiovascular_risk, cardiovascular_risk").
+		Order("primary_cardiovascular_risk, cardiovascular_risk").
+		Scan(&summary.ConfusionMatrix).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return &summary, nil
+}
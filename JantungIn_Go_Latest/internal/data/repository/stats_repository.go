@@ -15,7 +15,7 @@ type DailyVisit struct {
 }
 
 type StatsRepository interface {
-	InsertRequestLog(ctx context.Context, log *entity.RequestLog) error
+	InsertRequestLogs(ctx context.Context, logs []*entity.RequestLog) error
 	CountTotalVisits(ctx context.Context) (int64, error)
 	CountTodayVisits(ctx context.Context) (int64, error)
 	CountMonthlyVisits(ctx context.Context) (int64, error)
@@ -33,8 +33,12 @@ func NewStatsRepository(db *gorm.DB) StatsRepository {
 	return &statsRepository{db: db}
 }
 
-func (r *statsRepository) InsertRequestLog(ctx context.Context, log *entity.RequestLog) error {
-	return r.db.WithContext(ctx).Create(log).Error
+// InsertRequestLogs menulis semua log dalam satu INSERT multi-row
+func (r *statsRepository) InsertRequestLogs(ctx context.Context, logs []*entity.RequestLog) error {
+	if len(logs) == 0 {
+		return nil
+	}
+	return r.db.WithContext(ctx).Create(&logs).Error
 }
 
 func (r *statsRepository) CountTotalVisits(ctx context.Context) (int64, error) {
@@ -78,7 +82,8 @@ func (r *statsRepository) CountTotalDoctors(ctx context.Context) (int64, error)
 
 func (r *statsRepository) CountTotalDiagnoses(ctx context.Context) (int64, error) {
 	var count int64
-	err := r.db.WithContext(ctx).Table("diagnoses").Count(&count).Error
+	// Pakai Model agar diagnosis yang sudah di-soft delete tidak ikut dihitung
+	err := r.db.WithContext(ctx).Model(&entity.Diagnosis{}).Count(&count).Error
 	return count, err
 }
 
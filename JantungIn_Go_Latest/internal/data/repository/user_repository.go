@@ -4,6 +4,8 @@ import (
 	"context"
 	"errors"
 	"jantungin-api-server/internal/data/entity"
+	"jantungin-api-server/pkg/fieldcrypt"
+	"strings"
 
 	"github.com/google/uuid"
 	"gorm.io/gorm"
@@ -14,6 +16,7 @@ type UserRepository interface {
 	FindByUsername(ctx context.Context, username string) (*entity.User, error)
 	FindByID(ctx context.Context, id uuid.UUID) (*entity.User, error)
 	FindByEmail(ctx context.Context, email string) (*entity.User, error)
+	FindByNIK(ctx context.Context, nik string) (*entity.User, error)
 	FindAll(ctx context.Context) ([]entity.User, error)
 	FindAllByRole(ctx context.Context, role string) ([]entity.User, error)
 	SearchByName(ctx context.Context, query string) ([]entity.User, error)
@@ -58,9 +61,38 @@ func (r *userRepository) FindByID(ctx context.Context, id uuid.UUID) (*entity.Us
 	return &user, nil
 }
 
+// FindByEmail mencari lewat blind index karena kolom email terenkripsi (tidak peka huruf besar/kecil).
+// User dari sebelum migrasi 000016 belum punya email_index sampai `encryption reencrypt`
+// dijalankan; email mereka masih plaintext sehingga dicocokkan langsung.
 func (r *userRepository) FindByEmail(ctx context.Context, email string) (*entity.User, error) {
+	index := fieldcrypt.BlindIndex(&email)
+	if index == nil {
+		return nil, nil
+	}
+
+	var user entity.User
+	err := r.db.WithContext(ctx).
+		Where("email_index = ?", *index).
+		Or("email_index IS NULL AND LOWER(TRIM(email)) = ?", strings.ToLower(strings.TrimSpace(email))).
+		First(&user).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &user, nil
+}
+
+// FindByNIK mencari lewat blind index karena kolom nik terenkripsi. nik harus sudah dinormalisasi.
+func (r *userRepository) FindByNIK(ctx context.Context, nik string) (*entity.User, error) {
+	index := fieldcrypt.BlindIndex(&nik)
+	if index == nil {
+		return nil, nil
+	}
+
 	var user entity.User
-	err := r.db.WithContext(ctx).Where("email = ?", email).First(&user).Error
+	err := r.db.WithContext(ctx).Where("nik_index = ?", *index).First(&user).Error
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, nil
@@ -0,0 +1,298 @@
+package seed
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"jantungin-api-server/internal/data/entity"
+
+	"github.com/google/uuid"
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Jenis data fixture, dipakai untuk memilih sebagian data (seed -only)
+const (
+	KindAdmins    = "admins"
+	KindDoctors   = "doctors"
+	KindPatients  = "patients"
+	KindDiagnoses = "diagnoses"
+	KindDevices   = "devices"
+)
+
+// Kinds berurutan menurut dependensi: diagnosis dan device membutuhkan user
+var Kinds = []string{KindAdmins, KindDoctors, KindPatients, KindDiagnoses, KindDevices}
+
+var roleKinds = map[string]string{
+	"admin":  KindAdmins,
+	"dokter": KindDoctors,
+	"user":   KindPatients,
+}
+
+const insertBatchSize = 100
+
+// KindResult ringkasan Apply untuk satu jenis data
+type KindResult struct {
+	Kind     string `json:"kind"`
+	Inserted int    `json:"inserted"`
+	Skipped  int    `json:"skipped"` // sudah ada (natural key sama)
+	Missing  int    `json:"missing"` // user yang dirujuk tidak ada di database
+}
+
+type ApplyOptions struct {
+	Only []string // kosong berarti semua Kinds
+}
+
+func (o ApplyOptions) includes(kind string) bool {
+	return len(o.Only) == 0 || slices.Contains(o.Only, kind)
+}
+
+// Apply menyisipkan fixture dalam satu transaksi. Baris yang natural key-nya sudah ada
+// dilewati (ON CONFLICT DO NOTHING) dan tidak diubah, sehingga Apply aman diulang.
+func Apply(ctx context.Context, db *gorm.DB, fixture *Fixture, opts ApplyOptions) ([]KindResult, error) {
+	for _, kind := range opts.Only {
+		if !slices.Contains(Kinds, kind) {
+			return nil, fmt.Errorf("unknown fixture kind %q", kind)
+		}
+	}
+
+	var results []KindResult
+	err := db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		hashes := make(map[string]string)
+		for _, kind := range []string{KindAdmins, KindDoctors, KindPatients} {
+			if !opts.includes(kind) {
+				continue
+			}
+			result, err := applyUsers(tx, fixture.Users, kind, hashes)
+			if err != nil {
+				return err
+			}
+			results = append(results, result)
+		}
+
+		if !opts.includes(KindDiagnoses) && !opts.includes(KindDevices) {
+			return nil
+		}
+		userIDs, err := resolveUsernames(tx, fixture)
+		if err != nil {
+			return err
+		}
+
+		if opts.includes(KindDiagnoses) {
+			result, err := applyDiagnoses(tx, fixture.Diagnoses, userIDs)
+			if err != nil {
+				return err
+			}
+			results = append(results, result)
+		}
+		if opts.includes(KindDevices) {
+			result, err := applyDevices(tx, fixture.Devices, userIDs)
+			if err != nil {
+				return err
+			}
+			results = append(results, result)
+		}
+		return nil
+	})
+	return results, err
+}
+
+func applyUsers(tx *gorm.DB, users []FixtureUser, kind string, hashes map[string]string) (KindResult, error) {
+	result := KindResult{Kind: kind}
+
+	var rows []entity.User
+	for _, u := range users {
+		if roleKinds[u.Role] != kind {
+			continue
+		}
+
+		hash := u.PasswordHash
+		if hash == "" {
+			if u.Password == "" {
+				return result, fmt.Errorf("user %s has no password or passwordHash", u.Username)
+			}
+			// bcrypt lambat; fixture hasil Generate memakai satu password per role
+			if hashes[u.Password] == "" {
+				hashed, err := bcrypt.GenerateFromPassword([]byte(u.Password), bcrypt.DefaultCost)
+				if err != nil {
+					return result, fmt.Errorf("failed to hash password: %w", err)
+				}
+				hashes[u.Password] = string(hashed)
+			}
+			hash = hashes[u.Password]
+		}
+
+		var dob *time.Time
+		if u.DateOfBirth != "" {
+			parsed, err := time.Parse(dateLayout, u.DateOfBirth)
+			if err != nil {
+				return result, fmt.Errorf("user %s: invalid dateOfBirth %q", u.Username, u.DateOfBirth)
+			}
+			dob = &parsed
+		}
+
+		username := u.Username
+		rows = append(rows, entity.User{
+			Name:        u.Name,
+			Username:    &username,
+			Email:       u.Email,
+			NIK:         u.NIK,
+			Password:    hash,
+			Role:        u.Role,
+			DateOfBirth: dob,
+		})
+	}
+	if len(rows) == 0 {
+		return result, nil
+	}
+
+	// Konflik username, email, atau NIK berarti user sudah ada
+	res := tx.Clauses(clause.OnConflict{DoNothing: true}).CreateInBatches(&rows, insertBatchSize)
+	if res.Error != nil {
+		return result, fmt.Errorf("failed to insert %s: %w", kind, res.Error)
+	}
+	result.Inserted = int(res.RowsAffected)
+	result.Skipped = len(rows) - result.Inserted
+	return result, nil
+}
+
+// resolveUsernames memetakan username yang dirujuk diagnosis/device ke ID user di database
+func resolveUsernames(tx *gorm.DB, fixture *Fixture) (map[string]uuid.UUID, error) {
+	var usernames []string
+	for _, d := range fixture.Diagnoses {
+		usernames = append(usernames, d.Patient)
+		if d.CreatedBy != "" {
+			usernames = append(usernames, d.CreatedBy)
+		}
+		if d.ReviewedBy != "" {
+			usernames = append(usernames, d.ReviewedBy)
+		}
+	}
+	for _, d := range fixture.Devices {
+		usernames = append(usernames, d.User)
+	}
+	slices.Sort(usernames)
+	usernames = slices.Compact(usernames)
+
+	ids := make(map[string]uuid.UUID, len(usernames))
+	for chunk := range slices.Chunk(usernames, 1000) {
+		var rows []struct {
+			ID       uuid.UUID
+			Username string
+		}
+		if err := tx.Model(&entity.User{}).Select("id", "username").Where("username IN ?", chunk).Scan(&rows).Error; err != nil {
+			return nil, fmt.Errorf("failed to resolve usernames: %w", err)
+		}
+		for _, row := range rows {
+			ids[row.Username] = row.ID
+		}
+	}
+	return ids, nil
+}
+
+func applyDiagnoses(tx *gorm.DB, diagnoses []FixtureDiagnosis, userIDs map[string]uuid.UUID) (KindResult, error) {
+	result := KindResult{Kind: KindDiagnoses}
+
+	optionalID := func(username string) (*uuid.UUID, bool) {
+		if username == "" {
+			return nil, true
+		}
+		id, ok := userIDs[username]
+		return &id, ok
+	}
+
+	var rows []entity.Diagnosis
+	for _, d := range diagnoses {
+		if d.ID == uuid.Nil {
+			return result, fmt.Errorf("diagnosis for patient %s has no id", d.Patient)
+		}
+		patientID, ok := userIDs[d.Patient]
+		createdBy, creatorOK := optionalID(d.CreatedBy)
+		reviewedBy, reviewerOK := optionalID(d.ReviewedBy)
+		if !ok || !creatorOK || !reviewerOK {
+			result.Missing++
+			continue
+		}
+
+		rows = append(rows, entity.Diagnosis{
+			ID:                    d.ID,
+			UserID:                patientID,
+			CreatedBy:             createdBy,
+			Age:                   d.Age,
+			Sex:                   d.Sex,
+			ChestPainType:         d.ChestPainType,
+			RestingEcgResults:     d.RestingEcgResults,
+			FastingBloodSugar:     d.FastingBloodSugar,
+			RestingBloodPressure:  d.RestingBloodPressure,
+			MaximumHeartRate:      d.MaximumHeartRate,
+			ExerciseInducedAngina: d.ExerciseInducedAngina,
+			StSegment:             d.StSegment,
+			MajorVessels:          d.MajorVessels,
+			Thalassemia:           d.Thalassemia,
+			SerumCholesterol:      d.SerumCholesterol,
+			StDepression:          d.StDepression,
+			ResultPercentage:      d.ResultPercentage,
+			CardiovascularRisk:    d.CardiovascularRisk,
+			Prediction:            d.Prediction,
+			ModelVersion:          d.ModelVersion,
+			ClinicalNotes:         d.ClinicalNotes,
+			Status:                d.Status,
+			ReviewedBy:            reviewedBy,
+			ReviewedAt:            d.ReviewedAt,
+			ReviewNotes:           d.ReviewNotes,
+			CreatedAt:             d.CreatedAt,
+			UpdatedAt:             d.CreatedAt,
+		})
+	}
+	if len(rows) == 0 {
+		return result, nil
+	}
+
+	res := tx.Clauses(clause.OnConflict{DoNothing: true}).CreateInBatches(&rows, insertBatchSize)
+	if res.Error != nil {
+		return result, fmt.Errorf("failed to insert diagnoses: %w", res.Error)
+	}
+	result.Inserted = int(res.RowsAffected)
+	result.Skipped = len(rows) - result.Inserted
+	return result, nil
+}
+
+func applyDevices(tx *gorm.DB, devices []FixtureUserDevice, userIDs map[string]uuid.UUID) (KindResult, error) {
+	result := KindResult{Kind: KindDevices}
+
+	var rows []entity.UserDevice
+	for _, d := range devices {
+		if d.ID == uuid.Nil {
+			return result, fmt.Errorf("device for user %s has no id", d.User)
+		}
+		userID, ok := userIDs[d.User]
+		if !ok {
+			result.Missing++
+			continue
+		}
+		rows = append(rows, entity.UserDevice{
+			ID:                d.ID,
+			UserID:            userID,
+			UserAgent:         d.UserAgent,
+			IPAddress:         d.IPAddress,
+			DeviceFingerprint: d.DeviceFingerprint,
+			LastLogin:         d.LastLogin,
+			CreatedAt:         d.LastLogin,
+			UpdatedAt:         d.LastLogin,
+		})
+	}
+	if len(rows) == 0 {
+		return result, nil
+	}
+
+	res := tx.Clauses(clause.OnConflict{DoNothing: true}).CreateInBatches(&rows, insertBatchSize)
+	if res.Error != nil {
+		return result, fmt.Errorf("failed to insert devices: %w", res.Error)
+	}
+	result.Inserted = int(res.RowsAffected)
+	result.Skipped = len(rows) - result.Inserted
+	return result, nil
+}
@@ -0,0 +1,104 @@
+package seed
+
+import (
+	"context"
+	"fmt"
+
+	"jantungin-api-server/internal/data/entity"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Export membaca user, diagnosis (yang tidak dihapus), dan device dari database menjadi
+// fixture. Kolom terenkripsi didekripsi oleh serializer; password hanya berupa hash.
+// Hasilnya bisa diimpor dengan Apply ke database lain, mis. untuk test.
+func Export(ctx context.Context, db *gorm.DB) (*Fixture, error) {
+	db = db.WithContext(ctx)
+	fixture := &Fixture{
+		Version:   FixtureVersion,
+		Users:     []FixtureUser{},
+		Diagnoses: []FixtureDiagnosis{},
+		Devices:   []FixtureUserDevice{},
+	}
+
+	var users []entity.User
+	if err := db.Order("username").Find(&users).Error; err != nil {
+		return nil, fmt.Errorf("failed to read users: %w", err)
+	}
+	usernames := make(map[uuid.UUID]string, len(users))
+	for _, u := range users {
+		usernames[u.ID] = *u.Username
+
+		exported := FixtureUser{
+			Username:     *u.Username,
+			Name:         u.Name,
+			Email:        u.Email,
+			NIK:          u.NIK,
+			Role:         u.Role,
+			PasswordHash: u.Password,
+		}
+		if u.DateOfBirth != nil {
+			exported.DateOfBirth = u.DateOfBirth.Format(dateLayout)
+		}
+		fixture.Users = append(fixture.Users, exported)
+	}
+
+	usernameOf := func(id *uuid.UUID) string {
+		if id == nil {
+			return ""
+		}
+		return usernames[*id]
+	}
+
+	var diagnoses []entity.Diagnosis
+	if err := db.Order("created_at, id").Find(&diagnoses).Error; err != nil {
+		return nil, fmt.Errorf("failed to read diagnoses: %w", err)
+	}
+	for _, d := range diagnoses {
+		fixture.Diagnoses = append(fixture.Diagnoses, FixtureDiagnosis{
+			ID:                    d.ID,
+			Patient:               usernames[d.UserID],
+			CreatedBy:             usernameOf(d.CreatedBy),
+			CreatedAt:             d.CreatedAt,
+			Age:                   d.Age,
+			Sex:                   d.Sex,
+			ChestPainType:         d.ChestPainType,
+			RestingEcgResults:     d.RestingEcgResults,
+			FastingBloodSugar:     d.FastingBloodSugar,
+			RestingBloodPressure:  d.RestingBloodPressure,
+			MaximumHeartRate:      d.MaximumHeartRate,
+			ExerciseInducedAngina: d.ExerciseInducedAngina,
+			StSegment:             d.StSegment,
+			MajorVessels:          d.MajorVessels,
+			Thalassemia:           d.Thalassemia,
+			SerumCholesterol:      d.SerumCholesterol,
+			StDepression:          d.StDepression,
+			ResultPercentage:      d.ResultPercentage,
+			CardiovascularRisk:    d.CardiovascularRisk,
+			Prediction:            d.Prediction,
+			ModelVersion:          d.ModelVersion,
+			ClinicalNotes:         d.ClinicalNotes,
+			Status:                d.Status,
+			ReviewedBy:            usernameOf(d.ReviewedBy),
+			ReviewedAt:            d.ReviewedAt,
+			ReviewNotes:           d.ReviewNotes,
+		})
+	}
+
+	var devices []entity.UserDevice
+	if err := db.Order("user_id, created_at, id").Find(&devices).Error; err != nil {
+		return nil, fmt.Errorf("failed to read devices: %w", err)
+	}
+	for _, d := range devices {
+		fixture.Devices = append(fixture.Devices, FixtureUserDevice{
+			ID:                d.ID,
+			User:              usernames[d.UserID],
+			UserAgent:         d.UserAgent,
+			IPAddress:         d.IPAddress,
+			DeviceFingerprint: d.DeviceFingerprint,
+			LastLogin:         d.LastLogin,
+		})
+	}
+	return fixture, nil
+}
@@ -0,0 +1,102 @@
+package seed
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// FixtureVersion dinaikkan jika format fixture berubah tidak kompatibel
+const FixtureVersion = 1
+
+// Fixture adalah kumpulan data dalam bentuk plaintext yang bisa disimpan sebagai JSON.
+// Relasi memakai username, bukan ID user, agar fixture bisa diimpor ke database lain.
+type Fixture struct {
+	Version   int                 `json:"version"`
+	Scenario  *Scenario           `json:"scenario,omitempty"` // nil untuk fixture hasil ekspor
+	Users     []FixtureUser       `json:"users"`
+	Diagnoses []FixtureDiagnosis  `json:"diagnoses"`
+	Devices   []FixtureUserDevice `json:"devices"`
+}
+
+// FixtureUser dikenali dari Username. Password berisi plaintext untuk fixture hasil
+// Generate; fixture hasil ekspor hanya membawa PasswordHash.
+type FixtureUser struct {
+	Username     string  `json:"username"`
+	Name         string  `json:"name"`
+	Email        *string `json:"email,omitempty"`
+	NIK          *string `json:"nik,omitempty"`
+	Role         string  `json:"role"`
+	DateOfBirth  string  `json:"dateOfBirth,omitempty"` // YYYY-MM-DD
+	Password     string  `json:"password,omitempty"`
+	PasswordHash string  `json:"passwordHash,omitempty"`
+}
+
+type FixtureDiagnosis struct {
+	ID        uuid.UUID `json:"id"`
+	Patient   string    `json:"patient"`             // username pasien
+	CreatedBy string    `json:"createdBy,omitempty"` // username dokter
+	CreatedAt time.Time `json:"createdAt"`
+
+	Age                   int     `json:"age"`
+	Sex                   string  `json:"sex"`
+	ChestPainType         string  `json:"chestPainType"`
+	RestingEcgResults     string  `json:"restingEcgResults"`
+	FastingBloodSugar     float64 `json:"fastingBloodSugar"`
+	RestingBloodPressure  float64 `json:"restingBloodPressure"`
+	MaximumHeartRate      int     `json:"maximumHeartRate"`
+	ExerciseInducedAngina string  `json:"exerciseInducedAngina"`
+	StSegment             string  `json:"stSegment"`
+	MajorVessels          int     `json:"majorVessels"`
+	Thalassemia           string  `json:"thalassemia"`
+	SerumCholesterol      float64 `json:"serumCholesterol"`
+	StDepression          float64 `json:"stDepression"`
+
+	ResultPercentage   float64 `json:"resultPercentage"`
+	CardiovascularRisk string  `json:"cardiovascularRisk"`
+	Prediction         string  `json:"prediction"`
+	ModelVersion       string  `json:"modelVersion,omitempty"`
+	ClinicalNotes      string  `json:"clinicalNotes,omitempty"`
+
+	// Status review; kosong berarti draft
+	Status      string     `json:"status,omitempty"`
+	ReviewedBy  string     `json:"reviewedBy,omitempty"` // username reviewer
+	ReviewedAt  *time.Time `json:"reviewedAt,omitempty"`
+	ReviewNotes string     `json:"reviewNotes,omitempty"`
+}
+
+type FixtureUserDevice struct {
+	ID                uuid.UUID `json:"id"`
+	User              string    `json:"user"` // username
+	UserAgent         string    `json:"userAgent"`
+	IPAddress         string    `json:"ipAddress"`
+	DeviceFingerprint string    `json:"deviceFingerprint"`
+	LastLogin         time.Time `json:"lastLogin"`
+}
+
+func ReadFixture(path string) (*Fixture, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var fixture Fixture
+	if err := json.Unmarshal(content, &fixture); err != nil {
+		return nil, fmt.Errorf("invalid fixture %s: %w", path, err)
+	}
+	if fixture.Version != FixtureVersion {
+		return nil, fmt.Errorf("unsupported fixture version %d, expected %d", fixture.Version, FixtureVersion)
+	}
+	return &fixture, nil
+}
+
+func (f *Fixture) WriteFile(path string) error {
+	content, err := json.MarshalIndent(f, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(content, '\n'), 0o600)
+}
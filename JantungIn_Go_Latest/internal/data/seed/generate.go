@@ -0,0 +1,235 @@
+package seed
+
+import (
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"strings"
+	"time"
+
+	"jantungin-api-server/internal/vocabulary"
+	"jantungin-api-server/pkg/utils"
+
+	"github.com/google/uuid"
+)
+
+// idNamespace dipakai untuk menurunkan UUID v5 diagnosis dan device dari natural key-nya
+var idNamespace = uuid.MustParse("5b0f6f0e-3c52-4d8e-9a57-4f1d2c7e9b31")
+
+const (
+	doctorPassword  = "dokter123"
+	patientPassword = "pasien123"
+	modelVersion    = "seed"
+)
+
+// Urutan tetap agar pilihan kategori tidak bergantung urutan iterasi map
+var riskCategories = []vocabulary.RiskCategory{vocabulary.RiskCategoryHigh, vocabulary.RiskCategoryLow}
+
+// Generate membuat fixture dari scenario. Hasilnya hanya bergantung pada isi scenario:
+// user dibuat dari index, sedangkan diagnosis dan device memakai stream acak terpisah
+// dari Scenario.Seed sehingga mengubah jumlah dokter tidak menggeser data pasien.
+func Generate(s Scenario) (*Fixture, error) {
+	if err := s.Validate(); err != nil {
+		return nil, err
+	}
+	ref, _ := s.referenceTime()
+
+	fixture := &Fixture{Version: FixtureVersion, Scenario: &s}
+
+	doctors := make([]FixtureUser, 0, s.Doctors)
+	for i := range s.Doctors {
+		doctors = append(doctors, doctorAt(i))
+	}
+	patients := make([]FixtureUser, 0, s.Patients)
+	for i := range s.Patients {
+		patients = append(patients, patientAt(i, ref))
+	}
+	fixture.Users = append(doctors, patients...)
+
+	diagnosisRand := rand.New(rand.NewPCG(s.Seed, 1))
+	for i := range s.Diagnoses {
+		fixture.Diagnoses = append(fixture.Diagnoses, generateDiagnosis(diagnosisRand, s, ref, i, doctors, patients))
+	}
+
+	deviceRand := rand.New(rand.NewPCG(s.Seed, 2))
+	for _, patient := range patients {
+		fixture.Devices = append(fixture.Devices, generateDevices(deviceRand, ref, patient.Username)...)
+	}
+	return fixture, nil
+}
+
+// doctorAt memakai daftar doctorSeeds, lalu kombinasi nama pasien jika jumlahnya lebih banyak
+func doctorAt(i int) FixtureUser {
+	var name, email string
+	if i < len(doctorSeeds) {
+		name, email = doctorSeeds[i].name, doctorSeeds[i].email
+	} else {
+		first := patientFirstNames[i%len(patientFirstNames)]
+		last := patientLastNames[(i/len(patientFirstNames)+i)%len(patientLastNames)]
+		name = first + " " + last
+		email = fmt.Sprintf("dr.%s%s%d@jantungin.com", strings.ToLower(first), strings.ToLower(last), i+1)
+	}
+
+	return FixtureUser{
+		Username:    strings.Split(email, "@")[0],
+		Name:        "dr. " + name,
+		Email:       &email,
+		Role:        "dokter",
+		DateOfBirth: birthDateFromIndex(i).Format(dateLayout),
+		Password:    doctorPassword,
+	}
+}
+
+func patientAt(i int, ref time.Time) FixtureUser {
+	firstName := patientFirstNames[i%len(patientFirstNames)]
+	lastName := patientLastNames[i%len(patientLastNames)]
+
+	domain := patientEmailDomains[i%len(patientEmailDomains)]
+	username := fmt.Sprintf("%s.%s%d", strings.ToLower(firstName), strings.ToLower(lastName), i+1)
+	email := fmt.Sprintf("%s@%s", username, domain)
+
+	return FixtureUser{
+		Username:    username,
+		Name:        firstName + " " + lastName,
+		Email:       &email,
+		Role:        "user",
+		DateOfBirth: ref.AddDate(-generateAge(i), 0, 0).Format(dateLayout),
+		Password:    patientPassword,
+	}
+}
+
+func generateDiagnosis(rng *rand.Rand, s Scenario, ref time.Time, i int, doctors, patients []FixtureUser) FixtureDiagnosis {
+	patient := patients[rng.IntN(len(patients))]
+
+	var createdBy string
+	if len(doctors) > 0 {
+		createdBy = doctors[rng.IntN(len(doctors))].Username
+	}
+
+	// Tersebar dalam DateSpreadDays hari sebelum tanggal acuan, jam acak
+	daysAgo := rng.IntN(max(s.DateSpreadDays, 1)) + 1
+	createdAt := ref.AddDate(0, 0, -daysAgo).Add(time.Duration(rng.IntN(24*60)) * time.Minute)
+
+	dob, _ := time.Parse(dateLayout, patient.DateOfBirth)
+	age := int(createdAt.Sub(dob).Hours() / 24 / 365)
+
+	sex := string(vocabulary.SexMale)
+	if rng.Float32() > 0.5 {
+		sex = string(vocabulary.SexFemale)
+	}
+
+	// Pakai nilai kanonik vokabulari agar data seed bisa langsung dikirim ke ML service
+	chestPainTypes := vocabulary.ChestPainTypeField.Allowed()
+	restingEcgResults := vocabulary.RestingEcgField.Allowed()
+	exerciseAngina := vocabulary.ExerciseAnginaField.Allowed()
+	stSegments := vocabulary.StSlopeField.Allowed()
+	thalassemiaTypes := vocabulary.ThalassemiaField.Allowed()
+
+	risk := pickRisk(rng, s.RiskDistribution)
+	resultPercentage := 5 + rng.Float64()*44
+	prediction := "Tidak Berisiko"
+	if risk == vocabulary.RiskCategoryHigh {
+		resultPercentage = 50 + rng.Float64()*49
+		prediction = "Berisiko"
+	}
+
+	return FixtureDiagnosis{
+		ID:                    uuid.NewSHA1(idNamespace, fmt.Appendf(nil, "%s/%d/diagnosis/%d", s.Name, s.Seed, i)),
+		Patient:               patient.Username,
+		CreatedBy:             createdBy,
+		CreatedAt:             createdAt,
+		Age:                   age,
+		Sex:                   sex,
+		ChestPainType:         chestPainTypes[rng.IntN(len(chestPainTypes))],
+		RestingEcgResults:     restingEcgResults[rng.IntN(len(restingEcgResults))],
+		FastingBloodSugar:     float64(rng.IntN(150) + 70),
+		RestingBloodPressure:  float64(rng.IntN(80) + 90),
+		MaximumHeartRate:      rng.IntN(100) + 80,
+		ExerciseInducedAngina: exerciseAngina[rng.IntN(len(exerciseAngina))],
+		StSegment:             stSegments[rng.IntN(len(stSegments))],
+		MajorVessels:          rng.IntN(4),
+		Thalassemia:           thalassemiaTypes[rng.IntN(len(thalassemiaTypes))],
+		SerumCholesterol:      float64(rng.IntN(200) + 120),
+		StDepression:          round2(rng.Float64() * 4),
+		ResultPercentage:      round2(resultPercentage),
+		CardiovascularRisk:    string(risk),
+		Prediction:            prediction,
+		ModelVersion:          modelVersion,
+	}
+}
+
+func pickRisk(rng *rand.Rand, distribution map[string]float64) vocabulary.RiskCategory {
+	total := 0.0
+	for _, category := range riskCategories {
+		total += distribution[string(category)]
+	}
+
+	point := rng.Float64() * total
+	for _, category := range riskCategories {
+		weight := distribution[string(category)]
+		if weight > 0 && point < weight {
+			return category
+		}
+		point -= weight
+	}
+	return vocabulary.RiskCategoryLow
+}
+
+// generateDevices: 70% user punya 1 device, 20% punya 2, 10% punya 3
+func generateDevices(rng *rand.Rand, ref time.Time, username string) []FixtureUserDevice {
+	numDevices := 1
+	prob := rng.Float32()
+	if prob > 0.90 {
+		numDevices = 3
+	} else if prob > 0.70 {
+		numDevices = 2
+	}
+
+	devices := make([]FixtureUserDevice, 0, numDevices)
+	seen := make(map[string]bool)
+	for range numDevices {
+		ua := devicePool[rng.IntN(len(devicePool))]
+		ip := fmt.Sprintf("%s.%d", ipPool[rng.IntN(len(ipPool))], rng.IntN(254)+1)
+		lastLogin := ref.Add(-time.Duration(rng.IntN(720)) * time.Hour)
+
+		// Fingerprint sama dengan yang dihitung saat login
+		fingerprint := utils.GenerateDeviceFingerprint(ua, ip)
+		if seen[fingerprint] {
+			continue
+		}
+		seen[fingerprint] = true
+
+		devices = append(devices, FixtureUserDevice{
+			ID:                uuid.NewSHA1(idNamespace, []byte("device/"+username+"/"+fingerprint)),
+			User:              username,
+			UserAgent:         ua,
+			IPAddress:         ip,
+			DeviceFingerprint: fingerprint,
+			LastLogin:         lastLogin,
+		})
+	}
+	return devices
+}
+
+// birthDateFromIndex menghasilkan tanggal lahir yang bervariasi dari index
+func birthDateFromIndex(i int) time.Time {
+	year := 1965 + (i % 25)
+	month := time.Month(1 + (i*3)%12)
+	day := 1 + (i*7)%28
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+// generateAge: mayoritas 25-30, range 20-55
+func generateAge(index int) int {
+	if index%10 < 6 {
+		return 25 + (index % 6)
+	}
+	if index%10 < 8 {
+		return 20 + (index % 5)
+	}
+	return 31 + (index % 25)
+}
+
+func round2(v float64) float64 {
+	return math.Round(v*100) / 100
+}
@@ -0,0 +1,107 @@
+package seed
+
+// doctorSeeds berisi data dokter dummy dengan variasi nama 1, 2, dan 3 kata
+var doctorSeeds = []struct {
+	name  string
+	email string
+}{
+	// 3 kata
+	{"Ahmad Fauzi Pratama", "dr.ahmadfauzipratama@jantungin.com"},
+	{"Budi Santoso Wibowo", "dr.budisantosowibowo@jantungin.com"},
+	{"Citra Dewi Anggraeni", "dr.citradewi.anggraeni@jantungin.com"},
+	{"Dian Purnama Sari", "dr.dianpurnamasari@jantungin.com"},
+	{"Eko Prasetyo Nugroho", "dr.ekoprasetyo.nugroho@jantungin.com"},
+	{"Fajar Nugroho Santoso", "dr.fajarnugrohosantoso@jantungin.com"},
+	{"Hendra Wijaya Kusuma", "dr.hendrawijayakusuma@jantungin.com"},
+	{"Indah Lestari Putri", "dr.indahlestariputri@jantungin.com"},
+	{"Kartini Sari Dewi", "dr.kartinisaridewi@jantungin.com"},
+	{"Lukman Hakim Siregar", "dr.lukmanhakimsiregar@jantungin.com"},
+	{"Maya Anggraeni Putri", "dr.mayaanggraeniputri@jantungin.com"},
+	{"Nanda Pratama Putra", "dr.nandapratamamputra@jantungin.com"},
+	{"Rizky Maulana Akbar", "dr.rizkymaulanaakbar@jantungin.com"},
+	{"Sari Wulandari Ningrum", "dr.sariwulandari.ningrum@jantungin.com"},
+	{"Teguh Santoso Prabowo", "dr.teguhsantosoprabowo@jantungin.com"},
+	{"Wahyu Hidayat Saputra", "dr.wahyuhidayatsaputra@jantungin.com"},
+	{"Zahra Amalia Fitri", "dr.zahraamaliafitri@jantungin.com"},
+	{"Agus Kurniawan Setiawan", "dr.aguskurniawansetiawan@jantungin.com"},
+	{"Bayu Prabowo Santoso", "dr.bayuprabowosantoso@jantungin.com"},
+	{"Gilang Ramadhan Putra", "dr.gilangrmadhanputra@jantungin.com"},
+
+	// 2 kata
+	{"Gita Rahayu", "dr.gitarahayu@jantungin.com"},
+	{"Joko Susilo", "dr.jokosusilo@jantungin.com"},
+	{"Oka Setiawan", "dr.okasetiawan@jantungin.com"},
+	{"Putri Handayani", "dr.putrihandayani@jantungin.com"},
+	{"Qori Firdaus", "dr.qorifirdaus@jantungin.com"},
+	{"Umi Kalsum", "dr.umikalsum@jantungin.com"},
+	{"Vina Agustina", "dr.vinaagustina@jantungin.com"},
+	{"Xenia Priyatno", "dr.xeniapriyatno@jantungin.com"},
+	{"Yudi Hermawan", "dr.yudihermawan@jantungin.com"},
+	{"Candra Kusuma", "dr.candrakusuma@jantungin.com"},
+	{"Dewi Safitri", "dr.dewisafitri@jantungin.com"},
+	{"Endang Suryani", "dr.endangsuryani@jantungin.com"},
+	{"Firman Alamsyah", "dr.firmanalamsyah@jantungin.com"},
+	{"Hesti Pertiwi", "dr.hestipertiwi@jantungin.com"},
+	{"Ivan Kristanto", "dr.ivankristanto@jantungin.com"},
+	{"Krisna Adiputra", "dr.krisnadiputra@jantungin.com"},
+	{"Laras Setiabudi", "dr.larassetiabudi@jantungin.com"},
+	{"Niken Ayu", "dr.nikenayu@jantungin.com"},
+	{"Oscar Dermawan", "dr.oscardermawan@jantungin.com"},
+	{"Prita Kusumawati", "dr.pritakusumawati@jantungin.com"},
+	{"Silvana Maharani", "dr.silvanamaharani@jantungin.com"},
+	{"Tri Hartono", "dr.trihartono@jantungin.com"},
+	{"Vicky Ardiansyah", "dr.vickyardiansyah@jantungin.com"},
+	{"Yoga Prayitno", "dr.yogaprayitno@jantungin.com"},
+	{"Zulfan Arifin", "dr.zulfanarifin@jantungin.com"},
+
+	// 1 kata
+	{"Sukarno", "dr.sukarno@jantungin.com"},
+	{"Suharto", "dr.suharto@jantungin.com"},
+	{"Habibie", "dr.habibie@jantungin.com"},
+	{"Megawati", "dr.megawati@jantungin.com"},
+	{"Wiranto", "dr.wiranto@jantungin.com"},
+}
+
+var patientFirstNames = []string{
+	"Ahmad", "Budi", "Citra", "Dian", "Eka", "Fajar", "Gilang", "Hendra", "Indah", "Joko",
+	"Kartini", "Lukman", "Maya", "Nanda", "Okta", "Putri", "Qori", "Rini", "Siti", "Teguh",
+	"Umi", "Vina", "Wahyu", "Xenia", "Yani", "Zahra", "Agus", "Bayu", "Cahyo", "Dewi",
+	"Endang", "Firman", "Gita", "Hesti", "Ivan", "Krisna", "Laras", "Mirah", "Niken", "Oscar",
+	"Prita", "Reza", "Sari", "Tri", "Udin", "Vicky", "Wardi", "Yoga", "Zulfan", "Adinda",
+}
+
+var patientLastNames = []string{
+	"Saputra", "Wijaya", "Hermawan", "Kusuma", "Rahman", "Handoko", "Santoso", "Pranoto", "Nugroho", "Prabowo",
+	"Setiawan", "Hartono", "Gunawan", "Sutrisno", "Mulyadi", "Wibowo", "Suryadi", "Maulana", "Purwanto", "Kartini",
+	"Rahayu", "Safitri", "Susandi", "Wahyuni", "Suryani", "Pratiwi", "Samsinar", "Hadiwijaya", "Siswanto", "Ermawan",
+	"Suryana", "Sugito", "Putro", "Harsono", "Agustian", "Prayitno", "Subarno", "Budiman", "Darman", "Gunarto",
+	"Haryanto", "Ibrahim", "Jumadi", "Kustarto", "Lismanto", "Mukhlas", "Narwanto", "Oerip", "Prasetya", "Rachmat",
+}
+
+var patientEmailDomains = []string{
+	"gmail.com", "yahoo.com", "outlook.com", "hotmail.com", "icloud.com",
+}
+
+// Distribusi device populer di Indonesia (mayoritas Android)
+var devicePool = []string{
+	// Androids (Mayoritas)
+	"Mozilla/5.0 (Linux; Android 13; SM-A5350 Build/TP1A.220624.014) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Mobile Safari/537.36",
+	"Mozilla/5.0 (Linux; Android 12; Redmi Note 12 Build/S1P1R.220707.004) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Mobile Safari/537.36",
+	"Mozilla/5.0 (Linux; Android 12; CPH2347 Build/S.A.138) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Mobile Safari/537.36",
+	"Mozilla/5.0 (Linux; Android 12; V2142 Build/S.A.029) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Mobile Safari/537.36",
+	"Mozilla/5.0 (Linux; Android 11; SM-M335F Build/SP1A.220623.004) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/110.0.0.0 Mobile Safari/537.36",
+
+	// iOS
+	"Mozilla/5.0 (iPhone; CPU iPhone OS 17_2 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.2 Mobile/15E148 Safari/604.1",
+	"Mozilla/5.0 (iPad; CPU OS 17_2 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.2 Mobile/15E148 Safari/604.1",
+
+	// Windows
+	"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36",
+	"Mozilla/5.0 (Windows NT 10.0; Win64; x64; rv:121.0) Gecko/20100101 Firefox/121.0",
+}
+
+// ipPool berisi prefix /24, oktet terakhir diacak saat generate
+var ipPool = []string{
+	"192.168.1", "192.168.0", "203.0.113", "10.0.0", "172.16.0",
+	"114.124.0", "180.252.0", "120.188.0", "36.68.0", "140.213.0",
+}
@@ -0,0 +1,108 @@
+// Package seed membuat data dummy untuk development, demo, dan test.
+//
+// Data dibuat dalam dua langkah: Generate menghasilkan Fixture dari Scenario secara
+// deterministik (seed dan tanggal acuan sama menghasilkan fixture yang sama persis), lalu
+// Apply menyisipkan fixture ke database. Apply idempoten lewat natural key: user dikenali
+// dari username, diagnosis dan device dari ID yang diturunkan dari scenario, sehingga
+// menjalankan ulang seeder tidak menduplikasi atau mengubah data yang sudah ada.
+// Fixture bisa diekspor dari database dan diimpor kembali sebagai JSON.
+package seed
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"jantungin-api-server/internal/vocabulary"
+)
+
+const dateLayout = "2006-01-02"
+
+// Scenario menentukan jumlah dan sebaran data dummy. Dibaca dari file JSON;
+// field yang tidak diisi memakai nilai DefaultScenario.
+type Scenario struct {
+	Name          string `json:"name"`
+	Seed          uint64 `json:"seed"`
+	ReferenceDate string `json:"referenceDate"` // YYYY-MM-DD, pengganti "hari ini" agar hasil tetap sama
+
+	Doctors   int `json:"doctors"`
+	Patients  int `json:"patients"`
+	Diagnoses int `json:"diagnoses"`
+
+	// Bobot kategori cardiovascularRisk, mis. {"High Risk": 0.3, "Low": 0.7}
+	RiskDistribution map[string]float64 `json:"riskDistribution"`
+	// Diagnosis tersebar rata dalam DateSpreadDays hari sebelum ReferenceDate
+	DateSpreadDays int `json:"dateSpreadDays"`
+}
+
+// DefaultScenario sama dengan jumlah data seeder sebelumnya: 50 dokter, 100 pasien, 300 diagnosis
+func DefaultScenario() Scenario {
+	return Scenario{
+		Name:          "default",
+		Seed:          1,
+		ReferenceDate: "2025-01-01",
+		Doctors:       50,
+		Patients:      100,
+		Diagnoses:     300,
+		RiskDistribution: map[string]float64{
+			string(vocabulary.RiskCategoryHigh): 0.5,
+			string(vocabulary.RiskCategoryLow):  0.5,
+		},
+		DateSpreadDays: 365,
+	}
+}
+
+// LoadScenario membaca scenario dari file JSON di atas DefaultScenario
+func LoadScenario(path string) (Scenario, error) {
+	scenario := DefaultScenario()
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return scenario, err
+	}
+	// RiskDistribution dari file menggantikan default, bukan digabung
+	scenario.RiskDistribution = nil
+	if err := json.Unmarshal(content, &scenario); err != nil {
+		return scenario, fmt.Errorf("invalid scenario %s: %w", path, err)
+	}
+	if scenario.RiskDistribution == nil {
+		scenario.RiskDistribution = DefaultScenario().RiskDistribution
+	}
+	return scenario, scenario.Validate()
+}
+
+func (s Scenario) Validate() error {
+	if s.Name == "" {
+		return errors.New("scenario name is required")
+	}
+	if _, err := s.referenceTime(); err != nil {
+		return fmt.Errorf("invalid referenceDate %q, use YYYY-MM-DD", s.ReferenceDate)
+	}
+	if s.Doctors < 0 || s.Patients < 0 || s.Diagnoses < 0 || s.DateSpreadDays < 0 {
+		return errors.New("scenario counts must not be negative")
+	}
+	if s.Diagnoses > 0 && s.Patients == 0 {
+		return errors.New("scenario with diagnoses needs at least one patient")
+	}
+
+	total := 0.0
+	for category, weight := range s.RiskDistribution {
+		if category != string(vocabulary.RiskCategoryHigh) && category != string(vocabulary.RiskCategoryLow) {
+			return fmt.Errorf("unknown risk category %q, use %q or %q", category, vocabulary.RiskCategoryHigh, vocabulary.RiskCategoryLow)
+		}
+		if weight < 0 {
+			return fmt.Errorf("risk weight for %q must not be negative", category)
+		}
+		total += weight
+	}
+	if s.Diagnoses > 0 && total == 0 {
+		return errors.New("riskDistribution needs at least one positive weight")
+	}
+	return nil
+}
+
+func (s Scenario) referenceTime() (time.Time, error) {
+	return time.Parse(dateLayout, s.ReferenceDate)
+}
@@ -0,0 +1,253 @@
+// Package drift membandingkan distribusi input diagnosis terbaru dengan statistik
+// data training model (mean dan scale StandardScaler di scaler_info.json).
+//
+// scaler_info.json hanya menyimpan mean dan scale, sehingga distribusi training
+// didekati sebagai berikut untuk menghitung PSI:
+//   - fitur biner (sex, fbs, exang): Bernoulli dengan p = mean
+//   - fitur kategorikal berkode angka (cp, restecg, slope, ca, thal): normal(mean, scale)
+//     yang dipotong di titik tengah antar kode
+//   - fitur kontinu: normal(mean, scale) dengan 10 bin desil
+package drift
+
+import (
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+
+	"jantungin-api-server/internal/data/entity"
+	"jantungin-api-server/internal/vocabulary"
+)
+
+// Salinan JantungIn_ML/model/scaler_info.json, harus diperbarui bersama model
+//
+//go:embed scaler_info.json
+var scalerInfoJSON []byte
+
+// psiEpsilon mencegah log(0) untuk bin yang kosong
+const psiEpsilon = 1e-4
+
+const continuousBins = 10
+
+type kind int
+
+const (
+	kindContinuous kind = iota
+	kindBinary
+	kindDiscrete
+)
+
+type feature struct {
+	name   string
+	kind   kind
+	values []float64 // kode yang mungkin untuk kindDiscrete, urut naik
+}
+
+// features mengikuti urutan feature_names di scaler_info.json
+var features = []feature{
+	{name: "age", kind: kindContinuous},
+	{name: "sex", kind: kindBinary},
+	{name: "cp", kind: kindDiscrete, values: []float64{1, 2, 3, 4}},
+	{name: "trestbps", kind: kindContinuous},
+	{name: "chol", kind: kindContinuous},
+	{name: "fbs", kind: kindBinary},
+	{name: "restecg", kind: kindDiscrete, values: []float64{0, 1, 2}},
+	{name: "thalach", kind: kindContinuous},
+	{name: "exang", kind: kindBinary},
+	{name: "oldpeak", kind: kindContinuous},
+	{name: "slope", kind: kindDiscrete, values: []float64{1, 2, 3}},
+	{name: "ca", kind: kindDiscrete, values: []float64{0, 1, 2, 3}},
+	{name: "thal", kind: kindDiscrete, values: []float64{3, 6, 7}},
+}
+
+// Kode kategorikal sama dengan mapping di ML service (app/routes/prediction.py)
+var (
+	sexCodes         = map[string]float64{string(vocabulary.SexMale): 1, string(vocabulary.SexFemale): 0}
+	chestPainCodes   = map[string]float64{string(vocabulary.ChestPainTypical): 1, string(vocabulary.ChestPainAtypical): 2, string(vocabulary.ChestPainNonAnginal): 3, string(vocabulary.ChestPainAsymptomatic): 4}
+	restingEcgCodes  = map[string]float64{string(vocabulary.RestingEcgNormal): 0, string(vocabulary.RestingEcgSTTAbnormal): 1, string(vocabulary.RestingEcgLVH): 2}
+	exerciseCodes    = map[string]float64{string(vocabulary.ExerciseAnginaYes): 1, string(vocabulary.ExerciseAnginaNo): 0}
+	stSlopeCodes     = map[string]float64{string(vocabulary.StSlopeUpsloping): 1, string(vocabulary.StSlopeFlat): 2, string(vocabulary.StSlopeDownsloping): 3}
+	thalassemiaCodes = map[string]float64{string(vocabulary.ThalassemiaNormal): 3, string(vocabulary.ThalassemiaFixed): 6, string(vocabulary.ThalassemiaReversible): 7}
+)
+
+// Baseline adalah statistik data training per fitur
+type Baseline struct {
+	FeatureNames []string  `json:"feature_names"`
+	Mean         []float64 `json:"mean"`
+	Scale        []float64 `json:"scale"`
+}
+
+// DefaultBaseline membaca scaler_info.json yang di-embed
+func DefaultBaseline() (*Baseline, error) {
+	var b Baseline
+	if err := json.Unmarshal(scalerInfoJSON, &b); err != nil {
+		return nil, fmt.Errorf("invalid scaler_info.json: %w", err)
+	}
+	if len(b.FeatureNames) != len(features) || len(b.Mean) != len(features) || len(b.Scale) != len(features) {
+		return nil, errors.New("scaler_info.json does not contain 13 features")
+	}
+	for i, f := range features {
+		if b.FeatureNames[i] != f.name {
+			return nil, fmt.Errorf("scaler_info.json feature %d is %q, expected %q", i, b.FeatureNames[i], f.name)
+		}
+	}
+	return &b, nil
+}
+
+// Thresholds menentukan kapan satu fitur dianggap drift
+type Thresholds struct {
+	PSI    float64
+	ZScore float64
+	// MeanShift adalah besar efek minimal untuk uji rata-rata. Z-score tumbuh dengan
+	// akar jumlah sampel, sehingga pada sampel besar pergeseran yang tidak bermakna
+	// klinis pun signifikan; pergeseran baru dianggap drift jika juga >= MeanShift.
+	MeanShift float64
+}
+
+// Sample adalah nilai fitur yang terkumpul dari diagnosis, per fitur
+type Sample [][]float64
+
+// Collect mengubah diagnosis menjadi nilai fitur numerik seperti yang dikirim ke model.
+// Nilai kategorikal yang tidak dikenali (data lama) dilewati untuk fitur tersebut saja.
+func Collect(diagnoses []entity.Diagnosis) Sample {
+	sample := make(Sample, len(features))
+	add := func(i int, v float64) {
+		sample[i] = append(sample[i], v)
+	}
+	addCode := func(i int, codes map[string]float64, value string) {
+		if v, ok := codes[value]; ok {
+			add(i, v)
+		}
+	}
+
+	for _, d := range diagnoses {
+		fbs := 0.0
+		if d.FastingBloodSugar > 120 {
+			fbs = 1
+		}
+
+		add(0, float64(d.Age))
+		addCode(1, sexCodes, d.Sex)
+		addCode(2, chestPainCodes, d.ChestPainType)
+		add(3, d.RestingBloodPressure)
+		add(4, d.SerumCholesterol)
+		add(5, fbs)
+		addCode(6, restingEcgCodes, d.RestingEcgResults)
+		add(7, float64(d.MaximumHeartRate))
+		addCode(8, exerciseCodes, d.ExerciseInducedAngina)
+		add(9, d.StDepression)
+		addCode(10, stSlopeCodes, d.StSegment)
+		add(11, float64(d.MajorVessels))
+		addCode(12, thalassemiaCodes, d.Thalassemia)
+	}
+	return sample
+}
+
+// Compare menghitung drift setiap fitur. Fitur tanpa sampel dilaporkan dengan SampleSize 0.
+func Compare(b *Baseline, sample Sample, th Thresholds) []entity.DriftFeature {
+	result := make([]entity.DriftFeature, len(features))
+	for i, f := range features {
+		values := sample[i]
+		mean, scale := b.Mean[i], b.Scale[i]
+
+		fd := entity.DriftFeature{
+			Feature:       f.name,
+			SampleSize:    len(values),
+			TrainingMean:  mean,
+			TrainingScale: scale,
+		}
+		if len(values) > 0 && scale > 0 {
+			fd.SampleMean, fd.SampleStdDev = meanStdDev(values)
+			fd.MeanShift = (fd.SampleMean - mean) / scale
+			fd.ZScore = fd.MeanShift * math.Sqrt(float64(len(values)))
+			fd.PSI = psi(f, mean, scale, values)
+			meanDrifted := math.Abs(fd.ZScore) >= th.ZScore && math.Abs(fd.MeanShift) >= th.MeanShift
+			fd.Drifted = fd.PSI >= th.PSI || meanDrifted
+		}
+
+		result[i] = fd
+	}
+	return result
+}
+
+func meanStdDev(values []float64) (float64, float64) {
+	sum := 0.0
+	for _, v := range values {
+		sum += v
+	}
+	mean := sum / float64(len(values))
+
+	variance := 0.0
+	for _, v := range values {
+		variance += (v - mean) * (v - mean)
+	}
+	if len(values) > 1 {
+		variance /= float64(len(values) - 1)
+	}
+	return mean, math.Sqrt(variance)
+}
+
+// psi menghitung population stability index: Σ (aktual - ekspektasi) * ln(aktual / ekspektasi)
+func psi(f feature, mean, scale float64, values []float64) float64 {
+	var edges, expected []float64
+
+	switch f.kind {
+	case kindBinary:
+		p := math.Min(math.Max(mean, 0), 1)
+		edges = []float64{0.5}
+		expected = []float64{1 - p, p}
+	case kindDiscrete:
+		edges = make([]float64, len(f.values)-1)
+		for i := range edges {
+			edges[i] = (f.values[i] + f.values[i+1]) / 2
+		}
+		expected = normalBinProbabilities(edges, mean, scale)
+	default:
+		edges = make([]float64, continuousBins-1)
+		expected = make([]float64, continuousBins)
+		for i := range edges {
+			p := float64(i+1) / continuousBins
+			edges[i] = mean + scale*math.Sqrt2*math.Erfinv(2*p-1)
+		}
+		for i := range expected {
+			expected[i] = 1.0 / continuousBins
+		}
+	}
+
+	counts := make([]float64, len(expected))
+	for _, v := range values {
+		counts[binIndex(edges, v)]++
+	}
+
+	total := 0.0
+	for i := range expected {
+		actual := math.Max(counts[i]/float64(len(values)), psiEpsilon)
+		exp := math.Max(expected[i], psiEpsilon)
+		total += (actual - exp) * math.Log(actual/exp)
+	}
+	return total
+}
+
+// normalBinProbabilities menghitung peluang normal(mean, scale) untuk setiap bin
+// yang dibatasi edges (bin pertama dan terakhir terbuka ke -inf / +inf)
+func normalBinProbabilities(edges []float64, mean, scale float64) []float64 {
+	probs := make([]float64, len(edges)+1)
+	prev := 0.0
+	for i, e := range edges {
+		cdf := 0.5 * math.Erfc(-(e-mean)/(scale*math.Sqrt2))
+		probs[i] = cdf - prev
+		prev = cdf
+	}
+	probs[len(edges)] = 1 - prev
+	return probs
+}
+
+func binIndex(edges []float64, v float64) int {
+	for i, e := range edges {
+		if v < e {
+			return i
+		}
+	}
+	return len(edges)
+}
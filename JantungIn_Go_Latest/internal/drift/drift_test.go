@@ -0,0 +1,204 @@
+package drift
+
+import (
+	"math"
+	"testing"
+)
+
+var testThresholds = Thresholds{PSI: 0.25, ZScore: 3, MeanShift: 0.2}
+
+// normalQuantiles menghasilkan n nilai yang tersebar persis mengikuti normal(mean, scale)
+func normalQuantiles(n int, mean, scale float64) []float64 {
+	values := make([]float64, n)
+	for i := range values {
+		p := (float64(i) + 0.5) / float64(n)
+		values[i] = mean + scale*math.Sqrt2*math.Erfinv(2*p-1)
+	}
+	return values
+}
+
+func repeat(v float64, n int) []float64 {
+	values := make([]float64, n)
+	for i := range values {
+		values[i] = v
+	}
+	return values
+}
+
+func TestPSI(t *testing.T) {
+	continuous := features[0]
+	binary := features[1]
+	discrete := features[2]
+
+	// Semua nilai di satu dari 10 bin desil: 1 bin berisi 100%, 9 bin kosong (epsilon)
+	oneBin := (1-0.1)*math.Log(1/0.1) + 9*(psiEpsilon-0.1)*math.Log(psiEpsilon/0.1)
+
+	tests := []struct {
+		name   string
+		f      feature
+		mean   float64
+		scale  float64
+		values []float64
+		want   float64
+		tol    float64
+	}{
+		{name: "kontinu identik", f: continuous, mean: 54, scale: 9, values: normalQuantiles(1000, 54, 9), want: 0, tol: 1e-9},
+		{name: "kontinu semua di satu bin", f: continuous, mean: 54, scale: 9, values: repeat(54.1, 50), want: oneBin, tol: 1e-9},
+		{name: "biner identik", f: binary, mean: 0.7, scale: 0.46, values: append(repeat(1, 70), repeat(0, 30)...), want: 0, tol: 1e-9},
+		{
+			// 50/50 terhadap ekspektasi 70/30: (0.5-0.3)ln(0.5/0.3) + (0.5-0.7)ln(0.5/0.7)
+			name: "biner bergeser", f: binary, mean: 0.7, scale: 0.46,
+			values: append(repeat(1, 50), repeat(0, 50)...),
+			want:   0.2*math.Log(0.5/0.3) - 0.2*math.Log(0.5/0.7), tol: 1e-9,
+		},
+		{
+			name: "biner bin kosong", f: binary, mean: 0.7, scale: 0.46, values: repeat(1, 20),
+			want: (psiEpsilon-0.3)*math.Log(psiEpsilon/0.3) + 0.3*math.Log(1/0.7), tol: 1e-9,
+		},
+		{name: "kontinu bergeser 0.1 SD", f: continuous, mean: 54, scale: 9, values: normalQuantiles(1000, 54.9, 9), want: 0.01, tol: 0.005},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := psi(tt.f, tt.mean, tt.scale, tt.values)
+			if math.IsNaN(got) || math.IsInf(got, 0) {
+				t.Fatalf("psi = %v", got)
+			}
+			if math.Abs(got-tt.want) > tt.tol {
+				t.Errorf("psi = %v, want %v ± %v", got, tt.want, tt.tol)
+			}
+		})
+	}
+
+	t.Run("diskret mendekati ekspektasi", func(t *testing.T) {
+		expected := normalBinProbabilities([]float64{1.5, 2.5, 3.5}, 3.16, 0.96)
+		var values []float64
+		for i, p := range expected {
+			values = append(values, repeat(discrete.values[i], int(math.Round(p*10000)))...)
+		}
+		if got := psi(discrete, 3.16, 0.96, values); got > 1e-4 {
+			t.Errorf("psi = %v, want ≈ 0", got)
+		}
+	})
+}
+
+func TestNormalBinProbabilities(t *testing.T) {
+	probs := normalBinProbabilities([]float64{0}, 0, 1)
+	if math.Abs(probs[0]-0.5) > 1e-12 || math.Abs(probs[1]-0.5) > 1e-12 {
+		t.Errorf("probs = %v, want [0.5 0.5]", probs)
+	}
+
+	sum := 0.0
+	for _, p := range normalBinProbabilities([]float64{-1, 0, 1, 2}, 0.3, 1.7) {
+		if p < 0 {
+			t.Errorf("peluang negatif %v", p)
+		}
+		sum += p
+	}
+	if math.Abs(sum-1) > 1e-12 {
+		t.Errorf("jumlah peluang = %v, want 1", sum)
+	}
+}
+
+func TestBinIndex(t *testing.T) {
+	edges := []float64{1.5, 2.5, 3.5}
+	tests := []struct {
+		v    float64
+		want int
+	}{
+		{v: 1, want: 0},
+		{v: 1.5, want: 1},
+		{v: 2, want: 1},
+		{v: 3.5, want: 3},
+		{v: 100, want: 3},
+	}
+	for _, tt := range tests {
+		if got := binIndex(edges, tt.v); got != tt.want {
+			t.Errorf("binIndex(%v) = %d, want %d", tt.v, got, tt.want)
+		}
+	}
+}
+
+func TestCompareMeanShiftGate(t *testing.T) {
+	baseline, err := DefaultBaseline()
+	if err != nil {
+		t.Fatal(err)
+	}
+	mean, scale := baseline.Mean[0], baseline.Scale[0]
+
+	tests := []struct {
+		name       string
+		values     []float64
+		wantShift  float64
+		wantZ      float64
+		wantResult bool
+	}{
+		{name: "identik", values: normalQuantiles(1000, mean, scale), wantShift: 0, wantZ: 0, wantResult: false},
+		{
+			// z = 0.1 * sqrt(10000) = 10 melewati ZScore, tetapi efeknya di bawah MeanShift
+			name: "signifikan tapi efek kecil", values: normalQuantiles(10000, mean+0.1*scale, scale),
+			wantShift: 0.1, wantZ: 10, wantResult: false,
+		},
+		{
+			// z = 0.5 * sqrt(100) = 5, efek 0.5 SD
+			name: "signifikan dan efek besar", values: normalQuantiles(100, mean+0.5*scale, scale),
+			wantShift: 0.5, wantZ: 5, wantResult: true,
+		},
+		{
+			// efek besar tetapi sampel terlalu kecil: z = 0.5 * sqrt(4) = 1
+			name: "efek besar tapi tidak signifikan", values: []float64{mean + 0.5*scale, mean + 0.5*scale, mean + 0.5*scale, mean + 0.5*scale},
+			wantShift: 0.5, wantZ: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sample := make(Sample, len(features))
+			sample[0] = tt.values
+
+			fd := Compare(baseline, sample, testThresholds)[0]
+			if math.Abs(fd.MeanShift-tt.wantShift) > 1e-6 {
+				t.Errorf("MeanShift = %v, want %v", fd.MeanShift, tt.wantShift)
+			}
+			if math.Abs(fd.ZScore-tt.wantZ) > 1e-4 {
+				t.Errorf("ZScore = %v, want %v", fd.ZScore, tt.wantZ)
+			}
+			meanDrifted := math.Abs(fd.ZScore) >= testThresholds.ZScore && math.Abs(fd.MeanShift) >= testThresholds.MeanShift
+			if meanDrifted != tt.wantResult {
+				t.Errorf("uji rata-rata drift = %v, want %v", meanDrifted, tt.wantResult)
+			}
+			if fd.Drifted != (meanDrifted || fd.PSI >= testThresholds.PSI) {
+				t.Errorf("Drifted = %v tidak sesuai PSI %v dan uji rata-rata %v", fd.Drifted, fd.PSI, meanDrifted)
+			}
+		})
+	}
+}
+
+func TestCompareIdenticalNotDrifted(t *testing.T) {
+	baseline, err := DefaultBaseline()
+	if err != nil {
+		t.Fatal(err)
+	}
+	sample := make(Sample, len(features))
+	sample[0] = normalQuantiles(1000, baseline.Mean[0], baseline.Scale[0])
+	sample[3] = normalQuantiles(1000, baseline.Mean[3], baseline.Scale[3])
+
+	result := Compare(baseline, sample, testThresholds)
+	for _, i := range []int{0, 3} {
+		if result[i].Drifted || result[i].PSI > 1e-9 {
+			t.Errorf("%s: Drifted = %v, PSI = %v, want tidak drift", result[i].Feature, result[i].Drifted, result[i].PSI)
+		}
+	}
+}
+
+func TestCompareEmptySample(t *testing.T) {
+	baseline, err := DefaultBaseline()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, fd := range Compare(baseline, make(Sample, len(features)), testThresholds) {
+		if fd.SampleSize != 0 || fd.Drifted || fd.PSI != 0 || fd.ZScore != 0 {
+			t.Errorf("%s tanpa sampel = %+v", fd.Feature, fd)
+		}
+	}
+}
@@ -1,7 +1,10 @@
 package dto
 
 import (
+	"jantungin-api-server/internal/clinical"
 	"jantungin-api-server/internal/data/entity"
+
+	"github.com/google/uuid"
 )
 
 func ToPatientResponse(u entity.User) PatientResponse {
@@ -14,6 +17,7 @@ func ToPatientResponse(u entity.User) PatientResponse {
 		ID:          u.ID.String(),
 		Name:        u.Name,
 		Email:       u.Email,
+		NIK:         u.NIK,
 		DateOfBirth: dob,
 		Role:        u.Role,
 		CreatedAt:   u.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
@@ -55,6 +59,11 @@ func ToDiagnosisResponse(d entity.Diagnosis) DiagnosisResponse {
 		ResultPercentage:      d.ResultPercentage,
 		CardiovascularRisk:    d.CardiovascularRisk,
 		Prediction:            d.Prediction,
+		ModelVersion:          d.ModelVersion,
+		Version:               d.Version,
+		ClinicalNotes:         d.ClinicalNotes,
+		Status:                d.Status,
+		ReviewNotes:           d.ReviewNotes,
 		Patient: DiagnosisUserInfo{
 			Name: d.Patient.Name,
 			Role: d.Patient.Role,
@@ -71,6 +80,20 @@ func ToDiagnosisResponse(d entity.Diagnosis) DiagnosisResponse {
 		}
 	}
 
+	// Reviewer hanya di-set jika sudah di-review
+	if d.ReviewedBy != nil {
+		s := d.ReviewedBy.String()
+		resp.ReviewedBy = &s
+		resp.Reviewer = &DiagnosisUserInfo{
+			Name: d.Reviewer.Name,
+			Role: d.Reviewer.Role,
+		}
+	}
+	if d.ReviewedAt != nil {
+		s := d.ReviewedAt.Format("2006-01-02T15:04:05Z07:00")
+		resp.ReviewedAt = &s
+	}
+
 	return resp
 }
 
@@ -81,3 +104,153 @@ func ToDiagnosisResponseList(diagnoses []entity.Diagnosis) []DiagnosisResponse {
 	}
 	return result
 }
+
+func ToDiagnosisRevisionResponse(r entity.DiagnosisRevision) DiagnosisRevisionResponse {
+	resp := DiagnosisRevisionResponse{
+		ID:                    r.ID.String(),
+		DiagnosisID:           r.DiagnosisID.String(),
+		Version:               r.Version,
+		Action:                r.Action,
+		Reason:                r.Reason,
+		EditedBy:              r.EditedBy.String(),
+		Age:                   r.Age,
+		Sex:                   r.Sex,
+		ChestPainType:         r.ChestPainType,
+		RestingEcgResults:     r.RestingEcgResults,
+		FastingBloodSugar:     r.FastingBloodSugar,
+		RestingBloodPressure:  r.RestingBloodPressure,
+		MaximumHeartRate:      r.MaximumHeartRate,
+		ExerciseInducedAngina: r.ExerciseInducedAngina,
+		StSegment:             r.StSegment,
+		MajorVessels:          r.MajorVessels,
+		Thalassemia:           r.Thalassemia,
+		SerumCholesterol:      r.SerumCholesterol,
+		StDepression:          r.StDepression,
+		ResultPercentage:      r.ResultPercentage,
+		CardiovascularRisk:    r.CardiovascularRisk,
+		Prediction:            r.Prediction,
+		ClinicalNotes:         r.ClinicalNotes,
+		Status:                r.Status,
+		CreatedAt:             r.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
+	}
+
+	// Editor hanya di-set jika relasinya ter-load
+	if r.Editor.ID != uuid.Nil {
+		resp.Editor = &DiagnosisUserInfo{
+			Name: r.Editor.Name,
+			Role: r.Editor.Role,
+		}
+	}
+
+	return resp
+}
+
+func ToDiagnosisRevisionResponseList(revisions []entity.DiagnosisRevision) []DiagnosisRevisionResponse {
+	result := make([]DiagnosisRevisionResponse, len(revisions))
+	for i, r := range revisions {
+		result[i] = ToDiagnosisRevisionResponse(r)
+	}
+	return result
+}
+
+// ToCreateDiagnosisRequest mengembalikan input klinis dari diagnosis yang sudah tersimpan.
+// Nilai laboratorium di database selalu dalam mg/dL.
+func ToCreateDiagnosisRequest(d entity.Diagnosis) CreateDiagnosisRequest {
+	return CreateDiagnosisRequest{
+		PatientID:             d.UserID.String(),
+		Age:                   d.Age,
+		Sex:                   d.Sex,
+		ChestPainType:         d.ChestPainType,
+		RestingBloodPressure:  d.RestingBloodPressure,
+		SerumCholesterol:      d.SerumCholesterol,
+		CholesterolUnit:       clinical.UnitMgDL,
+		FastingBloodSugar:     d.FastingBloodSugar,
+		GlucoseUnit:           clinical.UnitMgDL,
+		RestingEcgResults:     d.RestingEcgResults,
+		MaximumHeartRate:      d.MaximumHeartRate,
+		ExerciseInducedAngina: d.ExerciseInducedAngina,
+		StDepression:          d.StDepression,
+		StSegment:             d.StSegment,
+		MajorVessels:          d.MajorVessels,
+		Thalassemia:           d.Thalassemia,
+	}
+}
+
+// ApplyTo menimpa input dasar dengan field skenario yang diisi
+func (s SimulationScenario) ApplyTo(base CreateDiagnosisRequest) CreateDiagnosisRequest {
+	if s.Age != nil {
+		base.Age = *s.Age
+	}
+	if s.Sex != nil {
+		base.Sex = *s.Sex
+	}
+	if s.ChestPainType != nil {
+		base.ChestPainType = *s.ChestPainType
+	}
+	if s.RestingBloodPressure != nil {
+		base.RestingBloodPressure = *s.RestingBloodPressure
+	}
+	// Satuan skenario hanya berlaku untuk nilai yang ikut diubah,
+	// nilai dasar tetap dalam mg/dL
+	if s.SerumCholesterol != nil {
+		base.SerumCholesterol = *s.SerumCholesterol
+		base.CholesterolUnit = s.CholesterolUnit
+	}
+	if s.FastingBloodSugar != nil {
+		base.FastingBloodSugar = *s.FastingBloodSugar
+		base.GlucoseUnit = s.GlucoseUnit
+	}
+	if s.RestingEcgResults != nil {
+		base.RestingEcgResults = *s.RestingEcgResults
+	}
+	if s.MaximumHeartRate != nil {
+		base.MaximumHeartRate = *s.MaximumHeartRate
+	}
+	if s.ExerciseInducedAngina != nil {
+		base.ExerciseInducedAngina = *s.ExerciseInducedAngina
+	}
+	if s.StDepression != nil {
+		base.StDepression = *s.StDepression
+	}
+	if s.StSegment != nil {
+		base.StSegment = *s.StSegment
+	}
+	if s.MajorVessels != nil {
+		base.MajorVessels = *s.MajorVessels
+	}
+	if s.Thalassemia != nil {
+		base.Thalassemia = *s.Thalassemia
+	}
+	return base
+}
+
+// Fields mengembalikan nama field CreateDiagnosisRequest yang diubah skenario,
+// untuk memvalidasi nilai skenario tanpa ikut memeriksa nilai diagnosis dasar
+func (s SimulationScenario) Fields() []string {
+	set := []struct {
+		name string
+		ok   bool
+	}{
+		{"Age", s.Age != nil},
+		{"Sex", s.Sex != nil},
+		{"ChestPainType", s.ChestPainType != nil},
+		{"RestingBloodPressure", s.RestingBloodPressure != nil},
+		{"SerumCholesterol", s.SerumCholesterol != nil},
+		{"FastingBloodSugar", s.FastingBloodSugar != nil},
+		{"RestingEcgResults", s.RestingEcgResults != nil},
+		{"MaximumHeartRate", s.MaximumHeartRate != nil},
+		{"ExerciseInducedAngina", s.ExerciseInducedAngina != nil},
+		{"StDepression", s.StDepression != nil},
+		{"StSegment", s.StSegment != nil},
+		{"MajorVessels", s.MajorVessels != nil},
+		{"Thalassemia", s.Thalassemia != nil},
+	}
+
+	var fields []string
+	for _, f := range set {
+		if f.ok {
+			fields = append(fields, f.name)
+		}
+	}
+	return fields
+}
@@ -6,6 +6,7 @@ type AuthRegisterRequest struct {
 	Email       string `json:"email"`
 	Password    string `json:"password" binding:"required"`
 	DateOfBirth string `json:"dateOfBirth"`
+	NIK         string `json:"nik"` // opsional, 16 digit NIK
 }
 
 type AuthLoginRequest struct {
@@ -21,21 +22,91 @@ type AuthLoginEmailRequest struct {
 type UpdateProfileRequest struct {
 	Name        string `json:"name"`
 	DateOfBirth string `json:"dateOfBirth"`
+	NIK         string `json:"nik"`
 }
 
 type CreateDiagnosisRequest struct {
 	PatientID             string  `json:"patientId"` // opsional, hanya untuk admin/dokter
 	Age                   int     `json:"age" binding:"required,min=1,max=120"`
-	Sex                   string  `json:"sex" binding:"required,oneof=Male Female"`
+	Sex                   string  `json:"sex" binding:"required"`
 	ChestPainType         string  `json:"chestPainType" binding:"required"`
 	RestingBloodPressure  float64 `json:"restingBloodPressure" binding:"required,gt=0"`
 	SerumCholesterol      float64 `json:"serumCholesterol" binding:"required,gte=0"`
+	CholesterolUnit       string  `json:"cholesterolUnit"` // opsional: "mg/dL" (default) atau "mmol/L"
 	FastingBloodSugar     float64 `json:"fastingBloodSugar" binding:"required,gte=0"`
+	GlucoseUnit           string  `json:"glucoseUnit"` // opsional: "mg/dL" (default) atau "mmol/L"
 	RestingEcgResults     string  `json:"restingEcgResults" binding:"required"`
 	MaximumHeartRate      int     `json:"maximumHeartRate" binding:"required,gte=0"`
-	ExerciseInducedAngina string  `json:"exerciseInducedAngina" binding:"required,oneof=Yes No"`
+	ExerciseInducedAngina string  `json:"exerciseInducedAngina" binding:"required"`
 	StDepression          float64 `json:"stDepression" binding:"gte=0"`
 	StSegment             string  `json:"stSegment" binding:"required"`
 	MajorVessels          int     `json:"majorVessels" binding:"gte=0,lte=3"`
 	Thalassemia           string  `json:"thalassemia" binding:"required"`
+	ClinicalNotes         string  `json:"clinicalNotes" binding:"max=5000"` // opsional, catatan klinis dokter
+}
+
+// UpdateDiagnosisRequest dipakai untuk mengoreksi diagnosis yang sudah ada.
+// PatientID diabaikan: pasien pemilik diagnosis tidak bisa dipindah.
+// ClinicalNotes menimpa field embedded: nil berarti catatan lama dipertahankan,
+// string kosong berarti catatan dihapus.
+type UpdateDiagnosisRequest struct {
+	CreateDiagnosisRequest
+	ClinicalNotes *string `json:"clinicalNotes" binding:"omitempty,max=5000"`
+	Version       int     `json:"version" binding:"gte=0"` // opsional, versi yang sedang diedit (untuk deteksi edit bersamaan)
+	Reason        string  `json:"reason" binding:"required,max=500"`
+}
+
+// DeleteDiagnosisRequest body opsional untuk DELETE /diagnosis/:id
+type DeleteDiagnosisRequest struct {
+	Reason string `json:"reason" binding:"max=500"`
+}
+
+// ReviewDiagnosisRequest dipakai dokter reviewer untuk menandai diagnosis
+// sebagai "reviewed" atau langsung "signed" (read-only)
+type ReviewDiagnosisRequest struct {
+	Decision string `json:"decision" binding:"required,oneof=reviewed signed"`
+	Notes    string `json:"notes" binding:"max=5000"`
+	Version  int    `json:"version" binding:"required"` // versi diagnosis yang dibaca reviewer
+}
+
+// SimulateDiagnosisRequest dipakai untuk simulasi "what-if" tanpa menyimpan ke database
+type SimulateDiagnosisRequest struct {
+	BaseDiagnosisID string               `json:"baseDiagnosisId" binding:"required,uuid"`
+	Scenarios       []SimulationScenario `json:"scenarios" binding:"required,min=1,max=10,dive"`
+}
+
+// SimulationScenario berisi field yang ingin diubah dari diagnosis dasar.
+// Field yang tidak dikirim (null) tetap memakai nilai dari diagnosis dasar.
+type SimulationScenario struct {
+	Name                  string   `json:"name"`
+	Age                   *int     `json:"age"`
+	Sex                   *string  `json:"sex"`
+	ChestPainType         *string  `json:"chestPainType"`
+	RestingBloodPressure  *float64 `json:"restingBloodPressure"`
+	SerumCholesterol      *float64 `json:"serumCholesterol"`
+	CholesterolUnit       string   `json:"cholesterolUnit"`
+	FastingBloodSugar     *float64 `json:"fastingBloodSugar"`
+	GlucoseUnit           string   `json:"glucoseUnit"`
+	RestingEcgResults     *string  `json:"restingEcgResults"`
+	MaximumHeartRate      *int     `json:"maximumHeartRate"`
+	ExerciseInducedAngina *string  `json:"exerciseInducedAngina"`
+	StDepression          *float64 `json:"stDepression"`
+	StSegment             *string  `json:"stSegment"`
+	MajorVessels          *int     `json:"majorVessels"`
+	Thalassemia           *string  `json:"thalassemia"`
+}
+
+// AlertRuleRequest dipakai admin untuk membuat/mengubah aturan alert diagnosis.
+// Threshold (persen) wajib untuk type risk_threshold.
+type AlertRuleRequest struct {
+	Name         string   `json:"name" binding:"required,max=100"`
+	Type         string   `json:"type" binding:"required,oneof=risk_threshold category_jump"`
+	Threshold    *float64 `json:"threshold" binding:"omitempty,gte=0,lte=100"`
+	Enabled      *bool    `json:"enabled"`      // default true
+	EmailEnabled *bool    `json:"emailEnabled"` // default true
+}
+
+// AcknowledgeNotificationRequest dipakai dokter untuk menandai alert sudah ditindaklanjuti
+type AcknowledgeNotificationRequest struct {
+	Note string `json:"note" binding:"max=2000"`
 }
@@ -1,10 +1,13 @@
 package dto
 
+import "jantungin-api-server/internal/clinical"
+
 // PatientResponse digunakan untuk endpoint GET /api/v1/admin/patients
 type PatientResponse struct {
 	ID          string  `json:"id"`
 	Name        string  `json:"name"`
 	Email       *string `json:"email,omitempty"`
+	NIK         *string `json:"nik,omitempty"`
 	DateOfBirth *string `json:"dateOfBirth,omitempty"`
 	Role        string  `json:"role"`
 	CreatedAt   string  `json:"createdAt"`
@@ -15,6 +18,7 @@ type AuthUserResponse struct {
 	Name     string  `json:"name"`
 	Username *string `json:"username,omitempty"`
 	Email    *string `json:"email"`
+	NIK      *string `json:"nik,omitempty"`
 	Role     string  `json:"role"`
 }
 
@@ -65,16 +69,19 @@ type UpdateProfileData struct {
 	Name        string  `json:"name"`
 	Username    *string `json:"username,omitempty"`
 	Email       *string `json:"email"`
+	NIK         *string `json:"nik,omitempty"`
 	DateOfBirth *string `json:"dateOfBirth,omitempty"`
 }
 
 type DiagnosisResultData struct {
-	ID                 string  `json:"id"`
-	UserID             string  `json:"userId"`
-	ResultPercentage   float64 `json:"resultPercentage"`
-	CardiovascularRisk string  `json:"cardiovascularRisk"`
-	Prediction         string  `json:"prediction"`
-	CreatedAt          string  `json:"createdAt"`
+	ID                 string             `json:"id"`
+	UserID             string             `json:"userId"`
+	ResultPercentage   float64            `json:"resultPercentage"`
+	CardiovascularRisk string             `json:"cardiovascularRisk"`
+	Prediction         string             `json:"prediction"`
+	Status             string             `json:"status"`
+	Warnings           []clinical.Finding `json:"warnings"`
+	CreatedAt          string             `json:"createdAt"`
 }
 
 type DiagnosisUserInfo struct {
@@ -102,8 +109,102 @@ type DiagnosisResponse struct {
 	ResultPercentage      float64            `json:"resultPercentage"`
 	CardiovascularRisk    string             `json:"cardiovascularRisk"`
 	Prediction            string             `json:"prediction"`
+	ModelVersion          string             `json:"modelVersion"`
+	Version               int                `json:"version"`
+	ClinicalNotes         string             `json:"clinicalNotes"`
+	Status                string             `json:"status"`
+	ReviewedBy            *string            `json:"reviewedBy"`
+	ReviewedAt            *string            `json:"reviewedAt"`
+	ReviewNotes           string             `json:"reviewNotes"`
 	Patient               DiagnosisUserInfo  `json:"patient"`
 	Creator               *DiagnosisUserInfo `json:"creator,omitempty"`
+	Reviewer              *DiagnosisUserInfo `json:"reviewer,omitempty"`
 	CreatedAt             string             `json:"createdAt"`
 	UpdatedAt             string             `json:"updatedAt"`
 }
+
+// DiagnosisRevisionResponse adalah snapshot diagnosis sebelum diubah/dihapus
+type DiagnosisRevisionResponse struct {
+	ID                    string             `json:"id"`
+	DiagnosisID           string             `json:"diagnosisId"`
+	Version               int                `json:"version"`
+	Action                string             `json:"action"`
+	Reason                string             `json:"reason"`
+	EditedBy              string             `json:"editedBy"`
+	Editor                *DiagnosisUserInfo `json:"editor,omitempty"`
+	Age                   int                `json:"age"`
+	Sex                   string             `json:"sex"`
+	ChestPainType         string             `json:"chestPainType"`
+	RestingEcgResults     string             `json:"restingEcgResults"`
+	FastingBloodSugar     float64            `json:"fastingBloodSugar"`
+	RestingBloodPressure  float64            `json:"restingBloodPressure"`
+	MaximumHeartRate      int                `json:"maximumHeartRate"`
+	ExerciseInducedAngina string             `json:"exerciseInducedAngina"`
+	StSegment             string             `json:"stSegment"`
+	MajorVessels          int                `json:"majorVessels"`
+	Thalassemia           string             `json:"thalassemia"`
+	SerumCholesterol      float64            `json:"serumCholesterol"`
+	StDepression          float64            `json:"stDepression"`
+	ResultPercentage      float64            `json:"resultPercentage"`
+	CardiovascularRisk    string             `json:"cardiovascularRisk"`
+	Prediction            string             `json:"prediction"`
+	ClinicalNotes         string             `json:"clinicalNotes"`
+	Status                string             `json:"status"`
+	CreatedAt             string             `json:"createdAt"`
+}
+
+// SimulationOutcome adalah hasil prediksi untuk satu set input
+type SimulationOutcome struct {
+	ResultPercentage   float64 `json:"resultPercentage"`
+	CardiovascularRisk string  `json:"cardiovascularRisk"`
+	Prediction         string  `json:"prediction"`
+}
+
+type SimulationScenarioResult struct {
+	Name string `json:"name"`
+	SimulationOutcome
+	DeltaPercentage float64            `json:"deltaPercentage"`
+	CategoryChanged bool               `json:"categoryChanged"`
+	Warnings        []clinical.Finding `json:"warnings"`
+	Error           string             `json:"error,omitempty"`
+}
+
+type SimulationResultData struct {
+	BaseDiagnosisID string                     `json:"baseDiagnosisId"`
+	UserID          string                     `json:"userId"`
+	Baseline        SimulationOutcome          `json:"baseline"`
+	Scenarios       []SimulationScenarioResult `json:"scenarios"`
+}
+
+// TrendChange adalah selisih terhadap kunjungan (diagnosis) sebelumnya
+type TrendChange struct {
+	DaysSinceLast        int     `json:"daysSinceLast"`
+	ResultPercentage     float64 `json:"resultPercentage"`
+	RestingBloodPressure float64 `json:"restingBloodPressure"`
+	SerumCholesterol     float64 `json:"serumCholesterol"`
+	MaximumHeartRate     int     `json:"maximumHeartRate"`
+}
+
+// TrendPoint adalah satu kunjungan pada deret waktu risiko pasien
+type TrendPoint struct {
+	DiagnosisID          string       `json:"diagnosisId"`
+	Date                 string       `json:"date"`
+	ResultPercentage     float64      `json:"resultPercentage"`
+	CardiovascularRisk   string       `json:"cardiovascularRisk"`
+	RestingBloodPressure float64      `json:"restingBloodPressure"`
+	SerumCholesterol     float64      `json:"serumCholesterol"`
+	MaximumHeartRate     int          `json:"maximumHeartRate"`
+	ChangeSinceLast      *TrendChange `json:"changeSinceLast"` // null untuk kunjungan pertama
+	RiskWorsened         bool         `json:"riskWorsened"`    // kategori risiko naik dibanding kunjungan sebelumnya
+}
+
+// PatientTrendData digunakan untuk endpoint GET /api/v1/admin/patients/:id/trend
+type PatientTrendData struct {
+	PatientID       string       `json:"patientId"`
+	PatientName     string       `json:"patientName"`
+	Visits          int          `json:"visits"`
+	Direction       string       `json:"direction"` // improving | worsening | stable | insufficient_data
+	ChangeSinceLast *TrendChange `json:"changeSinceLast"`
+	RiskWorsened    bool         `json:"riskWorsened"` // kunjungan terakhir memperburuk kategori risiko
+	Points          []TrendPoint `json:"points"`       // urut dari kunjungan paling lama
+}
@@ -0,0 +1,46 @@
+package dto
+
+import (
+	"jantungin-api-server/internal/vocabulary"
+
+	"github.com/go-playground/validator/v10"
+)
+
+// validate memakai tag `binding` supaya aturannya sama persis dengan yang
+// dijalankan gin saat ShouldBindJSON.
+var validate = func() *validator.Validate {
+	v := validator.New()
+	v.SetTagName("binding")
+	return v
+}()
+
+// Validate menjalankan validasi CreateDiagnosisRequest di luar proses binding,
+// misalnya untuk input hasil gabungan skenario simulasi.
+func (r CreateDiagnosisRequest) Validate() error {
+	return validate.Struct(r)
+}
+
+// ValidateFields seperti Validate, tetapi hanya untuk field yang disebut
+// (nama field struct, mis. "Age")
+func (r CreateDiagnosisRequest) ValidateFields(fields ...string) error {
+	if len(fields) == 0 {
+		return nil
+	}
+	return validate.StructPartial(r, fields...)
+}
+
+// Normalize menyeragamkan field kategorikal ke nilai kanonik vokabulari.
+// Semua field yang tidak dikenali dikumpulkan dalam vocabulary.ValueErrors.
+func (r *CreateDiagnosisRequest) Normalize() error {
+	var errs vocabulary.ValueErrors
+	vocabulary.SexField.NormalizeInto(&r.Sex, &errs)
+	vocabulary.ChestPainTypeField.NormalizeInto(&r.ChestPainType, &errs)
+	vocabulary.RestingEcgField.NormalizeInto(&r.RestingEcgResults, &errs)
+	vocabulary.ExerciseAnginaField.NormalizeInto(&r.ExerciseInducedAngina, &errs)
+	vocabulary.StSlopeField.NormalizeInto(&r.StSegment, &errs)
+	vocabulary.ThalassemiaField.NormalizeInto(&r.Thalassemia, &errs)
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
@@ -0,0 +1,102 @@
+package fhir
+
+import (
+	"errors"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+const (
+	DefaultPageSize = 50
+	MaxPageSize     = 200
+)
+
+// Page adalah parameter paging pencarian (_count dan _offset)
+type Page struct {
+	Offset int
+	Count  int
+}
+
+// ParsePage membaca _count dan _offset dari query. _count di atas MaxPageSize dipotong.
+func ParsePage(query url.Values) (Page, error) {
+	page := Page{Count: DefaultPageSize}
+
+	if v := query.Get("_count"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 {
+			return page, errors.New("_count harus bilangan bulat >= 0")
+		}
+		page.Count = min(n, MaxPageSize)
+	}
+	if v := query.Get("_offset"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 {
+			return page, errors.New("_offset harus bilangan bulat >= 0")
+		}
+		page.Offset = n
+	}
+
+	return page, nil
+}
+
+// NewSearchBundle membuat Bundle searchset dari seluruh hasil pencarian dan mengambil
+// satu halaman sesuai page. requestURL dipakai untuk link self/first/previous/next/last,
+// baseURL (misalnya https://host/api/v1/fhir/r4) untuk fullUrl setiap entry.
+// Entry dengan resourceType == matchType diberi search.mode "match", sisanya "include".
+func NewSearchBundle(all []Resource, matchType string, page Page, requestURL *url.URL, baseURL string) Bundle {
+	total := len(all)
+
+	start := min(page.Offset, total)
+	end := min(start+page.Count, total)
+
+	bundle := Bundle{
+		ResourceType: "Bundle",
+		Type:         "searchset",
+		Timestamp:    time.Now().Format(fhirDateTime),
+		Total:        &total,
+		Link:         []BundleLink{{Relation: "self", URL: pageURL(requestURL, page.Offset, page.Count)}},
+	}
+
+	// _count=0 hanya meminta total, tanpa entry dan tanpa link navigasi
+	if page.Count > 0 {
+		last := 0
+		if total > 0 {
+			last = (total - 1) / page.Count * page.Count
+		}
+
+		bundle.Link = append(bundle.Link, BundleLink{Relation: "first", URL: pageURL(requestURL, 0, page.Count)})
+		if page.Offset > 0 {
+			prev := max(page.Offset-page.Count, 0)
+			bundle.Link = append(bundle.Link, BundleLink{Relation: "previous", URL: pageURL(requestURL, prev, page.Count)})
+		}
+		if end < total {
+			bundle.Link = append(bundle.Link, BundleLink{Relation: "next", URL: pageURL(requestURL, end, page.Count)})
+		}
+		bundle.Link = append(bundle.Link, BundleLink{Relation: "last", URL: pageURL(requestURL, last, page.Count)})
+	}
+
+	for _, resource := range all[start:end] {
+		resourceType, id := resource.resourceKey()
+		mode := "include"
+		if resourceType == matchType {
+			mode = "match"
+		}
+		bundle.Entry = append(bundle.Entry, BundleEntry{
+			FullURL:  baseURL + "/" + resourceType + "/" + id,
+			Resource: resource,
+			Search:   &BundleEntrySearch{Mode: mode},
+		})
+	}
+
+	return bundle
+}
+
+func pageURL(requestURL *url.URL, offset, count int) string {
+	u := *requestURL
+	query := u.Query()
+	query.Set("_offset", strconv.Itoa(offset))
+	query.Set("_count", strconv.Itoa(count))
+	u.RawQuery = query.Encode()
+	return u.String()
+}
@@ -0,0 +1,338 @@
+package fhir
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"jantungin-api-server/internal/data/entity"
+	"jantungin-api-server/internal/vocabulary"
+)
+
+const (
+	SystemLOINC           = "http://loinc.org"
+	SystemSNOMED          = "http://snomed.info/sct"
+	SystemUCUM            = "http://unitsofmeasure.org"
+	SystemObsCategory     = "http://terminology.hl7.org/CodeSystem/observation-category"
+	SystemRiskProbability = "http://terminology.hl7.org/CodeSystem/risk-probability"
+
+	// Kode lokal untuk input klinis yang tidak punya kode LOINC yang pas
+	SystemClinicalInput = "urn:jantungin:codesystem:clinical-input"
+	// Prefix code system untuk nilai kategorikal; nama field vokabulari ditambahkan di belakang
+	systemVocabularyPrefix = "urn:jantungin:codesystem:"
+	SystemUserID           = "urn:jantungin:user-id"
+)
+
+const fhirDateTime = "2006-01-02T15:04:05Z07:00"
+
+// ObservationKind mendefinisikan satu input klinis sebagai Observation
+type ObservationKind struct {
+	Slug     string // dipakai sebagai suffix ID Observation: <diagnosisId>-<slug>
+	Code     Coding
+	Category string
+	value    func(d entity.Diagnosis, o *Observation)
+}
+
+var observationKinds = []ObservationKind{
+	{
+		Slug:     "age",
+		Code:     Coding{System: SystemLOINC, Code: "30525-0", Display: "Age"},
+		Category: "survey",
+		value: func(d entity.Diagnosis, o *Observation) {
+			o.ValueQuantity = &Quantity{Value: float64(d.Age), Unit: "years", System: SystemUCUM, Code: "a"}
+		},
+	},
+	{
+		Slug:     "sex",
+		Code:     Coding{System: SystemLOINC, Code: "46098-0", Display: "Sex"},
+		Category: "survey",
+		value:    categorical(vocabulary.SexField, func(d entity.Diagnosis) string { return d.Sex }),
+	},
+	{
+		Slug:     "chest-pain-type",
+		Code:     Coding{System: SystemClinicalInput, Code: "chest-pain-type", Display: "Chest pain type"},
+		Category: "exam",
+		value:    categorical(vocabulary.ChestPainTypeField, func(d entity.Diagnosis) string { return d.ChestPainType }),
+	},
+	{
+		Slug:     "resting-blood-pressure",
+		Code:     Coding{System: SystemLOINC, Code: "8480-6", Display: "Systolic blood pressure"},
+		Category: "vital-signs",
+		value: func(d entity.Diagnosis, o *Observation) {
+			o.ValueQuantity = &Quantity{Value: d.RestingBloodPressure, Unit: "mmHg", System: SystemUCUM, Code: "mm[Hg]"}
+		},
+	},
+	{
+		Slug:     "serum-cholesterol",
+		Code:     Coding{System: SystemLOINC, Code: "2093-3", Display: "Cholesterol [Mass/volume] in Serum or Plasma"},
+		Category: "laboratory",
+		value: func(d entity.Diagnosis, o *Observation) {
+			o.ValueQuantity = &Quantity{Value: d.SerumCholesterol, Unit: "mg/dL", System: SystemUCUM, Code: "mg/dL"}
+		},
+	},
+	{
+		Slug:     "fasting-blood-sugar",
+		Code:     Coding{System: SystemLOINC, Code: "1558-6", Display: "Fasting glucose [Mass/volume] in Serum or Plasma"},
+		Category: "laboratory",
+		value: func(d entity.Diagnosis, o *Observation) {
+			o.ValueQuantity = &Quantity{Value: d.FastingBloodSugar, Unit: "mg/dL", System: SystemUCUM, Code: "mg/dL"}
+		},
+	},
+	{
+		Slug:     "resting-ecg",
+		Code:     Coding{System: SystemLOINC, Code: "8601-7", Display: "EKG impression"},
+		Category: "procedure",
+		value:    categorical(vocabulary.RestingEcgField, func(d entity.Diagnosis) string { return d.RestingEcgResults }),
+	},
+	{
+		Slug:     "maximum-heart-rate",
+		Code:     Coding{System: SystemLOINC, Code: "8867-4", Display: "Heart rate"},
+		Category: "vital-signs",
+		value: func(d entity.Diagnosis, o *Observation) {
+			o.ValueQuantity = &Quantity{Value: float64(d.MaximumHeartRate), Unit: "beats/minute", System: SystemUCUM, Code: "/min"}
+		},
+	},
+	{
+		Slug:     "exercise-induced-angina",
+		Code:     Coding{System: SystemClinicalInput, Code: "exercise-induced-angina", Display: "Exercise induced angina"},
+		Category: "exam",
+		value:    categorical(vocabulary.ExerciseAnginaField, func(d entity.Diagnosis) string { return d.ExerciseInducedAngina }),
+	},
+	{
+		Slug:     "st-depression",
+		Code:     Coding{System: SystemClinicalInput, Code: "st-depression", Display: "ST depression induced by exercise relative to rest (oldpeak)"},
+		Category: "procedure",
+		value: func(d entity.Diagnosis, o *Observation) {
+			o.ValueQuantity = &Quantity{Value: d.StDepression, Unit: "mm", System: SystemUCUM, Code: "mm"}
+		},
+	},
+	{
+		Slug:     "st-slope",
+		Code:     Coding{System: SystemClinicalInput, Code: "st-slope", Display: "Slope of peak exercise ST segment"},
+		Category: "procedure",
+		value:    categorical(vocabulary.StSlopeField, func(d entity.Diagnosis) string { return d.StSegment }),
+	},
+	{
+		Slug:     "major-vessels",
+		Code:     Coding{System: SystemClinicalInput, Code: "major-vessels", Display: "Number of major vessels colored by fluoroscopy"},
+		Category: "imaging",
+		value: func(d entity.Diagnosis, o *Observation) {
+			v := d.MajorVessels
+			o.ValueInteger = &v
+		},
+	},
+	{
+		Slug:     "thalassemia",
+		Code:     Coding{System: SystemClinicalInput, Code: "thalassemia", Display: "Thalassemia (thallium stress test result)"},
+		Category: "imaging",
+		value:    categorical(vocabulary.ThalassemiaField, func(d entity.Diagnosis) string { return d.Thalassemia }),
+	},
+}
+
+// ObservationKinds mengembalikan definisi 13 input klinis sesuai urutan form diagnosis
+func ObservationKinds() []ObservationKind {
+	return observationKinds
+}
+
+// ObservationKindBySlug mencari definisi input klinis berdasarkan slug ID Observation
+func ObservationKindBySlug(slug string) (ObservationKind, bool) {
+	for _, kind := range observationKinds {
+		if kind.Slug == slug {
+			return kind, true
+		}
+	}
+	return ObservationKind{}, false
+}
+
+// MatchesCode mengecek parameter pencarian "code" dengan format "code" atau "system|code"
+func (k ObservationKind) MatchesCode(param string) bool {
+	system, code, hasSystem := strings.Cut(param, "|")
+	if !hasSystem {
+		return k.Code.Code == param
+	}
+	return (system == "" || system == k.Code.System) && code == k.Code.Code
+}
+
+func categorical(field *vocabulary.Field, get func(entity.Diagnosis) string) func(entity.Diagnosis, *Observation) {
+	return func(d entity.Diagnosis, o *Observation) {
+		value := get(d)
+		o.ValueCodeableConcept = &CodeableConcept{
+			Coding: []Coding{{
+				System:  systemVocabularyPrefix + field.Name,
+				Code:    value,
+				Display: field.Label(value, "en"),
+			}},
+			Text: value,
+		}
+	}
+}
+
+// PatientFromUser memetakan user ke Patient. gender diisi dari diagnosis terbaru
+// (entity.User tidak menyimpan jenis kelamin); kosong jika belum pernah didiagnosis.
+func PatientFromUser(u entity.User, gender string) Patient {
+	p := Patient{
+		ResourceType: "Patient",
+		ID:           u.ID.String(),
+		Meta:         &Meta{LastUpdated: u.UpdatedAt.Format(fhirDateTime)},
+		Identifier:   []Identifier{{System: SystemUserID, Value: u.ID.String()}},
+		Active:       true,
+		Name:         []HumanName{{Use: "official", Text: u.Name}},
+		Gender:       "unknown",
+	}
+
+	if u.Email != nil && *u.Email != "" {
+		p.Telecom = []ContactPoint{{System: "email", Value: *u.Email}}
+	}
+	if u.DateOfBirth != nil {
+		p.BirthDate = u.DateOfBirth.Format("2006-01-02")
+	}
+
+	switch vocabulary.Sex(gender) {
+	case vocabulary.SexMale:
+		p.Gender = "male"
+	case vocabulary.SexFemale:
+		p.Gender = "female"
+	}
+
+	return p
+}
+
+// ObservationID membentuk ID Observation dari ID diagnosis dan slug input klinis
+func ObservationID(diagnosisID string, slug string) string {
+	return diagnosisID + "-" + slug
+}
+
+// SplitObservationID memecah ID Observation menjadi ID diagnosis (UUID 36 karakter) dan slug
+func SplitObservationID(id string) (diagnosisID string, slug string, ok bool) {
+	const uuidLen = 36
+	if len(id) <= uuidLen+1 || id[uuidLen] != '-' {
+		return "", "", false
+	}
+	return id[:uuidLen], id[uuidLen+1:], true
+}
+
+// ObservationFromDiagnosis memetakan satu input klinis diagnosis ke Observation
+func ObservationFromDiagnosis(d entity.Diagnosis, kind ObservationKind) Observation {
+	o := Observation{
+		ResourceType: "Observation",
+		ID:           ObservationID(d.ID.String(), kind.Slug),
+		Meta:         diagnosisMeta(d),
+		Status:       diagnosisStatus(d),
+		Category: []CodeableConcept{{
+			Coding: []Coding{{System: SystemObsCategory, Code: kind.Category}},
+		}},
+		Code:              CodeableConcept{Coding: []Coding{kind.Code}, Text: kind.Code.Display},
+		Subject:           patientReference(d),
+		EffectiveDateTime: d.CreatedAt.Format(fhirDateTime),
+	}
+	if performer := creatorReference(d); performer != nil {
+		o.Performer = []Reference{*performer}
+	}
+	kind.value(d, &o)
+	return o
+}
+
+// ObservationsFromDiagnosis memetakan semua 13 input klinis diagnosis
+func ObservationsFromDiagnosis(d entity.Diagnosis) []Observation {
+	observations := make([]Observation, len(observationKinds))
+	for i, kind := range observationKinds {
+		observations[i] = ObservationFromDiagnosis(d, kind)
+	}
+	return observations
+}
+
+// RiskAssessmentFromDiagnosis memetakan hasil prediksi ML ke RiskAssessment.
+// basis merujuk ke Observation input klinis diagnosis yang sama.
+func RiskAssessmentFromDiagnosis(d entity.Diagnosis) RiskAssessment {
+	probability := d.ResultPercentage / 100
+
+	qualitative := "low"
+	if vocabulary.IsHighRisk(d.CardiovascularRisk) {
+		qualitative = "high"
+	}
+
+	methodText := "JantungIn machine learning model"
+	if d.ModelVersion != "" {
+		methodText += " v" + strings.TrimPrefix(d.ModelVersion, "v")
+	}
+
+	r := RiskAssessment{
+		ResourceType: "RiskAssessment",
+		ID:           d.ID.String(),
+		Meta:         diagnosisMeta(d),
+		Status:       diagnosisStatus(d),
+		Method:       &CodeableConcept{Text: methodText},
+		Code: &CodeableConcept{
+			Coding: []Coding{{System: SystemSNOMED, Code: "56265001", Display: "Heart disease"}},
+			Text:   "Heart disease risk",
+		},
+		Subject:            patientReference(d),
+		OccurrenceDateTime: d.CreatedAt.Format(fhirDateTime),
+		Performer:          creatorReference(d),
+		Prediction: []RiskAssessmentPrediction{{
+			Outcome: &CodeableConcept{
+				Coding: []Coding{{System: SystemSNOMED, Code: "56265001", Display: "Heart disease"}},
+				Text:   d.Prediction,
+			},
+			ProbabilityDecimal: &probability,
+			QualitativeRisk: &CodeableConcept{
+				Coding: []Coding{{System: SystemRiskProbability, Code: qualitative}},
+				Text:   d.CardiovascularRisk,
+			},
+		}},
+	}
+
+	for _, kind := range observationKinds {
+		r.Basis = append(r.Basis, Reference{Reference: "Observation/" + ObservationID(d.ID.String(), kind.Slug)})
+	}
+
+	if strings.TrimSpace(d.ClinicalNotes) != "" {
+		r.Note = []Annotation{{Text: d.ClinicalNotes}}
+	}
+
+	return r
+}
+
+// diagnosisStatus memetakan status review ke ObservationStatus:
+// belum direview -> preliminary, sudah direview -> final (amended jika pernah dikoreksi)
+func diagnosisStatus(d entity.Diagnosis) string {
+	switch d.Status {
+	case entity.DiagnosisStatusReviewed, entity.DiagnosisStatusSigned:
+		if d.Version > 1 {
+			return "amended"
+		}
+		return "final"
+	default:
+		return "preliminary"
+	}
+}
+
+func diagnosisMeta(d entity.Diagnosis) *Meta {
+	version := d.Version
+	if version < 1 {
+		version = 1
+	}
+	return &Meta{
+		VersionID:   strconv.Itoa(version),
+		LastUpdated: latest(d.UpdatedAt, d.CreatedAt).Format(fhirDateTime),
+	}
+}
+
+func patientReference(d entity.Diagnosis) Reference {
+	return Reference{Reference: "Patient/" + d.UserID.String(), Display: d.Patient.Name}
+}
+
+// creatorReference hanya berisi display karena dokter tidak diekspos sebagai Practitioner
+func creatorReference(d entity.Diagnosis) *Reference {
+	if d.CreatedBy == nil || d.Creator.Name == "" {
+		return nil
+	}
+	return &Reference{Display: d.Creator.Name}
+}
+
+func latest(a, b time.Time) time.Time {
+	if a.After(b) {
+		return a
+	}
+	return b
+}
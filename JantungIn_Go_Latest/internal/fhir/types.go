@@ -0,0 +1,159 @@
+package fhir
+
+// Subset resource FHIR R4 yang dipakai JantungIn. Field yang tidak dipakai tidak
+// didefinisikan; semua field opsional memakai omitempty agar JSON tetap valid
+// menurut spesifikasi (FHIR tidak mengizinkan nilai null atau array kosong).
+
+const ContentType = "application/fhir+json"
+
+type Meta struct {
+	VersionID   string `json:"versionId,omitempty"`
+	LastUpdated string `json:"lastUpdated,omitempty"`
+}
+
+type Coding struct {
+	System  string `json:"system,omitempty"`
+	Code    string `json:"code,omitempty"`
+	Display string `json:"display,omitempty"`
+}
+
+type CodeableConcept struct {
+	Coding []Coding `json:"coding,omitempty"`
+	Text   string   `json:"text,omitempty"`
+}
+
+type Reference struct {
+	Reference string `json:"reference,omitempty"`
+	Display   string `json:"display,omitempty"`
+}
+
+type Quantity struct {
+	Value  float64 `json:"value"`
+	Unit   string  `json:"unit,omitempty"`
+	System string  `json:"system,omitempty"`
+	Code   string  `json:"code,omitempty"`
+}
+
+type Identifier struct {
+	System string `json:"system,omitempty"`
+	Value  string `json:"value,omitempty"`
+}
+
+type HumanName struct {
+	Use  string `json:"use,omitempty"`
+	Text string `json:"text,omitempty"`
+}
+
+type ContactPoint struct {
+	System string `json:"system,omitempty"`
+	Value  string `json:"value,omitempty"`
+}
+
+type Annotation struct {
+	Text string `json:"text"`
+}
+
+type Patient struct {
+	ResourceType string         `json:"resourceType"`
+	ID           string         `json:"id"`
+	Meta         *Meta          `json:"meta,omitempty"`
+	Identifier   []Identifier   `json:"identifier,omitempty"`
+	Active       bool           `json:"active"`
+	Name         []HumanName    `json:"name,omitempty"`
+	Telecom      []ContactPoint `json:"telecom,omitempty"`
+	Gender       string         `json:"gender,omitempty"`
+	BirthDate    string         `json:"birthDate,omitempty"`
+}
+
+type Observation struct {
+	ResourceType         string            `json:"resourceType"`
+	ID                   string            `json:"id"`
+	Meta                 *Meta             `json:"meta,omitempty"`
+	Status               string            `json:"status"`
+	Category             []CodeableConcept `json:"category,omitempty"`
+	Code                 CodeableConcept   `json:"code"`
+	Subject              Reference         `json:"subject"`
+	EffectiveDateTime    string            `json:"effectiveDateTime,omitempty"`
+	Performer            []Reference       `json:"performer,omitempty"`
+	ValueQuantity        *Quantity         `json:"valueQuantity,omitempty"`
+	ValueCodeableConcept *CodeableConcept  `json:"valueCodeableConcept,omitempty"`
+	ValueInteger         *int              `json:"valueInteger,omitempty"`
+}
+
+type RiskAssessmentPrediction struct {
+	Outcome            *CodeableConcept `json:"outcome,omitempty"`
+	ProbabilityDecimal *float64         `json:"probabilityDecimal,omitempty"`
+	QualitativeRisk    *CodeableConcept `json:"qualitativeRisk,omitempty"`
+}
+
+type RiskAssessment struct {
+	ResourceType       string                     `json:"resourceType"`
+	ID                 string                     `json:"id"`
+	Meta               *Meta                      `json:"meta,omitempty"`
+	Status             string                     `json:"status"`
+	Method             *CodeableConcept           `json:"method,omitempty"`
+	Code               *CodeableConcept           `json:"code,omitempty"`
+	Subject            Reference                  `json:"subject"`
+	OccurrenceDateTime string                     `json:"occurrenceDateTime,omitempty"`
+	Performer          *Reference                 `json:"performer,omitempty"`
+	Basis              []Reference                `json:"basis,omitempty"`
+	Prediction         []RiskAssessmentPrediction `json:"prediction,omitempty"`
+	Note               []Annotation               `json:"note,omitempty"`
+}
+
+type BundleLink struct {
+	Relation string `json:"relation"`
+	URL      string `json:"url"`
+}
+
+type BundleEntrySearch struct {
+	Mode string `json:"mode"`
+}
+
+type BundleEntry struct {
+	FullURL  string             `json:"fullUrl,omitempty"`
+	Resource any                `json:"resource"`
+	Search   *BundleEntrySearch `json:"search,omitempty"`
+}
+
+type Bundle struct {
+	ResourceType string        `json:"resourceType"`
+	Type         string        `json:"type"`
+	Timestamp    string        `json:"timestamp,omitempty"`
+	Total        *int          `json:"total,omitempty"`
+	Link         []BundleLink  `json:"link,omitempty"`
+	Entry        []BundleEntry `json:"entry,omitempty"`
+}
+
+type OperationOutcomeIssue struct {
+	Severity    string `json:"severity"`
+	Code        string `json:"code"`
+	Diagnostics string `json:"diagnostics,omitempty"`
+}
+
+type OperationOutcome struct {
+	ResourceType string                  `json:"resourceType"`
+	Issue        []OperationOutcomeIssue `json:"issue"`
+}
+
+// NewOperationOutcome membuat OperationOutcome dengan satu issue berseverity error.
+// code mengikuti value set issue-type, misalnya "not-found", "invalid", "forbidden".
+func NewOperationOutcome(code, diagnostics string) OperationOutcome {
+	return OperationOutcome{
+		ResourceType: "OperationOutcome",
+		Issue: []OperationOutcomeIssue{{
+			Severity:    "error",
+			Code:        code,
+			Diagnostics: diagnostics,
+		}},
+	}
+}
+
+// Resource adalah resource yang bisa dimasukkan ke Bundle
+type Resource interface {
+	resourceKey() (resourceType string, id string)
+}
+
+func (p Patient) resourceKey() (string, string)        { return p.ResourceType, p.ID }
+func (o Observation) resourceKey() (string, string)    { return o.ResourceType, o.ID }
+func (r RiskAssessment) resourceKey() (string, string) { return r.ResourceType, r.ID }
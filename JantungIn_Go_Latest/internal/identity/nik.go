@@ -0,0 +1,154 @@
+// Package identity memvalidasi Nomor Induk Kependudukan (NIK) Indonesia.
+//
+// NIK terdiri dari 16 digit: kode provinsi (2), kabupaten/kota (2), kecamatan (2),
+// tanggal lahir DDMMYY (6, tanggal + 40 untuk perempuan), dan nomor urut (4).
+// NIK tidak memiliki digit checksum, sehingga validasi dilakukan terhadap strukturnya:
+// kode wilayah, tanggal lahir yang benar-benar ada, dan nomor urut bukan 0000.
+package identity
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	ErrNIKFormat    = errors.New("NIK harus 16 digit angka")
+	ErrNIKRegion    = errors.New("kode wilayah NIK tidak valid")
+	ErrNIKBirthDate = errors.New("tanggal lahir pada NIK tidak valid")
+	ErrNIKSerial    = errors.New("nomor urut NIK tidak valid")
+)
+
+// provinceCodes adalah kode provinsi Kemendagri, termasuk provinsi pemekaran Papua 2022
+var provinceCodes = map[string]string{
+	"11": "Aceh",
+	"12": "Sumatera Utara",
+	"13": "Sumatera Barat",
+	"14": "Riau",
+	"15": "Jambi",
+	"16": "Sumatera Selatan",
+	"17": "Bengkulu",
+	"18": "Lampung",
+	"19": "Kepulauan Bangka Belitung",
+	"21": "Kepulauan Riau",
+	"31": "DKI Jakarta",
+	"32": "Jawa Barat",
+	"33": "Jawa Tengah",
+	"34": "DI Yogyakarta",
+	"35": "Jawa Timur",
+	"36": "Banten",
+	"51": "Bali",
+	"52": "Nusa Tenggara Barat",
+	"53": "Nusa Tenggara Timur",
+	"61": "Kalimantan Barat",
+	"62": "Kalimantan Tengah",
+	"63": "Kalimantan Selatan",
+	"64": "Kalimantan Timur",
+	"65": "Kalimantan Utara",
+	"71": "Sulawesi Utara",
+	"72": "Sulawesi Tengah",
+	"73": "Sulawesi Selatan",
+	"74": "Sulawesi Tenggara",
+	"75": "Gorontalo",
+	"76": "Sulawesi Barat",
+	"81": "Maluku",
+	"82": "Maluku Utara",
+	"91": "Papua",
+	"92": "Papua Barat",
+	"93": "Papua Selatan",
+	"94": "Papua Tengah",
+	"95": "Papua Pegunungan",
+	"96": "Papua Barat Daya",
+}
+
+// NIK adalah hasil parsing NIK yang valid
+type NIK struct {
+	Number       string // 16 digit, tanpa pemisah
+	ProvinceCode string
+	RegencyCode  string
+	DistrictCode string
+	BirthDate    time.Time
+	Female       bool
+	Serial       string
+}
+
+// Province mengembalikan nama provinsi dari kode wilayah NIK
+func (n NIK) Province() string {
+	return provinceCodes[n.ProvinceCode]
+}
+
+// NormalizeNIK menghapus spasi, titik, dan tanda hubung yang sering ikut tersalin dari KTP
+func NormalizeNIK(value string) string {
+	return strings.Map(func(r rune) rune {
+		if r == ' ' || r == '.' || r == '-' {
+			return -1
+		}
+		return r
+	}, strings.TrimSpace(value))
+}
+
+// LooksLikeNIK melaporkan apakah value (setelah dinormalisasi) berupa 16 digit angka,
+// untuk membedakan pencarian NIK dari pencarian nama
+func LooksLikeNIK(value string) bool {
+	value = NormalizeNIK(value)
+	if len(value) != 16 {
+		return false
+	}
+	for _, r := range value {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// ParseNIK memvalidasi struktur NIK. now dipakai untuk menentukan abad tahun lahir
+// (YY di atas tahun berjalan dianggap 19YY).
+func ParseNIK(value string, now time.Time) (*NIK, error) {
+	number := NormalizeNIK(value)
+	if !LooksLikeNIK(number) {
+		return nil, ErrNIKFormat
+	}
+
+	nik := &NIK{
+		Number:       number,
+		ProvinceCode: number[0:2],
+		RegencyCode:  number[2:4],
+		DistrictCode: number[4:6],
+		Serial:       number[12:16],
+	}
+
+	if _, ok := provinceCodes[nik.ProvinceCode]; !ok {
+		return nil, ErrNIKRegion
+	}
+	// Kabupaten 01-69, kota 71-99; kecamatan dimulai dari 01
+	if nik.RegencyCode == "00" || nik.RegencyCode == "70" || nik.DistrictCode == "00" {
+		return nil, ErrNIKRegion
+	}
+	if nik.Serial == "0000" {
+		return nil, ErrNIKSerial
+	}
+
+	day, _ := strconv.Atoi(number[6:8])
+	month, _ := strconv.Atoi(number[8:10])
+	year, _ := strconv.Atoi(number[10:12])
+	if day > 40 {
+		nik.Female = true
+		day -= 40
+	}
+
+	year += 2000
+	if year > now.Year() {
+		year -= 100
+	}
+
+	birthDate := time.Date(year, time.Month(month), day, 0, 0, 0, 0, time.UTC)
+	// time.Date menormalisasi tanggal yang tidak ada (mis. 31 Februari), jadi dibandingkan ulang
+	if day < 1 || birthDate.Day() != day || int(birthDate.Month()) != month || birthDate.After(now) {
+		return nil, ErrNIKBirthDate
+	}
+	nik.BirthDate = birthDate
+
+	return nik, nil
+}
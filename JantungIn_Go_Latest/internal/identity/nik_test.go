@@ -0,0 +1,89 @@
+package identity
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestParseNIK(t *testing.T) {
+	now := time.Date(2026, time.October, 19, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name      string
+		value     string
+		wantErr   error
+		wantBirth time.Time
+		female    bool
+	}{
+		{name: "laki-laki", value: "3201011708900001", wantBirth: date(1990, 8, 17)},
+		{name: "perempuan tanggal + 40", value: "3201015708900001", wantBirth: date(1990, 8, 17), female: true},
+		{name: "perempuan tanggal 31", value: "3201017112850002", wantBirth: date(1985, 12, 31), female: true},
+		{name: "pemisah dari KTP", value: "3201.01-17 0890 0001", wantBirth: date(1990, 8, 17)},
+
+		{name: "31 Februari", value: "3201013102900001", wantErr: ErrNIKBirthDate},
+		{name: "29 Februari tahun kabisat", value: "3201012902000001", wantBirth: date(2000, 2, 29)},
+		{name: "29 Februari bukan kabisat", value: "3201012902010001", wantErr: ErrNIKBirthDate},
+		{name: "perempuan 31 Februari", value: "3201017102900001", wantErr: ErrNIKBirthDate},
+		{name: "tanggal 00", value: "3201010008900001", wantErr: ErrNIKBirthDate},
+		{name: "tanggal 40", value: "3201014008900001", wantErr: ErrNIKBirthDate},
+		{name: "bulan 13", value: "3201011713900001", wantErr: ErrNIKBirthDate},
+
+		{name: "abad: YY tahun berjalan", value: "3201010101260001", wantBirth: date(2026, 1, 1)},
+		{name: "abad: YY di atas tahun berjalan", value: "3201010101270001", wantBirth: date(1927, 1, 1)},
+		{name: "abad: tahun berjalan setelah now", value: "3201012010260001", wantErr: ErrNIKBirthDate},
+		{name: "abad: YY 00", value: "3201010101000001", wantBirth: date(2000, 1, 1)},
+
+		{name: "kabupaten 00", value: "3200011708900001", wantErr: ErrNIKRegion},
+		{name: "kode 70", value: "3270011708900001", wantErr: ErrNIKRegion},
+		{name: "kota 71", value: "3271011708900001", wantBirth: date(1990, 8, 17)},
+		{name: "kecamatan 00", value: "3201001708900001", wantErr: ErrNIKRegion},
+		{name: "provinsi tidak dikenal", value: "2001011708900001", wantErr: ErrNIKRegion},
+
+		{name: "nomor urut 0000", value: "3201011708900000", wantErr: ErrNIKSerial},
+
+		{name: "Papua Selatan", value: "9301011708900001", wantBirth: date(1990, 8, 17)},
+		{name: "Papua Tengah", value: "9401011708900001", wantBirth: date(1990, 8, 17)},
+		{name: "Papua Pegunungan", value: "9501011708900001", wantBirth: date(1990, 8, 17)},
+		{name: "Papua Barat Daya", value: "9601011708900001", wantBirth: date(1990, 8, 17)},
+		{name: "kode Papua 97", value: "9701011708900001", wantErr: ErrNIKRegion},
+
+		{name: "15 digit", value: "320101170890001", wantErr: ErrNIKFormat},
+		{name: "huruf", value: "32010117089000A1", wantErr: ErrNIKFormat},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			nik, err := ParseNIK(tt.value, now)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("ParseNIK(%q) error = %v, want %v", tt.value, err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseNIK(%q) error = %v", tt.value, err)
+			}
+			if !nik.BirthDate.Equal(tt.wantBirth) {
+				t.Errorf("BirthDate = %s, want %s", nik.BirthDate.Format(time.DateOnly), tt.wantBirth.Format(time.DateOnly))
+			}
+			if nik.Female != tt.female {
+				t.Errorf("Female = %v, want %v", nik.Female, tt.female)
+			}
+		})
+	}
+}
+
+func TestParseNIKProvince(t *testing.T) {
+	nik, err := ParseNIK("9601011708900001", time.Date(2026, time.October, 19, 0, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := nik.Province(); got != "Papua Barat Daya" {
+		t.Errorf("Province() = %q, want %q", got, "Papua Barat Daya")
+	}
+}
+
+func date(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
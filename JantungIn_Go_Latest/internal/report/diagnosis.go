@@ -0,0 +1,352 @@
+package report
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"jantungin-api-server/internal/data/entity"
+	"jantungin-api-server/internal/vocabulary"
+)
+
+// Language bahasa laporan
+type Language string
+
+const (
+	LangID Language = "id"
+	LangEN Language = "en"
+)
+
+// ParseLanguage menerima "id" atau "en" (default "id" jika kosong)
+func ParseLanguage(s string) (Language, bool) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "", "id":
+		return LangID, true
+	case "en":
+		return LangEN, true
+	}
+	return "", false
+}
+
+// DiagnosisReport adalah data yang dibutuhkan untuk merender laporan diagnosis.
+// Diagnosis.Creator dan Diagnosis.Reviewer dipakai jika sudah ter-load.
+type DiagnosisReport struct {
+	Diagnosis   entity.Diagnosis
+	Patient     entity.User
+	GeneratedAt time.Time
+	Location    *time.Location
+}
+
+// Layout halaman (point)
+const (
+	marginX      = 50.0
+	marginTop    = 60.0
+	marginBottom = 60.0
+	contentWidth = pageWidth - 2*marginX
+	labelWidth   = 210.0
+)
+
+// RenderDiagnosis menulis laporan diagnosis dalam format PDF ke w
+func RenderDiagnosis(w io.Writer, r DiagnosisReport, lang Language) error {
+	t := translations[lang]
+	if t == nil {
+		t = translations[LangID]
+	}
+	loc := r.Location
+	if loc == nil {
+		loc = time.UTC
+	}
+	d := r.Diagnosis
+
+	l := newLayout(newPDFDocument(t["title"], r.GeneratedAt))
+
+	// Header
+	l.doc.fillRect(l.page, 0, 0, pageWidth, 8, 0.76, 0.09, 0.16)
+	l.write(fontBold, 16, t["title"])
+	l.write(fontRegular, 9, fmt.Sprintf("JantungIn · %s %s", t["generatedAt"], formatTime(r.GeneratedAt, loc)))
+	l.space(6)
+
+	l.section(t["patient"])
+	l.row(t["patientName"], r.Patient.Name)
+	l.row(t["patientId"], r.Patient.ID.String())
+	if r.Patient.DateOfBirth != nil {
+		l.row(t["dateOfBirth"], r.Patient.DateOfBirth.Format("2006-01-02"))
+	}
+
+	l.section(t["diagnosis"])
+	l.row(t["diagnosisId"], d.ID.String())
+	l.row(t["diagnosisDate"], formatTime(d.CreatedAt, loc))
+	l.row(t["status"], translateValue(t, "status.", d.Status))
+	l.row(t["version"], strconv.Itoa(d.Version))
+
+	l.section(t["inputs"])
+	l.row(t["age"], fmt.Sprintf("%d %s", d.Age, t["years"]))
+	l.row(t["sex"], vocabulary.SexField.Label(d.Sex, string(lang)))
+	l.row(t["chestPainType"], vocabulary.ChestPainTypeField.Label(d.ChestPainType, string(lang)))
+	l.row(t["restingBloodPressure"], formatNumber(d.RestingBloodPressure)+" mmHg")
+	l.row(t["serumCholesterol"], formatNumber(d.SerumCholesterol)+" mg/dL")
+	l.row(t["fastingBloodSugar"], formatNumber(d.FastingBloodSugar)+" mg/dL")
+	l.row(t["restingEcgResults"], vocabulary.RestingEcgField.Label(d.RestingEcgResults, string(lang)))
+	l.row(t["maximumHeartRate"], fmt.Sprintf("%d bpm", d.MaximumHeartRate))
+	l.row(t["exerciseInducedAngina"], vocabulary.ExerciseAnginaField.Label(d.ExerciseInducedAngina, string(lang)))
+	l.row(t["stDepression"], formatNumber(d.StDepression)+" mm")
+	l.row(t["stSegment"], vocabulary.StSlopeField.Label(d.StSegment, string(lang)))
+	l.row(t["majorVessels"], strconv.Itoa(d.MajorVessels))
+	l.row(t["thalassemia"], vocabulary.ThalassemiaField.Label(d.Thalassemia, string(lang)))
+
+	l.section(t["result"])
+	l.row(t["resultPercentage"], formatNumber(d.ResultPercentage)+"%")
+	l.row(t["cardiovascularRisk"], translateValue(t, "risk.", d.CardiovascularRisk))
+	l.row(t["prediction"], translateValue(t, "prediction.", d.Prediction))
+	l.row(t["modelVersion"], valueOrDash(d.ModelVersion))
+
+	if strings.TrimSpace(d.ClinicalNotes) != "" {
+		l.section(t["clinicalNotes"])
+		l.paragraph(fontRegular, 10, d.ClinicalNotes)
+	}
+
+	l.section(t["signOff"])
+	l.row(t["createdBy"], userLabel(d.CreatedBy != nil, d.Creator, t))
+	if d.ReviewedBy != nil {
+		l.row(t["reviewedBy"], userLabel(true, d.Reviewer, t))
+		if d.ReviewedAt != nil {
+			l.row(t["reviewedAt"], formatTime(*d.ReviewedAt, loc))
+		}
+		if strings.TrimSpace(d.ReviewNotes) != "" {
+			l.row(t["reviewNotes"], d.ReviewNotes)
+		}
+	} else {
+		l.row(t["reviewedBy"], t["notReviewed"])
+	}
+
+	l.section(t["disclaimerTitle"])
+	l.paragraph(fontRegular, 9, t["disclaimer"])
+
+	l.footer(t["page"], t["of"])
+
+	_, err := l.doc.WriteTo(w)
+	return err
+}
+
+// layout menyimpan posisi kursor dan otomatis pindah halaman jika ruang habis
+type layout struct {
+	doc  *pdfDocument
+	page int
+	y    float64
+}
+
+func newLayout(doc *pdfDocument) *layout {
+	l := &layout{doc: doc}
+	l.newPage()
+	return l
+}
+
+func (l *layout) newPage() {
+	l.page = l.doc.addPage()
+	l.y = marginTop
+}
+
+func (l *layout) ensure(height float64) {
+	if l.y+height > pageHeight-marginBottom {
+		l.newPage()
+	}
+}
+
+func (l *layout) space(h float64) {
+	l.y += h
+}
+
+func (l *layout) write(font pdfFont, size float64, s string) {
+	lineHeight := size * 1.4
+	l.ensure(lineHeight)
+	l.y += lineHeight
+	l.doc.text(l.page, marginX, l.y, font, size, s)
+}
+
+func (l *layout) paragraph(font pdfFont, size float64, s string) {
+	for _, line := range wrapText(s, font, size, contentWidth) {
+		l.write(font, size, line)
+	}
+}
+
+func (l *layout) section(title string) {
+	l.ensure(40)
+	l.space(12)
+	l.write(fontBold, 12, title)
+	l.space(4)
+	l.doc.line(l.page, marginX, l.y, pageWidth-marginX, l.y)
+	l.space(2)
+}
+
+// row menulis pasangan label-nilai dua kolom; nilai panjang dibungkus ke baris berikutnya
+func (l *layout) row(label, value string) {
+	const size = 10
+	const lineHeight = size * 1.5
+
+	lines := wrapText(value, fontRegular, size, contentWidth-labelWidth)
+	l.ensure(lineHeight * float64(len(lines)))
+	for i, line := range lines {
+		l.y += lineHeight
+		if i == 0 {
+			l.doc.text(l.page, marginX, l.y, fontBold, size, label)
+		}
+		l.doc.text(l.page, marginX+labelWidth, l.y, fontRegular, size, line)
+	}
+}
+
+// footer menulis nomor halaman di setiap halaman setelah jumlah halaman diketahui
+func (l *layout) footer(pageWord, ofWord string) {
+	total := len(l.doc.pages)
+	for i := range total {
+		s := fmt.Sprintf("%s %d %s %d", pageWord, i+1, ofWord, total)
+		x := pageWidth - marginX - textWidth(s, fontRegular, 8)
+		l.doc.text(i, x, pageHeight-marginBottom/2, fontRegular, 8, s)
+	}
+}
+
+func formatTime(t time.Time, loc *time.Location) string {
+	return t.In(loc).Format("2006-01-02 15:04 MST")
+}
+
+func formatNumber(f float64) string {
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
+
+func valueOrDash(s string) string {
+	if strings.TrimSpace(s) == "" {
+		return "-"
+	}
+	return s
+}
+
+func translateValue(t map[string]string, prefix, value string) string {
+	if translated, ok := t[prefix+value]; ok {
+		return translated
+	}
+	return value
+}
+
+func userLabel(present bool, u entity.User, t map[string]string) string {
+	if !present || u.Name == "" {
+		return "-"
+	}
+	return fmt.Sprintf("%s (%s)", u.Name, translateValue(t, "role.", u.Role))
+}
+
+var translations = map[Language]map[string]string{
+	LangID: {
+		"title":                 "Laporan Hasil Diagnosis Risiko Penyakit Jantung",
+		"generatedAt":           "Dibuat pada",
+		"patient":               "Data Pasien",
+		"patientName":           "Nama",
+		"patientId":             "ID Pasien",
+		"dateOfBirth":           "Tanggal Lahir",
+		"diagnosis":             "Data Diagnosis",
+		"diagnosisId":           "ID Diagnosis",
+		"diagnosisDate":         "Tanggal Diagnosis",
+		"status":                "Status",
+		"version":               "Versi",
+		"inputs":                "Data Klinis",
+		"age":                   "Usia",
+		"years":                 "tahun",
+		"sex":                   "Jenis Kelamin",
+		"chestPainType":         "Tipe Nyeri Dada",
+		"restingBloodPressure":  "Tekanan Darah Istirahat",
+		"serumCholesterol":      "Kolesterol Serum",
+		"fastingBloodSugar":     "Gula Darah Puasa",
+		"restingEcgResults":     "Hasil EKG Istirahat",
+		"maximumHeartRate":      "Detak Jantung Maksimum",
+		"exerciseInducedAngina": "Angina Akibat Olahraga",
+		"stDepression":          "Depresi ST (Oldpeak)",
+		"stSegment":             "Kemiringan Segmen ST",
+		"majorVessels":          "Jumlah Pembuluh Utama",
+		"thalassemia":           "Thalassemia",
+		"result":                "Hasil Prediksi",
+		"resultPercentage":      "Persentase Risiko",
+		"cardiovascularRisk":    "Kategori Risiko",
+		"prediction":            "Prediksi",
+		"modelVersion":          "Versi Model",
+		"clinicalNotes":         "Catatan Klinis",
+		"signOff":               "Pemeriksa",
+		"createdBy":             "Dibuat oleh",
+		"reviewedBy":            "Direview oleh",
+		"reviewedAt":            "Tanggal Review",
+		"reviewNotes":           "Catatan Review",
+		"notReviewed":           "Belum direview",
+		"disclaimerTitle":       "Penafian",
+		"disclaimer": "Hasil ini dihasilkan oleh model machine learning sebagai alat bantu skrining dan " +
+			"bukan merupakan diagnosis medis. Keputusan klinis harus diambil oleh dokter berdasarkan " +
+			"pemeriksaan lengkap. Bawa laporan ini saat berkonsultasi dengan dokter spesialis jantung.",
+		"page":                  "Halaman",
+		"of":                    "dari",
+		"status.draft":          "Draf",
+		"status.pending_review": "Menunggu review",
+		"status.reviewed":       "Sudah direview",
+		"status.signed":         "Ditandatangani",
+		"risk.High Risk":        "Risiko tinggi",
+		"risk.Low":              "Risiko rendah",
+		"prediction.Berisiko":   "Berisiko",
+		"role.admin":            "Admin",
+		"role.dokter":           "Dokter",
+		"role.user":             "Pasien",
+	},
+	LangEN: {
+		"title":                 "Heart Disease Risk Diagnosis Report",
+		"generatedAt":           "Generated at",
+		"patient":               "Patient Information",
+		"patientName":           "Name",
+		"patientId":             "Patient ID",
+		"dateOfBirth":           "Date of Birth",
+		"diagnosis":             "Diagnosis Information",
+		"diagnosisId":           "Diagnosis ID",
+		"diagnosisDate":         "Diagnosis Date",
+		"status":                "Status",
+		"version":               "Version",
+		"inputs":                "Clinical Data",
+		"age":                   "Age",
+		"years":                 "years",
+		"sex":                   "Sex",
+		"chestPainType":         "Chest Pain Type",
+		"restingBloodPressure":  "Resting Blood Pressure",
+		"serumCholesterol":      "Serum Cholesterol",
+		"fastingBloodSugar":     "Fasting Blood Sugar",
+		"restingEcgResults":     "Resting ECG Results",
+		"maximumHeartRate":      "Maximum Heart Rate",
+		"exerciseInducedAngina": "Exercise Induced Angina",
+		"stDepression":          "ST Depression (Oldpeak)",
+		"stSegment":             "ST Segment Slope",
+		"majorVessels":          "Major Vessels",
+		"thalassemia":           "Thalassemia",
+		"result":                "Prediction Result",
+		"resultPercentage":      "Risk Percentage",
+		"cardiovascularRisk":    "Risk Category",
+		"prediction":            "Prediction",
+		"modelVersion":          "Model Version",
+		"clinicalNotes":         "Clinical Notes",
+		"signOff":               "Clinicians",
+		"createdBy":             "Created by",
+		"reviewedBy":            "Reviewed by",
+		"reviewedAt":            "Review Date",
+		"reviewNotes":           "Review Notes",
+		"notReviewed":           "Not reviewed yet",
+		"disclaimerTitle":       "Disclaimer",
+		"disclaimer": "This result was produced by a machine learning model as a screening aid and is " +
+			"not a medical diagnosis. Clinical decisions must be made by a physician based on a full " +
+			"examination. Bring this report when consulting a cardiologist.",
+		"page":                      "Page",
+		"of":                        "of",
+		"status.draft":              "Draft",
+		"status.pending_review":     "Pending review",
+		"status.reviewed":           "Reviewed",
+		"status.signed":             "Signed",
+		"risk.High Risk":            "High risk",
+		"risk.Low":                  "Low risk",
+		"prediction.Berisiko":       "At risk",
+		"prediction.Tidak Berisiko": "Not at risk",
+		"role.admin":                "Admin",
+		"role.dokter":               "Doctor",
+		"role.user":                 "Patient",
+	},
+}
@@ -0,0 +1,248 @@
+package report
+
+import (
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// Penulis PDF minimal: halaman A4, font standar Helvetica (tidak perlu embed font),
+// teks, garis, dan kotak berwarna. Cukup untuk laporan satu-dua halaman tanpa
+// dependency eksternal.
+
+const (
+	pageWidth  = 595.28 // A4 dalam point
+	pageHeight = 841.89
+)
+
+type pdfFont int
+
+const (
+	fontRegular pdfFont = iota
+	fontBold
+)
+
+func (f pdfFont) resourceName() string {
+	if f == fontBold {
+		return "F2"
+	}
+	return "F1"
+}
+
+type pdfDocument struct {
+	title   string
+	created time.Time
+	pages   []*bytes.Buffer
+}
+
+func newPDFDocument(title string, created time.Time) *pdfDocument {
+	return &pdfDocument{title: title, created: created}
+}
+
+// addPage membuat halaman baru dan mengembalikan indeksnya
+func (d *pdfDocument) addPage() int {
+	d.pages = append(d.pages, &bytes.Buffer{})
+	return len(d.pages) - 1
+}
+
+// text menulis teks satu baris pada halaman; y dihitung dari atas halaman
+func (d *pdfDocument) text(page int, x, y float64, font pdfFont, size float64, s string) {
+	fmt.Fprintf(d.pages[page], "BT /%s %.1f Tf %.2f %.2f Td (%s) Tj ET\n",
+		font.resourceName(), size, x, pageHeight-y, escapePDFString(s))
+}
+
+// line menggambar garis tipis abu-abu
+func (d *pdfDocument) line(page int, x1, y1, x2, y2 float64) {
+	fmt.Fprintf(d.pages[page], "0.6 0.6 0.6 RG 0.5 w %.2f %.2f m %.2f %.2f l S\n",
+		x1, pageHeight-y1, x2, pageHeight-y2)
+}
+
+// fillRect menggambar kotak berwarna (r, g, b dalam rentang 0-1) lalu mengembalikan warna isi ke hitam
+func (d *pdfDocument) fillRect(page int, x, y, w, h, r, g, b float64) {
+	fmt.Fprintf(d.pages[page], "%.3f %.3f %.3f rg %.2f %.2f %.2f %.2f re f 0 0 0 rg\n",
+		r, g, b, x, pageHeight-y-h, w, h)
+}
+
+// WriteTo menyusun objek PDF beserta tabel xref
+func (d *pdfDocument) WriteTo(w io.Writer) (int64, error) {
+	var buf bytes.Buffer
+	var offsets []int
+
+	beginObj := func() int {
+		offsets = append(offsets, buf.Len())
+		id := len(offsets)
+		fmt.Fprintf(&buf, "%d 0 obj\n", id)
+		return id
+	}
+	endObj := func() {
+		buf.WriteString("endobj\n")
+	}
+
+	buf.WriteString("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+
+	// 1: catalog, 2: pages, 3-4: font, 5: info. Halaman mulai dari objek 6.
+	const firstPageObj = 6
+	pageObj := func(i int) int { return firstPageObj + i*2 }
+
+	beginObj()
+	buf.WriteString("<< /Type /Catalog /Pages 2 0 R >>\n")
+	endObj()
+
+	beginObj()
+	kids := make([]string, len(d.pages))
+	for i := range d.pages {
+		kids[i] = fmt.Sprintf("%d 0 R", pageObj(i))
+	}
+	fmt.Fprintf(&buf, "<< /Type /Pages /Kids [%s] /Count %d >>\n", strings.Join(kids, " "), len(d.pages))
+	endObj()
+
+	for _, base := range []string{"Helvetica", "Helvetica-Bold"} {
+		beginObj()
+		fmt.Fprintf(&buf, "<< /Type /Font /Subtype /Type1 /BaseFont /%s /Encoding /WinAnsiEncoding >>\n", base)
+		endObj()
+	}
+
+	beginObj()
+	fmt.Fprintf(&buf, "<< /Title (%s) /Producer (JantungIn) /CreationDate (D:%s) >>\n",
+		escapePDFString(d.title), d.created.UTC().Format("20060102150405Z"))
+	endObj()
+
+	for i, content := range d.pages {
+		id := beginObj()
+		fmt.Fprintf(&buf, "<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %.2f %.2f] "+
+			"/Resources << /Font << /F1 3 0 R /F2 4 0 R >> >> /Contents %d 0 R >>\n",
+			pageWidth, pageHeight, id+1)
+		endObj()
+
+		var compressed bytes.Buffer
+		zw := zlib.NewWriter(&compressed)
+		if _, err := zw.Write(content.Bytes()); err != nil {
+			return 0, err
+		}
+		if err := zw.Close(); err != nil {
+			return 0, err
+		}
+
+		if got := beginObj(); got != pageObj(i)+1 {
+			return 0, fmt.Errorf("unexpected pdf object id %d", got)
+		}
+		fmt.Fprintf(&buf, "<< /Length %d /Filter /FlateDecode >>\nstream\n", compressed.Len())
+		buf.Write(compressed.Bytes())
+		buf.WriteString("\nendstream\n")
+		endObj()
+	}
+
+	xrefOffset := buf.Len()
+	fmt.Fprintf(&buf, "xref\n0 %d\n0000000000 65535 f \n", len(offsets)+1)
+	for _, off := range offsets {
+		fmt.Fprintf(&buf, "%010d 00000 n \n", off)
+	}
+	fmt.Fprintf(&buf, "trailer\n<< /Size %d /Root 1 0 R /Info 5 0 R >>\nstartxref\n%d\n%%%%EOF\n",
+		len(offsets)+1, xrefOffset)
+
+	n, err := w.Write(buf.Bytes())
+	return int64(n), err
+}
+
+// escapePDFString mengubah teks ke WinAnsiEncoding dan meng-escape karakter khusus PDF.
+// Karakter di luar WinAnsi diganti "?".
+func escapePDFString(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		c, ok := toWinAnsi(r)
+		if !ok {
+			c = '?'
+		}
+		switch c {
+		case '(', ')', '\\':
+			b.WriteByte('\\')
+			b.WriteByte(c)
+		case '\n', '\r', '\t':
+			b.WriteByte(' ')
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return b.String()
+}
+
+var winAnsiSpecial = map[rune]byte{
+	'€': 0x80, '‚': 0x82, '„': 0x84, '…': 0x85, '•': 0x95,
+	'‘': 0x91, '’': 0x92, '“': 0x93, '”': 0x94, '–': 0x96, '—': 0x97,
+}
+
+func toWinAnsi(r rune) (byte, bool) {
+	switch {
+	case r >= 0x20 && r < 0x7f, r == '\n', r == '\r', r == '\t':
+		return byte(r), true
+	case r >= 0xa0 && r <= 0xff:
+		return byte(r), true
+	}
+	c, ok := winAnsiSpecial[r]
+	return c, ok
+}
+
+// textWidth menghitung lebar teks dalam point memakai metrik Helvetica
+func textWidth(s string, font pdfFont, size float64) float64 {
+	widths := &helveticaWidths
+	if font == fontBold {
+		widths = &helveticaBoldWidths
+	}
+
+	var total int
+	for _, r := range s {
+		if r >= 0x20 && r < 0x7f {
+			total += widths[r-0x20]
+		} else {
+			total += 556
+		}
+	}
+	return float64(total) * size / 1000
+}
+
+// wrapText memecah teks menjadi beberapa baris agar tidak melebihi maxWidth
+func wrapText(s string, font pdfFont, size, maxWidth float64) []string {
+	var lines []string
+	for _, paragraph := range strings.Split(s, "\n") {
+		words := strings.Fields(paragraph)
+		if len(words) == 0 {
+			lines = append(lines, "")
+			continue
+		}
+
+		current := words[0]
+		for _, word := range words[1:] {
+			candidate := current + " " + word
+			if textWidth(candidate, font, size) > maxWidth {
+				lines = append(lines, current)
+				current = word
+				continue
+			}
+			current = candidate
+		}
+		lines = append(lines, current)
+	}
+	return lines
+}
+
+// Lebar glyph (per 1000 unit) untuk karakter ASCII 0x20-0x7E, dari AFM Adobe Core14
+var helveticaWidths = [95]int{
+	278, 278, 355, 556, 556, 889, 667, 191, 333, 333, 389, 584, 278, 333, 278, 278,
+	556, 556, 556, 556, 556, 556, 556, 556, 556, 556, 278, 278, 584, 584, 584, 556,
+	1015, 667, 667, 722, 722, 667, 611, 778, 722, 278, 500, 667, 556, 833, 722, 778,
+	667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611, 278, 278, 278, 469, 556,
+	333, 556, 556, 500, 556, 556, 278, 556, 556, 222, 222, 500, 222, 833, 556, 556,
+	556, 556, 333, 500, 278, 556, 500, 722, 500, 500, 500, 334, 260, 334, 584,
+}
+
+var helveticaBoldWidths = [95]int{
+	278, 333, 474, 556, 556, 889, 722, 238, 333, 333, 389, 584, 278, 333, 278, 278,
+	556, 556, 556, 556, 556, 556, 556, 556, 556, 556, 333, 333, 584, 584, 584, 611,
+	975, 722, 722, 722, 722, 667, 611, 778, 722, 278, 556, 722, 611, 833, 722, 778,
+	667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611, 333, 278, 333, 584, 556,
+	333, 556, 611, 556, 611, 556, 333, 611, 611, 278, 278, 556, 278, 889, 611, 611,
+	611, 611, 389, 556, 333, 611, 556, 778, 556, 556, 500, 389, 280, 389, 584,
+}
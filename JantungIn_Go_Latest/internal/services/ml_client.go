@@ -4,11 +4,36 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
+	"sync"
 	"time"
 )
 
+// Predictor adalah kontrak prediksi risiko kardiovaskular. MLClient adalah
+// implementasinya; usecase bergantung pada interface ini.
+type Predictor interface {
+	Predict(ctx context.Context, req MLPredictRequest) (*MLPredictResult, error)
+	// PredictBatch mengembalikan satu BatchResult per request dengan urutan yang sama.
+	// Kegagalan satu item (atau satu chunk) tidak menggagalkan item lain.
+	PredictBatch(ctx context.Context, reqs []MLPredictRequest) []BatchResult
+}
+
+// BatchResult adalah hasil satu item PredictBatch: Result terisi jika Err nil.
+type BatchResult struct {
+	Result *MLPredictResult
+	Err    error
+}
+
+const (
+	DefaultBatchSize        = 50
+	DefaultBatchConcurrency = 4
+
+	// Batas ukuran batch di sisi ML service (MAX_BATCH_SIZE)
+	maxServiceBatchSize = 100
+)
+
 type MLPredictRequest struct {
 	Age                   int     `json:"age"`
 	Sex                   string  `json:"sex"`
@@ -37,17 +62,54 @@ type mlPredictResponse struct {
 	Data    MLPredictResult `json:"data"`
 }
 
+type mlBatchRequest struct {
+	Items []MLPredictRequest `json:"items"`
+}
+
+type mlBatchItem struct {
+	Index   int              `json:"index"`
+	Success bool             `json:"success"`
+	Data    *MLPredictResult `json:"data"`
+	Error   string           `json:"error"`
+}
+
+type mlBatchResponse struct {
+	Success bool          `json:"success"`
+	Message string        `json:"message"`
+	Data    []mlBatchItem `json:"data"`
+}
+
+// errBatchUnsupported menandakan ML service versi lama tanpa endpoint /predict/batch
+var errBatchUnsupported = errors.New("ML service does not support batch prediction")
+
 type MLClient struct {
-	baseURL    string
-	httpClient *http.Client
+	baseURL        string
+	httpClient     *http.Client
+	batchSize      int
+	maxConcurrency int
 }
 
-func NewMLClient(baseURL string) *MLClient {
+// NewMLClient membuat client ML service. batchSize adalah jumlah record per
+// request /predict/batch dan maxConcurrency jumlah request batch yang berjalan
+// bersamaan; nilai <= 0 memakai default.
+func NewMLClient(baseURL string, batchSize int, maxConcurrency int) *MLClient {
+	if batchSize <= 0 {
+		batchSize = DefaultBatchSize
+	}
+	if batchSize > maxServiceBatchSize {
+		batchSize = maxServiceBatchSize
+	}
+	if maxConcurrency <= 0 {
+		maxConcurrency = DefaultBatchConcurrency
+	}
+
 	return &MLClient{
 		baseURL: baseURL,
 		httpClient: &http.Client{
 			Timeout: 10 * time.Second,
 		},
+		batchSize:      batchSize,
+		maxConcurrency: maxConcurrency,
 	}
 }
 
@@ -85,3 +147,153 @@ func (c *MLClient) Predict(ctx context.Context, req MLPredictRequest) (*MLPredic
 
 	return &mlResp.Data, nil
 }
+
+// PredictBatch membagi reqs menjadi chunk sebesar batchSize dan mengirimnya ke
+// /predict/batch, maksimal maxConcurrency chunk sekaligus. Jika ML service belum
+// mendukung batch, chunk tersebut diprediksi satu per satu lewat Predict.
+func (c *MLClient) PredictBatch(ctx context.Context, reqs []MLPredictRequest) []BatchResult {
+	results := make([]BatchResult, len(reqs))
+	if len(reqs) == 0 {
+		return results
+	}
+
+	sem := make(chan struct{}, c.maxConcurrency)
+	var wg sync.WaitGroup
+
+	for start := 0; start < len(reqs); start += c.batchSize {
+		end := min(start+c.batchSize, len(reqs))
+
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			for i := start; i < len(reqs); i++ {
+				results[i].Err = ctx.Err()
+			}
+			wg.Wait()
+			return results
+		}
+
+		wg.Add(1)
+		go func(start, end int) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			// Setiap goroutine hanya menulis ke rentang results miliknya sendiri
+			c.predictChunk(ctx, reqs[start:end], results[start:end])
+		}(start, end)
+	}
+	wg.Wait()
+
+	return results
+}
+
+func (c *MLClient) predictChunk(ctx context.Context, reqs []MLPredictRequest, results []BatchResult) {
+	items, err := c.callBatch(ctx, reqs)
+	if errors.Is(err, errBatchUnsupported) {
+		for i, r := range reqs {
+			results[i].Result, results[i].Err = c.Predict(ctx, r)
+		}
+		return
+	}
+	if err != nil {
+		for i := range results {
+			results[i].Err = err
+		}
+		return
+	}
+
+	received := make([]bool, len(reqs))
+	for _, item := range items {
+		if item.Index < 0 || item.Index >= len(reqs) {
+			continue
+		}
+		received[item.Index] = true
+
+		switch {
+		case !item.Success:
+			results[item.Index].Err = fmt.Errorf("ML service rejected item: %s", item.Error)
+		case item.Data == nil:
+			results[item.Index].Err = errors.New("ML service returned empty prediction")
+		default:
+			results[item.Index].Result = item.Data
+		}
+	}
+
+	for i, ok := range received {
+		if !ok {
+			results[i].Err = errors.New("ML service returned no result for item")
+		}
+	}
+}
+
+func (c *MLClient) callBatch(ctx context.Context, reqs []MLPredictRequest) ([]mlBatchItem, error) {
+	body, err := json.Marshal(mlBatchRequest{Items: reqs})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal batch predict request: %w", err)
+	}
+
+	url := fmt.Sprintf("%s/api/v1/predict/batch", c.baseURL)
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewBuffer(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create http request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.httpClient.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call ML service: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound || resp.StatusCode == http.StatusMethodNotAllowed {
+		return nil, errBatchUnsupported
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("ML service returned status %d", resp.StatusCode)
+	}
+
+	var mlResp mlBatchResponse
+	if err := json.NewDecoder(resp.Body).Decode(&mlResp); err != nil {
+		return nil, fmt.Errorf("failed to decode ML batch response: %w", err)
+	}
+
+	if !mlResp.Success {
+		return nil, fmt.Errorf("ML service error: %s", mlResp.Message)
+	}
+
+	return mlResp.Data, nil
+}
+
+type mlHealthResponse struct {
+	Status      string `json:"status"`
+	ModelLoaded bool   `json:"model_loaded"`
+}
+
+// Health memanggil GET /health ML service. ML service yang hidup tetapi belum
+// memuat model dianggap tidak sehat karena semua prediksi akan gagal.
+func (c *MLClient) Health(ctx context.Context) error {
+	url := fmt.Sprintf("%s/health", c.baseURL)
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create http request: %w", err)
+	}
+
+	resp, err := c.httpClient.Do(httpReq)
+	if err != nil {
+		return fmt.Errorf("failed to call ML service: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("ML service returned status %d", resp.StatusCode)
+	}
+
+	var health mlHealthResponse
+	if err := json.NewDecoder(resp.Body).Decode(&health); err != nil {
+		return fmt.Errorf("failed to decode ML health response: %w", err)
+	}
+	if !health.ModelLoaded {
+		return errors.New("ML model is not loaded")
+	}
+	return nil
+}
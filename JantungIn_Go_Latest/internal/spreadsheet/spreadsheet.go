@@ -0,0 +1,113 @@
+package spreadsheet
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	FormatCSV  = "csv"
+	FormatXLSX = "xlsx"
+)
+
+// Table adalah isi sheet: baris pertama sebagai header, sisanya data.
+// Baris yang seluruh selnya kosong dilewati.
+type Table struct {
+	Header []string
+	Rows   []Row
+}
+
+// Row adalah satu baris data. Line adalah nomor baris di file (header = 1).
+type Row struct {
+	Line   int
+	Values []string
+}
+
+// Get mengembalikan nilai kolom ke-i, string kosong jika baris lebih pendek dari header
+func (r Row) Get(i int) string {
+	if i < 0 || i >= len(r.Values) {
+		return ""
+	}
+	return strings.TrimSpace(r.Values[i])
+}
+
+// DetectFormat menentukan format dari ekstensi nama file
+func DetectFormat(filename string) (string, error) {
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".csv":
+		return FormatCSV, nil
+	case ".xlsx":
+		return FormatXLSX, nil
+	}
+	return "", fmt.Errorf("format file %q tidak didukung, gunakan .csv atau .xlsx", filepath.Ext(filename))
+}
+
+// Parse membaca file CSV atau XLSX (sheet pertama) menjadi Table
+func Parse(format string, data []byte) (*Table, error) {
+	var records [][]string
+	var err error
+
+	switch format {
+	case FormatCSV:
+		records, err = readCSV(data)
+	case FormatXLSX:
+		records, err = readXLSX(data)
+	default:
+		return nil, fmt.Errorf("format %q tidak didukung", format)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	table := &Table{}
+	for i, record := range records {
+		if isBlank(record) {
+			continue
+		}
+		if table.Header == nil {
+			table.Header = record
+			continue
+		}
+		table.Rows = append(table.Rows, Row{Line: i + 1, Values: record})
+	}
+
+	if table.Header == nil {
+		return nil, errors.New("file kosong atau tidak memiliki header")
+	}
+	return table, nil
+}
+
+// readCSV membaca CSV dengan pemisah koma atau titik koma (default Excel berbahasa Indonesia)
+func readCSV(data []byte) ([][]string, error) {
+	data = bytes.TrimPrefix(data, []byte("\xef\xbb\xbf")) // BOM dari Excel
+
+	firstLine, _, _ := bytes.Cut(data, []byte("\n"))
+	delimiter := ','
+	if bytes.Count(firstLine, []byte(";")) > bytes.Count(firstLine, []byte(",")) {
+		delimiter = ';'
+	}
+
+	reader := csv.NewReader(bytes.NewReader(data))
+	reader.Comma = delimiter
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("gagal membaca CSV: %w", err)
+	}
+	return records, nil
+}
+
+func isBlank(record []string) bool {
+	for _, v := range record {
+		if strings.TrimSpace(v) != "" {
+			return false
+		}
+	}
+	return true
+}
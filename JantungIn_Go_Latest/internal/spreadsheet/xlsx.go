@@ -0,0 +1,221 @@
+package spreadsheet
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strconv"
+	"strings"
+)
+
+// Pembaca XLSX minimal: hanya sheet pertama, nilai sel sebagai teks.
+// Formula dibaca dari nilai cache-nya; tanggal tetap berupa serial number Excel.
+
+const maxXLSXPartSize = 50 << 20 // batas ukuran satu file XML di dalam zip
+
+type xlsxWorkbook struct {
+	Sheets []struct {
+		Name string `xml:"name,attr"`
+		RID  string `xml:"http://schemas.openxmlformats.org/officeDocument/2006/relationships id,attr"`
+	} `xml:"sheets>sheet"`
+}
+
+type xlsxRelationships struct {
+	Relationships []struct {
+		ID     string `xml:"Id,attr"`
+		Target string `xml:"Target,attr"`
+	} `xml:"Relationship"`
+}
+
+type xlsxSharedStrings struct {
+	Items []xlsxRichText `xml:"si"`
+}
+
+// xlsxRichText bisa berupa <t> langsung atau beberapa run <r><t>
+type xlsxRichText struct {
+	Text string `xml:"t"`
+	Runs []struct {
+		Text string `xml:"t"`
+	} `xml:"r"`
+}
+
+func (t xlsxRichText) String() string {
+	if len(t.Runs) == 0 {
+		return t.Text
+	}
+	var b strings.Builder
+	b.WriteString(t.Text)
+	for _, r := range t.Runs {
+		b.WriteString(r.Text)
+	}
+	return b.String()
+}
+
+type xlsxSheet struct {
+	Rows []struct {
+		Index int `xml:"r,attr"`
+		Cells []struct {
+			Ref       string        `xml:"r,attr"`
+			Type      string        `xml:"t,attr"`
+			Value     string        `xml:"v"`
+			InlineStr *xlsxRichText `xml:"is"`
+		} `xml:"c"`
+	} `xml:"sheetData>row"`
+}
+
+func readXLSX(data []byte) ([][]string, error) {
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, fmt.Errorf("file XLSX tidak valid: %w", err)
+	}
+
+	files := make(map[string]*zip.File, len(zr.File))
+	for _, f := range zr.File {
+		files[f.Name] = f
+	}
+
+	sheetPath, err := firstSheetPath(files)
+	if err != nil {
+		return nil, err
+	}
+
+	var shared xlsxSharedStrings
+	if f, ok := files["xl/sharedStrings.xml"]; ok {
+		if err := decodeXLSXPart(f, &shared); err != nil {
+			return nil, err
+		}
+	}
+
+	f, ok := files[sheetPath]
+	if !ok {
+		return nil, fmt.Errorf("sheet %s tidak ditemukan di file XLSX", sheetPath)
+	}
+	var sheet xlsxSheet
+	if err := decodeXLSXPart(f, &sheet); err != nil {
+		return nil, err
+	}
+
+	var records [][]string
+	for _, row := range sheet.Rows {
+		index := row.Index
+		if index <= 0 {
+			index = len(records) + 1
+		}
+		// Isi baris kosong yang dilewati agar indeks record = nomor baris - 1
+		for len(records) < index-1 {
+			records = append(records, nil)
+		}
+
+		var record []string
+		for i, cell := range row.Cells {
+			col := i
+			if cell.Ref != "" {
+				if c, err := columnIndex(cell.Ref); err == nil {
+					col = c
+				}
+			}
+			for len(record) <= col {
+				record = append(record, "")
+			}
+
+			switch cell.Type {
+			case "s":
+				n, err := strconv.Atoi(cell.Value)
+				if err != nil || n < 0 || n >= len(shared.Items) {
+					return nil, fmt.Errorf("shared string tidak valid di sel %s", cell.Ref)
+				}
+				record[col] = shared.Items[n].String()
+			case "inlineStr":
+				if cell.InlineStr != nil {
+					record[col] = cell.InlineStr.String()
+				}
+			case "b":
+				record[col] = map[string]string{"1": "true", "0": "false"}[cell.Value]
+			default:
+				record[col] = cell.Value
+			}
+		}
+		records = append(records, record)
+	}
+
+	return records, nil
+}
+
+// firstSheetPath mencari path XML sheet pertama lewat workbook.xml dan relasinya
+func firstSheetPath(files map[string]*zip.File) (string, error) {
+	wbFile, ok := files["xl/workbook.xml"]
+	if !ok {
+		return "", errors.New("file XLSX tidak valid: workbook.xml tidak ditemukan")
+	}
+	var wb xlsxWorkbook
+	if err := decodeXLSXPart(wbFile, &wb); err != nil {
+		return "", err
+	}
+	if len(wb.Sheets) == 0 {
+		return "", errors.New("file XLSX tidak memiliki sheet")
+	}
+
+	relsFile, ok := files["xl/_rels/workbook.xml.rels"]
+	if !ok {
+		return "xl/worksheets/sheet1.xml", nil
+	}
+	var rels xlsxRelationships
+	if err := decodeXLSXPart(relsFile, &rels); err != nil {
+		return "", err
+	}
+
+	for _, rel := range rels.Relationships {
+		if rel.ID != wb.Sheets[0].RID {
+			continue
+		}
+		if strings.HasPrefix(rel.Target, "/") {
+			return strings.TrimPrefix(rel.Target, "/"), nil
+		}
+		return path.Join("xl", rel.Target), nil
+	}
+	return "xl/worksheets/sheet1.xml", nil
+}
+
+func decodeXLSXPart(f *zip.File, v any) error {
+	if f.UncompressedSize64 > maxXLSXPartSize {
+		return fmt.Errorf("bagian %s pada file XLSX terlalu besar", f.Name)
+	}
+
+	rc, err := f.Open()
+	if err != nil {
+		return fmt.Errorf("gagal membuka %s: %w", f.Name, err)
+	}
+	defer rc.Close()
+
+	if err := xml.NewDecoder(io.LimitReader(rc, maxXLSXPartSize)).Decode(v); err != nil {
+		return fmt.Errorf("gagal membaca %s: %w", f.Name, err)
+	}
+	return nil
+}
+
+// columnIndex mengubah referensi sel seperti "C12" menjadi indeks kolom 0-based (2)
+func columnIndex(ref string) (int, error) {
+	col := 0
+	n := 0
+	for _, r := range ref {
+		if r >= 'A' && r <= 'Z' {
+			col = col*26 + int(r-'A'+1)
+			n++
+			continue
+		}
+		if r >= 'a' && r <= 'z' {
+			col = col*26 + int(r-'a'+1)
+			n++
+			continue
+		}
+		break
+	}
+	if n == 0 {
+		return 0, fmt.Errorf("referensi sel %q tidak valid", ref)
+	}
+	return col - 1, nil
+}
@@ -0,0 +1,428 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"jantungin-api-server/internal/data/entity"
+	"jantungin-api-server/internal/data/repository"
+	"jantungin-api-server/internal/dto"
+	"jantungin-api-server/internal/vocabulary"
+	"jantungin-api-server/pkg/background"
+	"jantungin-api-server/pkg/mailer"
+	"jantungin-api-server/pkg/utils"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+const (
+	alertEvaluationTimeout = 30 * time.Second
+
+	// Evaluasi alert dijalankan oleh sejumlah worker tetap dari antrean berukuran tetap,
+	// sehingga lonjakan diagnosis tidak memicu evaluasi, query, dan email tanpa batas.
+	// Jika antrean penuh, alert dilewati dan dicatat di log.
+	alertWorkers   = 4
+	alertQueueSize = 256
+)
+
+type AlertUsecase interface {
+	NotifyDiagnosis(diagnosis entity.Diagnosis)
+	GetNotifications(ctx context.Context, userID string, unacknowledgedOnly bool) ([]entity.Notification, error)
+	AcknowledgeNotification(ctx context.Context, userID string, notificationID string, req dto.AcknowledgeNotificationRequest) (*entity.Notification, error)
+	GetAlertRules(ctx context.Context) ([]entity.AlertRule, error)
+	CreateAlertRule(ctx context.Context, req dto.AlertRuleRequest) (*entity.AlertRule, error)
+	UpdateAlertRule(ctx context.Context, ruleID string, req dto.AlertRuleRequest) (*entity.AlertRule, error)
+	DeleteAlertRule(ctx context.Context, ruleID string) error
+}
+
+type alertUsecase struct {
+	notificationRepo repository.NotificationRepository
+	diagnosisRepo    repository.DiagnosisRepository
+	userRepo         repository.UserRepository
+	mailer           mailer.Mailer
+	runner           *background.Runner
+	queue            chan entity.Diagnosis
+}
+
+func NewAlertUsecase(
+	notificationRepo repository.NotificationRepository,
+	diagnosisRepo repository.DiagnosisRepository,
+	userRepo repository.UserRepository,
+	mail mailer.Mailer,
+	runner *background.Runner,
+) AlertUsecase {
+	u := &alertUsecase{
+		notificationRepo: notificationRepo,
+		diagnosisRepo:    diagnosisRepo,
+		userRepo:         userRepo,
+		mailer:           mail,
+		runner:           runner,
+	}
+	if runner != nil {
+		u.queue = make(chan entity.Diagnosis, alertQueueSize)
+		for i := 0; i < alertWorkers; i++ {
+			if err := runner.Go(fmt.Sprintf("diagnosis-alert-worker:%d", i), u.work); err != nil {
+				utils.Warn("Diagnosis alert worker not started", zap.Error(err))
+			}
+		}
+	}
+	return u
+}
+
+type skipAlertsKey struct{}
+
+// withoutAlerts menandai ctx agar CreateDiagnosis tidak mengevaluasi alert, dipakai
+// import massal supaya ribuan baris tidak memicu ribuan notifikasi dan email
+func withoutAlerts(ctx context.Context) context.Context {
+	return context.WithValue(ctx, skipAlertsKey{}, true)
+}
+
+func alertsSkipped(ctx context.Context) bool {
+	skipped, _ := ctx.Value(skipAlertsKey{}).(bool)
+	return skipped
+}
+
+// NotifyDiagnosis memasukkan diagnosis yang baru dibuat ke antrean evaluasi alert.
+// Kegagalan hanya dicatat di log dan tidak memengaruhi pembuatan diagnosis.
+func (u *alertUsecase) NotifyDiagnosis(diagnosis entity.Diagnosis) {
+	if u.queue == nil {
+		return
+	}
+
+	select {
+	case u.queue <- diagnosis:
+	default:
+		utils.Warn("Diagnosis alert skipped, queue full",
+			zap.String("diagnosis_id", diagnosis.ID.String()),
+		)
+	}
+}
+
+// work mengevaluasi alert dari antrean sampai runner di-shutdown
+func (u *alertUsecase) work(ctx context.Context) {
+	for {
+		select {
+		case diagnosis := <-u.queue:
+			evalCtx, cancel := context.WithTimeout(ctx, alertEvaluationTimeout)
+			if err := u.evaluate(evalCtx, diagnosis); err != nil {
+				utils.Error("Failed to evaluate diagnosis alerts",
+					zap.String("diagnosis_id", diagnosis.ID.String()),
+					zap.Error(err),
+				)
+			}
+			cancel()
+		case <-ctx.Done():
+			if pending := len(u.queue); pending > 0 {
+				utils.Warn("Diagnosis alerts not evaluated before shutdown", zap.Int("pending", pending))
+			}
+			return
+		}
+	}
+}
+
+func (u *alertUsecase) evaluate(ctx context.Context, diagnosis entity.Diagnosis) error {
+	rules, err := u.notificationRepo.FindEnabledRules(ctx)
+	if err != nil {
+		return err
+	}
+	if len(rules) == 0 {
+		return nil
+	}
+
+	history, err := u.diagnosisRepo.FindByPatientID(ctx, diagnosis.UserID)
+	if err != nil {
+		return err
+	}
+	previous := previousDiagnosis(history, diagnosis)
+
+	var matched []entity.AlertRule
+	for _, rule := range rules {
+		if ruleMatches(rule, diagnosis, previous) {
+			matched = append(matched, rule)
+		}
+	}
+	if len(matched) == 0 {
+		return nil
+	}
+
+	recipient, err := u.resolveRecipient(ctx, diagnosis, previous)
+	if err != nil {
+		return err
+	}
+	if recipient == nil {
+		utils.Warn("Diagnosis alert has no recipient",
+			zap.String("diagnosis_id", diagnosis.ID.String()),
+		)
+		return nil
+	}
+
+	patientName := "pasien"
+	if patient, err := u.userRepo.FindByID(ctx, diagnosis.UserID); err == nil && patient != nil {
+		patientName = patient.Name
+	}
+
+	for _, rule := range matched {
+		notification := &entity.Notification{
+			RecipientID:        recipient.ID,
+			DiagnosisID:        diagnosis.ID,
+			PatientID:          diagnosis.UserID,
+			RuleID:             rule.ID,
+			Type:               rule.Type,
+			Title:              alertTitle(rule),
+			Message:            alertMessage(rule, diagnosis, previous, patientName),
+			ResultPercentage:   diagnosis.ResultPercentage,
+			CardiovascularRisk: diagnosis.CardiovascularRisk,
+			EmailStatus:        entity.NotificationEmailPending,
+		}
+		if !rule.EmailEnabled || recipient.Email == nil || *recipient.Email == "" {
+			notification.EmailStatus = entity.NotificationEmailSkipped
+		}
+
+		created, err := u.notificationRepo.Create(ctx, notification)
+		if err != nil {
+			return err
+		}
+		if !created {
+			continue
+		}
+
+		utils.Info("Diagnosis alert created",
+			zap.String("notification_id", notification.ID.String()),
+			zap.String("diagnosis_id", diagnosis.ID.String()),
+			zap.String("rule", rule.Name),
+			zap.String("recipient_id", recipient.ID.String()),
+		)
+
+		if notification.EmailStatus == entity.NotificationEmailPending {
+			u.sendEmail(ctx, notification, *recipient.Email)
+		}
+	}
+
+	return nil
+}
+
+// sendEmail mengirim alert ke email dokter dan menyimpan status pengirimannya.
+// Email tidak memuat nama pasien; detail hanya bisa dilihat setelah login.
+func (u *alertUsecase) sendEmail(ctx context.Context, notification *entity.Notification, to string) {
+	msg := mailer.Message{
+		To:      []string{to},
+		Subject: "[JantungIn] " + notification.Title,
+		Body: fmt.Sprintf(
+			"%s\n\nID diagnosis: %s\nRisiko: %.0f%% (%s)\n\nSilakan login ke JantungIn untuk melihat detail dan menandai alert ini sudah ditindaklanjuti.",
+			notification.Title,
+			notification.DiagnosisID,
+			notification.ResultPercentage,
+			notification.CardiovascularRisk,
+		),
+	}
+
+	status, emailErr := entity.NotificationEmailSent, ""
+	if err := u.mailer.Send(ctx, msg); err != nil {
+		if errors.Is(err, mailer.ErrDisabled) {
+			status = entity.NotificationEmailSkipped
+		} else {
+			status, emailErr = entity.NotificationEmailFailed, err.Error()
+			utils.Warn("Failed to send diagnosis alert email",
+				zap.String("notification_id", notification.ID.String()),
+				zap.Error(err),
+			)
+		}
+	}
+
+	if err := u.notificationRepo.UpdateEmailStatus(context.WithoutCancel(ctx), notification.ID, status, emailErr); err != nil {
+		utils.Error("Failed to update notification email status",
+			zap.String("notification_id", notification.ID.String()),
+			zap.Error(err),
+		)
+	}
+}
+
+// resolveRecipient menentukan dokter penerima alert: pembuat diagnosis jika dokter/admin.
+// Jika diagnosis tidak dibuat oleh dokter/admin, dipakai pembuat diagnosis pasien sebelumnya
+// sebagai dokter yang menangani pasien. nil jika tidak ada dokter yang bisa dihubungi.
+func (u *alertUsecase) resolveRecipient(ctx context.Context, diagnosis entity.Diagnosis, previous *entity.Diagnosis) (*entity.User, error) {
+	candidates := []*uuid.UUID{diagnosis.CreatedBy}
+	if previous != nil {
+		candidates = append(candidates, previous.CreatedBy)
+	}
+
+	for _, id := range candidates {
+		if id == nil {
+			continue
+		}
+		user, err := u.userRepo.FindByID(ctx, *id)
+		if err != nil {
+			return nil, err
+		}
+		if user != nil && (user.Role == "dokter" || user.Role == "admin") {
+			return user, nil
+		}
+	}
+	return nil, nil
+}
+
+// previousDiagnosis mencari diagnosis pasien terakhir sebelum diagnosis ini.
+// history diurutkan dari yang terbaru (FindByPatientID).
+func previousDiagnosis(history []entity.Diagnosis, diagnosis entity.Diagnosis) *entity.Diagnosis {
+	for i := range history {
+		if history[i].ID != diagnosis.ID && !history[i].CreatedAt.After(diagnosis.CreatedAt) {
+			return &history[i]
+		}
+	}
+	return nil
+}
+
+func ruleMatches(rule entity.AlertRule, diagnosis entity.Diagnosis, previous *entity.Diagnosis) bool {
+	switch rule.Type {
+	case entity.AlertRuleRiskThreshold:
+		return rule.Threshold != nil && diagnosis.ResultPercentage >= *rule.Threshold
+	case entity.AlertRuleCategoryJump:
+		return previous != nil &&
+			!vocabulary.IsHighRisk(previous.CardiovascularRisk) &&
+			vocabulary.IsHighRisk(diagnosis.CardiovascularRisk)
+	default:
+		return false
+	}
+}
+
+func alertTitle(rule entity.AlertRule) string {
+	switch rule.Type {
+	case entity.AlertRuleCategoryJump:
+		return "Risiko pasien naik menjadi High Risk"
+	default:
+		return "Diagnosis dengan risiko kardiovaskular tinggi"
+	}
+}
+
+func alertMessage(rule entity.AlertRule, diagnosis entity.Diagnosis, previous *entity.Diagnosis, patientName string) string {
+	switch rule.Type {
+	case entity.AlertRuleCategoryJump:
+		return fmt.Sprintf("Risiko %s berubah dari %s (%.0f%%) menjadi %s (%.0f%%).",
+			patientName,
+			previous.CardiovascularRisk, previous.ResultPercentage,
+			diagnosis.CardiovascularRisk, diagnosis.ResultPercentage,
+		)
+	default:
+		return fmt.Sprintf("Diagnosis %s menunjukkan risiko %.0f%% (%s), melewati ambang %.0f%% pada aturan \"%s\".",
+			patientName,
+			diagnosis.ResultPercentage, diagnosis.CardiovascularRisk,
+			*rule.Threshold, rule.Name,
+		)
+	}
+}
+
+func (u *alertUsecase) GetNotifications(ctx context.Context, userID string, unacknowledgedOnly bool) ([]entity.Notification, error) {
+	uid, err := uuid.Parse(userID)
+	if err != nil {
+		return nil, errors.New("invalid user ID")
+	}
+
+	return u.notificationRepo.FindByRecipient(ctx, uid, unacknowledgedOnly)
+}
+
+// AcknowledgeNotification menandai alert sudah ditindaklanjuti, hanya oleh penerimanya.
+// Acknowledge ulang tidak mengubah waktu dan catatan acknowledge pertama.
+func (u *alertUsecase) AcknowledgeNotification(ctx context.Context, userID string, notificationID string, req dto.AcknowledgeNotificationRequest) (*entity.Notification, error) {
+	uid, err := uuid.Parse(userID)
+	if err != nil {
+		return nil, errors.New("invalid user ID")
+	}
+	nid, err := uuid.Parse(notificationID)
+	if err != nil {
+		return nil, errors.New("invalid notification ID")
+	}
+
+	notification, err := u.notificationRepo.FindByID(ctx, nid)
+	if err != nil {
+		return nil, err
+	}
+	if notification == nil || notification.RecipientID != uid {
+		return nil, errors.New("notification not found")
+	}
+	if notification.AcknowledgedAt != nil {
+		return notification, nil
+	}
+
+	if err := u.notificationRepo.Acknowledge(ctx, nid, time.Now(), req.Note); err != nil {
+		utils.Error("Failed to acknowledge notification", zap.Error(err))
+		return nil, errors.New("gagal menyimpan acknowledgement")
+	}
+
+	return u.notificationRepo.FindByID(ctx, nid)
+}
+
+func (u *alertUsecase) GetAlertRules(ctx context.Context) ([]entity.AlertRule, error) {
+	return u.notificationRepo.FindRules(ctx)
+}
+
+func (u *alertUsecase) CreateAlertRule(ctx context.Context, req dto.AlertRuleRequest) (*entity.AlertRule, error) {
+	rule := &entity.AlertRule{}
+	if err := applyAlertRule(rule, req); err != nil {
+		return nil, err
+	}
+
+	if err := u.notificationRepo.CreateRule(ctx, rule); err != nil {
+		utils.Error("Failed to create alert rule", zap.Error(err))
+		return nil, errors.New("gagal menyimpan aturan alert")
+	}
+	return rule, nil
+}
+
+func (u *alertUsecase) UpdateAlertRule(ctx context.Context, ruleID string, req dto.AlertRuleRequest) (*entity.AlertRule, error) {
+	rule, err := u.findRule(ctx, ruleID)
+	if err != nil {
+		return nil, err
+	}
+	if err := applyAlertRule(rule, req); err != nil {
+		return nil, err
+	}
+
+	if err := u.notificationRepo.UpdateRule(ctx, rule); err != nil {
+		utils.Error("Failed to update alert rule", zap.Error(err))
+		return nil, errors.New("gagal menyimpan aturan alert")
+	}
+	return rule, nil
+}
+
+func (u *alertUsecase) DeleteAlertRule(ctx context.Context, ruleID string) error {
+	rule, err := u.findRule(ctx, ruleID)
+	if err != nil {
+		return err
+	}
+	return u.notificationRepo.DeleteRule(ctx, rule.ID)
+}
+
+func (u *alertUsecase) findRule(ctx context.Context, ruleID string) (*entity.AlertRule, error) {
+	id, err := uuid.Parse(ruleID)
+	if err != nil {
+		return nil, errors.New("invalid alert rule ID")
+	}
+
+	rule, err := u.notificationRepo.FindRuleByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if rule == nil {
+		return nil, errors.New("alert rule not found")
+	}
+	return rule, nil
+}
+
+func applyAlertRule(rule *entity.AlertRule, req dto.AlertRuleRequest) error {
+	if req.Type == entity.AlertRuleRiskThreshold && req.Threshold == nil {
+		return errors.New("threshold wajib diisi untuk aturan risk_threshold")
+	}
+
+	rule.Name = req.Name
+	rule.Type = req.Type
+	rule.Threshold = nil
+	if req.Type == entity.AlertRuleRiskThreshold {
+		rule.Threshold = req.Threshold
+	}
+	rule.Enabled = req.Enabled == nil || *req.Enabled
+	rule.EmailEnabled = req.EmailEnabled == nil || *req.EmailEnabled
+	return nil
+}
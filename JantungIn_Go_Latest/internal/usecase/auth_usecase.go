@@ -9,6 +9,7 @@ import (
 	"jantungin-api-server/internal/data/entity"
 	"jantungin-api-server/internal/data/repository"
 	"jantungin-api-server/internal/dto"
+	"jantungin-api-server/internal/identity"
 	"jantungin-api-server/pkg/utils"
 
 	"github.com/google/uuid"
@@ -74,6 +75,11 @@ func (u *authUsecase) Register(ctx context.Context, req dto.AuthRegisterRequest)
 		}
 	}
 
+	nik, err := u.checkNIK(ctx, req.NIK, nil)
+	if err != nil {
+		return nil, err
+	}
+
 	// Hash password
 	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(req.Password), bcrypt.DefaultCost)
 	if err != nil {
@@ -88,6 +94,8 @@ func (u *authUsecase) Register(ctx context.Context, req dto.AuthRegisterRequest)
 			return nil, errors.New("format tanggal lahir tidak valid, gunakan YYYY-MM-DD")
 		}
 		dob = &parsedDate
+	} else if nik != nil {
+		dob = &nik.BirthDate
 	}
 
 	displayName := strings.TrimSpace(req.Name)
@@ -105,6 +113,9 @@ func (u *authUsecase) Register(ctx context.Context, req dto.AuthRegisterRequest)
 	if normalizedEmail != "" {
 		newUser.Email = &normalizedEmail
 	}
+	if nik != nil {
+		newUser.NIK = &nik.Number
+	}
 
 	if err := u.userRepo.Create(ctx, &newUser); err != nil {
 		utils.Error("Failed to create user", zap.Error(err))
@@ -255,6 +266,7 @@ func (u *authUsecase) GetProfile(ctx context.Context, userID string) (*dto.AuthU
 		Name:     user.Name,
 		Username: user.Username,
 		Email:    user.Email,
+		NIK:      user.NIK,
 		Role:     user.Role,
 	}, nil
 }
@@ -284,6 +296,13 @@ func (u *authUsecase) UpdateProfile(ctx context.Context, userID string, req dto.
 		}
 		user.DateOfBirth = &parsedDate
 	}
+	if req.NIK != "" {
+		nik, err := u.checkNIK(ctx, req.NIK, &user.ID)
+		if err != nil {
+			return nil, err
+		}
+		user.NIK = &nik.Number
+	}
 
 	if err := u.userRepo.Update(ctx, user); err != nil {
 		utils.Error("Failed to update user", zap.Error(err))
@@ -299,6 +318,7 @@ func (u *authUsecase) UpdateProfile(ctx context.Context, userID string, req dto.
 		Name:     user.Name,
 		Username: user.Username,
 		Email:    user.Email,
+		NIK:      user.NIK,
 	}
 	if user.DateOfBirth != nil {
 		formatted := user.DateOfBirth.Format("2006-01-02")
@@ -308,6 +328,30 @@ func (u *authUsecase) UpdateProfile(ctx context.Context, userID string, req dto.
 	return result, nil
 }
 
+// checkNIK memvalidasi NIK dan memastikan belum dipakai user lain (selain selfID).
+// NIK kosong tidak divalidasi dan menghasilkan nil.
+func (u *authUsecase) checkNIK(ctx context.Context, raw string, selfID *uuid.UUID) (*identity.NIK, error) {
+	if strings.TrimSpace(raw) == "" {
+		return nil, nil
+	}
+
+	nik, err := identity.ParseNIK(raw, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	existing, err := u.userRepo.FindByNIK(ctx, nik.Number)
+	if err != nil {
+		utils.Error("Failed to check NIK", zap.Error(err))
+		return nil, errors.New("gagal memeriksa NIK")
+	}
+	if existing != nil && (selfID == nil || existing.ID != *selfID) {
+		return nil, errors.New("NIK sudah terdaftar")
+	}
+
+	return nik, nil
+}
+
 // generateToken membuat JWT access token dari data user
 func (u *authUsecase) generateToken(user *entity.User) (string, error) {
 	email := ""
@@ -0,0 +1,162 @@
+package usecase
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"jantungin-api-server/internal/data/entity"
+	"jantungin-api-server/internal/dto"
+	"jantungin-api-server/pkg/background"
+	"jantungin-api-server/pkg/utils"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+const (
+	maxIdempotencyKeyLength = 255
+
+	// Reservasi yang belum selesai setelah selang ini dianggap ditinggalkan
+	// (mis. server mati di tengah request) dan boleh diambil alih oleh retry
+	idempotencyStaleAfter = 2 * time.Minute
+
+	idempotencyCleanupInterval = time.Hour
+)
+
+// CreateDiagnosisIdempotent sama seperti CreateDiagnosis, tetapi retry dengan Idempotency-Key
+// dan body yang sama mengembalikan hasil asli (replayed = true) tanpa memanggil ML service
+// atau membuat diagnosis baru. Request yang gagal tidak disimpan sehingga bisa diulang.
+func (u *diagnosisUsecase) CreateDiagnosisIdempotent(ctx context.Context, creatorID string, key string, req dto.CreateDiagnosisRequest) (*dto.DiagnosisResultData, bool, error) {
+	creatorUID, err := uuid.Parse(creatorID)
+	if err != nil {
+		return nil, false, errors.New("invalid creator ID")
+	}
+	if len(key) > maxIdempotencyKeyLength {
+		return nil, false, errors.New("Idempotency-Key maksimal 255 karakter")
+	}
+
+	body, err := json.Marshal(req)
+	if err != nil {
+		return nil, false, err
+	}
+	sum := sha256.Sum256(body)
+	requestHash := hex.EncodeToString(sum[:])
+
+	// Percobaan kedua hanya terjadi jika key lama kedaluwarsa/ditinggalkan dan sudah dihapus
+	for attempt := 0; attempt < 2; attempt++ {
+		now := time.Now()
+		record := &entity.IdempotencyKey{
+			UserID:      creatorUID,
+			Endpoint:    entity.IdempotencyEndpointCreateDiagnosis,
+			Key:         key,
+			RequestHash: requestHash,
+			ExpiresAt:   now.Add(u.idempotencyTTL),
+		}
+
+		reserved, err := u.idempotencyRepo.Reserve(ctx, record)
+		if err != nil {
+			utils.Error("Failed to reserve idempotency key", zap.Error(err))
+			return nil, false, errors.New("gagal memproses Idempotency-Key")
+		}
+		if reserved {
+			result, err := u.createReserved(ctx, creatorID, req, record)
+			return result, false, err
+		}
+
+		existing, err := u.idempotencyRepo.Find(ctx, creatorUID, entity.IdempotencyEndpointCreateDiagnosis, key)
+		if err != nil {
+			utils.Error("Failed to read idempotency key", zap.Error(err))
+			return nil, false, errors.New("gagal memproses Idempotency-Key")
+		}
+		if existing == nil {
+			continue
+		}
+
+		abandoned := existing.ResponseBody == nil && now.Sub(existing.CreatedAt) > idempotencyStaleAfter
+		if now.After(existing.ExpiresAt) || abandoned {
+			if err := u.idempotencyRepo.Delete(ctx, existing.ID); err != nil {
+				utils.Error("Failed to delete stale idempotency key", zap.Error(err))
+				return nil, false, errors.New("gagal memproses Idempotency-Key")
+			}
+			continue
+		}
+
+		if existing.RequestHash != requestHash {
+			return nil, false, errors.New("Idempotency-Key sudah dipakai untuk request yang berbeda")
+		}
+		if existing.ResponseBody == nil {
+			return nil, false, errors.New("request dengan Idempotency-Key yang sama masih diproses")
+		}
+
+		var result dto.DiagnosisResultData
+		if err := json.Unmarshal([]byte(*existing.ResponseBody), &result); err != nil {
+			utils.Error("Invalid stored idempotent response", zap.String("idempotency_key_id", existing.ID.String()), zap.Error(err))
+			return nil, false, errors.New("gagal memproses Idempotency-Key")
+		}
+
+		utils.Info("Idempotent diagnosis request replayed",
+			zap.String("diagnosis_id", result.ID),
+			zap.String("created_by", creatorID),
+		)
+		return &result, true, nil
+	}
+
+	return nil, false, errors.New("gagal memproses Idempotency-Key")
+}
+
+// createReserved membuat diagnosis untuk key yang sudah direservasi lalu menyimpan hasilnya.
+// Jika gagal, reservasi dihapus agar client bisa mengulang dengan key yang sama.
+func (u *diagnosisUsecase) createReserved(ctx context.Context, creatorID string, req dto.CreateDiagnosisRequest, record *entity.IdempotencyKey) (*dto.DiagnosisResultData, error) {
+	result, err := u.CreateDiagnosis(ctx, creatorID, req)
+	if err != nil {
+		if delErr := u.idempotencyRepo.Delete(context.WithoutCancel(ctx), record.ID); delErr != nil {
+			utils.Error("Failed to release idempotency key", zap.Error(delErr))
+		}
+		return nil, err
+	}
+
+	body, err := json.Marshal(result)
+	if err == nil {
+		err = u.idempotencyRepo.Complete(context.WithoutCancel(ctx), record.ID, string(body))
+	}
+	if err != nil {
+		// Diagnosis sudah tersimpan; retry berikutnya akan membuat duplikat setelah reservasi dianggap ditinggalkan
+		utils.Error("Failed to store idempotent response",
+			zap.String("diagnosis_id", result.ID),
+			zap.Error(err),
+		)
+	}
+
+	return result, nil
+}
+
+// StartIdempotencyCleanup menghapus Idempotency-Key yang melewati IDEMPOTENCY_TTL setiap jam
+func (u *diagnosisUsecase) StartIdempotencyCleanup(runner *background.Runner) {
+	err := runner.Go("idempotency-cleanup", func(ctx context.Context) {
+		ticker := time.NewTicker(idempotencyCleanupInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				deleted, err := u.idempotencyRepo.DeleteExpired(ctx, time.Now())
+				if err != nil {
+					utils.Error("Failed to delete expired idempotency keys", zap.Error(err))
+					continue
+				}
+				if deleted > 0 {
+					utils.Info("Expired idempotency keys deleted", zap.Int64("count", deleted))
+				}
+			}
+		}
+	})
+	if err != nil {
+		utils.Warn("Idempotency cleanup not started", zap.Error(err))
+	}
+}
@@ -0,0 +1,113 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"jantungin-api-server/internal/data/entity"
+	"jantungin-api-server/internal/data/repository"
+	"jantungin-api-server/internal/services"
+	"jantungin-api-server/pkg/utils"
+
+	"go.uber.org/zap"
+)
+
+const (
+	// Batas prediksi shadow yang berjalan bersamaan; jika penuh, prediksi shadow dilewati
+	// agar model kandidat tidak pernah memperlambat pembuatan diagnosis
+	maxInflightShadowPredictions = 16
+
+	shadowPredictionTimeout = 15 * time.Second
+)
+
+// ShadowReport membandingkan model utama dengan model shadow
+type ShadowReport struct {
+	Enabled             bool    `json:"enabled"`
+	ModelVersion        string  `json:"modelVersion"` // versi shadow yang dilaporkan, kosong = semua versi
+	PrimaryModelVersion string  `json:"primaryModelVersion"`
+	AgreementRate       float64 `json:"agreementRate"` // 0-1, proporsi kategori risiko yang sama
+	*repository.ShadowSummary
+}
+
+// runShadowPrediction memanggil model shadow di background untuk diagnosis yang baru
+// dibuat. Kegagalan hanya dicatat di log; pasien tidak pernah melihat hasil shadow.
+func (u *diagnosisUsecase) runShadowPrediction(diagnosis entity.Diagnosis, mlReq services.MLPredictRequest) {
+	if u.shadowPredictor == nil || u.runner == nil {
+		return
+	}
+
+	select {
+	case u.shadowSlots <- struct{}{}:
+	default:
+		utils.Warn("Shadow prediction skipped, too many in flight",
+			zap.String("diagnosis_id", diagnosis.ID.String()),
+		)
+		return
+	}
+
+	err := u.runner.Go("shadow-prediction:"+diagnosis.ID.String(), func(ctx context.Context) {
+		defer func() { <-u.shadowSlots }()
+
+		ctx, cancel := context.WithTimeout(ctx, shadowPredictionTimeout)
+		defer cancel()
+
+		started := time.Now()
+		result, err := u.shadowPredictor.Predict(ctx, mlReq)
+		if err != nil {
+			utils.Warn("Shadow prediction failed",
+				zap.String("diagnosis_id", diagnosis.ID.String()),
+				zap.String("shadow_model_version", u.shadowVersion),
+				zap.Error(err),
+			)
+			return
+		}
+
+		shadow := &entity.ShadowPrediction{
+			DiagnosisID:               diagnosis.ID,
+			ModelVersion:              u.shadowVersion,
+			ResultPercentage:          float64(result.ResultPercentage),
+			CardiovascularRisk:        result.CardiovascularRisk,
+			Prediction:                result.Prediction,
+			PrimaryModelVersion:       diagnosis.ModelVersion,
+			PrimaryResultPercentage:   diagnosis.ResultPercentage,
+			PrimaryCardiovascularRisk: diagnosis.CardiovascularRisk,
+			LatencyMs:                 time.Since(started).Milliseconds(),
+		}
+		if err := u.shadowRepo.Create(context.WithoutCancel(ctx), shadow); err != nil {
+			utils.Error("Failed to save shadow prediction",
+				zap.String("diagnosis_id", diagnosis.ID.String()),
+				zap.Error(err),
+			)
+		}
+	})
+	if err != nil {
+		<-u.shadowSlots
+		utils.Warn("Shadow prediction skipped", zap.String("diagnosis_id", diagnosis.ID.String()), zap.Error(err))
+	}
+}
+
+// GetShadowReport menghitung tingkat kesepakatan, rata-rata selisih absolut, dan
+// confusion matrix kategori risiko. modelVersion kosong memakai versi shadow yang aktif.
+func (u *diagnosisUsecase) GetShadowReport(ctx context.Context, modelVersion string) (*ShadowReport, error) {
+	if modelVersion == "" {
+		modelVersion = u.shadowVersion
+	}
+
+	summary, err := u.shadowRepo.Summarize(ctx, modelVersion)
+	if err != nil {
+		utils.Error("Failed to summarize shadow predictions", zap.Error(err))
+		return nil, err
+	}
+
+	report := &ShadowReport{
+		Enabled:             u.shadowPredictor != nil,
+		ModelVersion:        modelVersion,
+		PrimaryModelVersion: u.modelVersion,
+		ShadowSummary:       summary,
+	}
+	if summary.Compared > 0 {
+		report.AgreementRate = float64(summary.Agreements) / float64(summary.Compared)
+	}
+
+	return report, nil
+}
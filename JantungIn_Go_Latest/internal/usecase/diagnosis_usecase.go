@@ -1,13 +1,21 @@
 package usecase
 
 import (
+	"bytes"
 	"context"
 	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
 
+	"jantungin-api-server/internal/clinical"
 	"jantungin-api-server/internal/data/entity"
 	"jantungin-api-server/internal/data/repository"
 	"jantungin-api-server/internal/dto"
+	"jantungin-api-server/internal/report"
 	"jantungin-api-server/internal/services"
+	"jantungin-api-server/pkg/background"
 	"jantungin-api-server/pkg/utils"
 
 	"github.com/google/uuid"
@@ -16,27 +24,77 @@ import (
 
 type DiagnosisUsecase interface {
 	CreateDiagnosis(ctx context.Context, creatorID string, req dto.CreateDiagnosisRequest) (*dto.DiagnosisResultData, error)
+	CreateDiagnosisIdempotent(ctx context.Context, creatorID string, key string, req dto.CreateDiagnosisRequest) (*dto.DiagnosisResultData, bool, error)
 	GetDiagnosisHistory(ctx context.Context, userID string, role string, patientID string) ([]entity.Diagnosis, error)
 	GetDiagnosisByID(ctx context.Context, userID string, role string, diagnosisID string) (*entity.Diagnosis, error)
 	GetAllDiagnoses(ctx context.Context) ([]entity.Diagnosis, error)
 	GetPatientDiagnoses(ctx context.Context, patientID string) ([]entity.Diagnosis, error)
+	SimulateDiagnosis(ctx context.Context, userID string, role string, req dto.SimulateDiagnosisRequest) (*dto.SimulationResultData, error)
+	UpdateDiagnosis(ctx context.Context, userID string, role string, diagnosisID string, req dto.UpdateDiagnosisRequest) (*dto.DiagnosisResultData, error)
+	DeleteDiagnosis(ctx context.Context, userID string, role string, diagnosisID string, reason string) error
+	GetDiagnosisRevisions(ctx context.Context, userID string, role string, diagnosisID string) ([]entity.DiagnosisRevision, error)
+	SubmitForReview(ctx context.Context, userID string, role string, diagnosisID string) (*entity.Diagnosis, error)
+	ReviewDiagnosis(ctx context.Context, reviewerID string, role string, diagnosisID string, req dto.ReviewDiagnosisRequest) (*entity.Diagnosis, error)
+	GetPendingReview(ctx context.Context, reviewerID string) ([]entity.Diagnosis, error)
+	GenerateDiagnosisReport(ctx context.Context, userID string, role string, diagnosisID string, lang report.Language) ([]byte, error)
+	GetShadowReport(ctx context.Context, modelVersion string) (*ShadowReport, error)
+	StartIdempotencyCleanup(runner *background.Runner)
 }
 
 type diagnosisUsecase struct {
 	diagnosisRepo repository.DiagnosisRepository
 	userRepo      repository.UserRepository
-	mlClient      *services.MLClient
+	predictor     services.Predictor
+	modelVersion  string
+	location      *time.Location
+
+	// Shadow mode: nil jika ML_SHADOW_SERVICE_URL kosong
+	shadowRepo      repository.ShadowRepository
+	shadowPredictor services.Predictor
+	shadowVersion   string
+	shadowSlots     chan struct{}
+	runner          *background.Runner
+
+	alerts AlertUsecase
+
+	idempotencyRepo repository.IdempotencyRepository
+	idempotencyTTL  time.Duration
 }
 
 func NewDiagnosisUsecase(
 	diagnosisRepo repository.DiagnosisRepository,
 	userRepo repository.UserRepository,
-	mlClient *services.MLClient,
+	shadowRepo repository.ShadowRepository,
+	predictor services.Predictor,
+	shadowPredictor services.Predictor,
+	alerts AlertUsecase,
+	idempotencyRepo repository.IdempotencyRepository,
+	runner *background.Runner,
+	cfg *utils.Config,
 ) DiagnosisUsecase {
+	location, err := time.LoadLocation(cfg.App.Timezone)
+	if err != nil {
+		utils.Warn("Invalid APP_TIMEZONE, falling back to UTC", zap.String("timezone", cfg.App.Timezone))
+		location = time.UTC
+	}
+
 	return &diagnosisUsecase{
 		diagnosisRepo: diagnosisRepo,
 		userRepo:      userRepo,
-		mlClient:      mlClient,
+		predictor:     predictor,
+		modelVersion:  cfg.App.MLModelVersion,
+		location:      location,
+
+		shadowRepo:      shadowRepo,
+		shadowPredictor: shadowPredictor,
+		shadowVersion:   cfg.App.MLShadowVersion,
+		shadowSlots:     make(chan struct{}, maxInflightShadowPredictions),
+		runner:          runner,
+
+		alerts: alerts,
+
+		idempotencyRepo: idempotencyRepo,
+		idempotencyTTL:  cfg.App.IdempotencyTTL,
 	}
 }
 
@@ -46,6 +104,11 @@ func (u *diagnosisUsecase) CreateDiagnosis(ctx context.Context, creatorID string
 		return nil, errors.New("invalid creator ID")
 	}
 
+	warnings, inputErr := normalizeInput(&req)
+	if inputErr != nil {
+		return nil, inputErr
+	}
+
 	userUID := creatorUID
 	if req.PatientID != "" {
 		parsed, err := uuid.Parse(req.PatientID)
@@ -66,23 +129,9 @@ func (u *diagnosisUsecase) CreateDiagnosis(ctx context.Context, creatorID string
 	}
 
 	// Panggil ML service
-	mlReq := services.MLPredictRequest{
-		Age:                   req.Age,
-		Sex:                   req.Sex,
-		ChestPainType:         req.ChestPainType,
-		RestingBloodPressure:  req.RestingBloodPressure,
-		SerumCholesterol:      req.SerumCholesterol,
-		FastingBloodSugar:     req.FastingBloodSugar,
-		RestingEcgResults:     req.RestingEcgResults,
-		MaximumHeartRate:      req.MaximumHeartRate,
-		ExerciseInducedAngina: req.ExerciseInducedAngina,
-		StDepression:          req.StDepression,
-		StSegment:             req.StSegment,
-		MajorVessels:          req.MajorVessels,
-		Thalassemia:           req.Thalassemia,
-	}
+	mlReq := toMLRequest(req)
 
-	mlResult, err := u.mlClient.Predict(ctx, mlReq)
+	mlResult, err := u.predictor.Predict(ctx, mlReq)
 	if err != nil {
 		utils.Error("ML service prediction failed", zap.Error(err))
 		return nil, errors.New("gagal melakukan prediksi")
@@ -90,25 +139,12 @@ func (u *diagnosisUsecase) CreateDiagnosis(ctx context.Context, creatorID string
 
 	// Simpan ke database
 	diagnosis := &entity.Diagnosis{
-		UserID:                userUID,
-		CreatedBy:             &creatorUID,
-		Age:                   req.Age,
-		Sex:                   req.Sex,
-		ChestPainType:         req.ChestPainType,
-		RestingEcgResults:     req.RestingEcgResults,
-		FastingBloodSugar:     req.FastingBloodSugar,
-		RestingBloodPressure:  req.RestingBloodPressure,
-		MaximumHeartRate:      req.MaximumHeartRate,
-		ExerciseInducedAngina: req.ExerciseInducedAngina,
-		StSegment:             req.StSegment,
-		MajorVessels:          req.MajorVessels,
-		Thalassemia:           req.Thalassemia,
-		SerumCholesterol:      req.SerumCholesterol,
-		StDepression:          req.StDepression,
-		ResultPercentage:      float64(mlResult.ResultPercentage),
-		CardiovascularRisk:    mlResult.CardiovascularRisk,
-		Prediction:            mlResult.Prediction,
+		UserID:    userUID,
+		CreatedBy: &creatorUID,
+		Status:    entity.DiagnosisStatusDraft,
 	}
+	applyDiagnosisInput(diagnosis, req, mlResult)
+	diagnosis.ModelVersion = u.modelVersion
 
 	if err := u.diagnosisRepo.Create(ctx, diagnosis); err != nil {
 		utils.Error("Failed to save diagnosis", zap.Error(err))
@@ -122,12 +158,19 @@ func (u *diagnosisUsecase) CreateDiagnosis(ctx context.Context, creatorID string
 		zap.String("prediction", mlResult.Prediction),
 	)
 
+	u.runShadowPrediction(*diagnosis, mlReq)
+	if !alertsSkipped(ctx) {
+		u.alerts.NotifyDiagnosis(*diagnosis)
+	}
+
 	return &dto.DiagnosisResultData{
 		ID:                 diagnosis.ID.String(),
 		UserID:             userUID.String(),
 		ResultPercentage:   float64(mlResult.ResultPercentage),
 		CardiovascularRisk: mlResult.CardiovascularRisk,
 		Prediction:         mlResult.Prediction,
+		Status:             diagnosis.Status,
+		Warnings:           warnings,
 		CreatedAt:          diagnosis.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
 	}, nil
 }
@@ -191,3 +234,465 @@ func (u *diagnosisUsecase) GetPatientDiagnoses(ctx context.Context, patientID st
 
 	return u.diagnosisRepo.FindByPatientID(ctx, uid)
 }
+
+// SimulateDiagnosis menjalankan prediksi untuk beberapa skenario "what-if" berdasarkan
+// input diagnosis yang sudah ada. Hasilnya tidak disimpan ke tabel diagnoses.
+func (u *diagnosisUsecase) SimulateDiagnosis(ctx context.Context, userID string, role string, req dto.SimulateDiagnosisRequest) (*dto.SimulationResultData, error) {
+	base, err := u.GetDiagnosisByID(ctx, userID, role, req.BaseDiagnosisID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Nilai tersimpan (termasuk data lama di luar rentang kewajaran) dipakai apa adanya;
+	// hanya vokabulari yang diseragamkan agar kode untuk model benar
+	baseInput := dto.ToCreateDiagnosisRequest(*base)
+	if err := baseInput.Normalize(); err != nil {
+		return nil, &InputError{Message: "diagnosis dasar memiliki nilai yang tidak valid", Details: err}
+	}
+
+	// Index 0 adalah baseline, sisanya skenario sesuai urutan request
+	mlReqs := make([]services.MLPredictRequest, 0, len(req.Scenarios)+1)
+	mlReqs = append(mlReqs, toMLRequest(baseInput))
+	scenarioWarnings := make([][]clinical.Finding, len(req.Scenarios))
+
+	for i, scenario := range req.Scenarios {
+		input := scenario.ApplyTo(baseInput)
+		warnings, inputErr := normalizeFields(&input, scenario.Fields())
+		if inputErr != nil {
+			return nil, &InputError{
+				Message: fmt.Sprintf("skenario ke-%d tidak valid", i+1),
+				Details: inputErr.Details,
+			}
+		}
+		scenarioWarnings[i] = warnings
+		mlReqs = append(mlReqs, toMLRequest(input))
+	}
+
+	results := u.predictor.PredictBatch(ctx, mlReqs)
+	if results[0].Err != nil {
+		utils.Error("ML service baseline simulation failed", zap.Error(results[0].Err))
+		return nil, errors.New("gagal melakukan prediksi")
+	}
+
+	baseline := toSimulationOutcome(results[0].Result)
+	data := &dto.SimulationResultData{
+		BaseDiagnosisID: base.ID.String(),
+		UserID:          base.UserID.String(),
+		Baseline:        baseline,
+		Scenarios:       make([]dto.SimulationScenarioResult, len(req.Scenarios)),
+	}
+
+	for i, scenario := range req.Scenarios {
+		name := scenario.Name
+		if name == "" {
+			name = fmt.Sprintf("Skenario %d", i+1)
+		}
+
+		item := dto.SimulationScenarioResult{Name: name, Warnings: scenarioWarnings[i]}
+		if results[i+1].Err != nil {
+			utils.Warn("ML service scenario simulation failed",
+				zap.String("diagnosis_id", base.ID.String()),
+				zap.Int("scenario", i+1),
+				zap.Error(results[i+1].Err),
+			)
+			item.Error = "gagal melakukan prediksi"
+		} else {
+			item.SimulationOutcome = toSimulationOutcome(results[i+1].Result)
+			item.DeltaPercentage = item.ResultPercentage - baseline.ResultPercentage
+			item.CategoryChanged = item.CardiovascularRisk != baseline.CardiovascularRisk
+		}
+		data.Scenarios[i] = item
+	}
+
+	return data, nil
+}
+
+// UpdateDiagnosis mengoreksi input diagnosis dan menjalankan ulang prediksi.
+// Versi sebelumnya disimpan di diagnosis_revisions beserta editor dan alasannya.
+func (u *diagnosisUsecase) UpdateDiagnosis(ctx context.Context, userID string, role string, diagnosisID string, req dto.UpdateDiagnosisRequest) (*dto.DiagnosisResultData, error) {
+	diagnosis, editorUID, err := u.findModifiableDiagnosis(ctx, userID, role, diagnosisID)
+	if err != nil {
+		return nil, err
+	}
+
+	if diagnosis.IsSigned() {
+		return nil, errors.New("diagnosis sudah ditandatangani dan tidak dapat diubah")
+	}
+	if req.Version != 0 && req.Version != diagnosis.Version {
+		return nil, errors.New("diagnosis telah diubah oleh pengguna lain")
+	}
+
+	warnings, inputErr := normalizeInput(&req.CreateDiagnosisRequest)
+	if inputErr != nil {
+		return nil, inputErr
+	}
+
+	// Amend tanpa clinicalNotes tidak boleh menghapus catatan klinis yang sudah ada
+	req.CreateDiagnosisRequest.ClinicalNotes = diagnosis.ClinicalNotes
+	if req.ClinicalNotes != nil {
+		req.CreateDiagnosisRequest.ClinicalNotes = *req.ClinicalNotes
+	}
+
+	mlResult, err := u.predictor.Predict(ctx, toMLRequest(req.CreateDiagnosisRequest))
+	if err != nil {
+		utils.Error("ML service prediction failed", zap.Error(err))
+		return nil, errors.New("gagal melakukan prediksi")
+	}
+
+	revision := entity.NewDiagnosisRevision(*diagnosis, entity.RevisionActionUpdate, editorUID, req.Reason)
+	applyDiagnosisInput(diagnosis, req.CreateDiagnosisRequest, mlResult)
+	diagnosis.ModelVersion = u.modelVersion
+
+	// Hasil review lama tidak berlaku lagi untuk nilai yang sudah dikoreksi
+	if diagnosis.Status == entity.DiagnosisStatusReviewed || diagnosis.Status == entity.DiagnosisStatusPendingReview {
+		diagnosis.Status = entity.DiagnosisStatusPendingReview
+		diagnosis.ReviewedBy = nil
+		diagnosis.ReviewedAt = nil
+		diagnosis.ReviewNotes = ""
+	}
+
+	if err := u.diagnosisRepo.Amend(ctx, diagnosis, &revision); err != nil {
+		if errors.Is(err, repository.ErrDiagnosisVersionConflict) {
+			return nil, errors.New("diagnosis telah diubah oleh pengguna lain")
+		}
+		utils.Error("Failed to amend diagnosis", zap.Error(err))
+		return nil, errors.New("gagal menyimpan diagnosis")
+	}
+
+	utils.Info("Diagnosis amended",
+		zap.String("diagnosis_id", diagnosis.ID.String()),
+		zap.String("edited_by", userID),
+		zap.Int("version", diagnosis.Version),
+		zap.String("prediction", mlResult.Prediction),
+	)
+
+	return &dto.DiagnosisResultData{
+		ID:                 diagnosis.ID.String(),
+		UserID:             diagnosis.UserID.String(),
+		ResultPercentage:   diagnosis.ResultPercentage,
+		CardiovascularRisk: diagnosis.CardiovascularRisk,
+		Prediction:         diagnosis.Prediction,
+		Status:             diagnosis.Status,
+		Warnings:           warnings,
+		CreatedAt:          diagnosis.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
+	}, nil
+}
+
+// DeleteDiagnosis melakukan soft delete; snapshot terakhir tetap ada di diagnosis_revisions
+func (u *diagnosisUsecase) DeleteDiagnosis(ctx context.Context, userID string, role string, diagnosisID string, reason string) error {
+	diagnosis, editorUID, err := u.findModifiableDiagnosis(ctx, userID, role, diagnosisID)
+	if err != nil {
+		return err
+	}
+
+	if diagnosis.IsSigned() {
+		return errors.New("diagnosis sudah ditandatangani dan tidak dapat diubah")
+	}
+
+	revision := entity.NewDiagnosisRevision(*diagnosis, entity.RevisionActionDelete, editorUID, reason)
+	if err := u.diagnosisRepo.SoftDelete(ctx, diagnosis, &revision); err != nil {
+		if errors.Is(err, repository.ErrDiagnosisVersionConflict) {
+			return errors.New("diagnosis telah diubah oleh pengguna lain")
+		}
+		utils.Error("Failed to delete diagnosis", zap.Error(err))
+		return errors.New("gagal menghapus diagnosis")
+	}
+
+	utils.Info("Diagnosis deleted",
+		zap.String("diagnosis_id", diagnosis.ID.String()),
+		zap.String("deleted_by", userID),
+	)
+
+	return nil
+}
+
+// GetDiagnosisRevisions mengembalikan riwayat versi diagnosis. Aksesnya sama dengan
+// GetDiagnosisByID, kecuali admin yang tetap bisa melihat riwayat diagnosis yang sudah dihapus.
+func (u *diagnosisUsecase) GetDiagnosisRevisions(ctx context.Context, userID string, role string, diagnosisID string) ([]entity.DiagnosisRevision, error) {
+	var did uuid.UUID
+	if role == "admin" {
+		parsed, err := uuid.Parse(diagnosisID)
+		if err != nil {
+			return nil, errors.New("invalid diagnosis ID")
+		}
+
+		diagnosis, err := u.diagnosisRepo.FindByIDWithDeleted(ctx, parsed)
+		if err != nil {
+			return nil, err
+		}
+		if diagnosis == nil {
+			return nil, errors.New("diagnosis not found")
+		}
+		did = diagnosis.ID
+	} else {
+		diagnosis, err := u.GetDiagnosisByID(ctx, userID, role, diagnosisID)
+		if err != nil {
+			return nil, err
+		}
+		did = diagnosis.ID
+	}
+
+	return u.diagnosisRepo.FindRevisions(ctx, did)
+}
+
+// SubmitForReview memindahkan diagnosis draft ke antrean review
+func (u *diagnosisUsecase) SubmitForReview(ctx context.Context, userID string, role string, diagnosisID string) (*entity.Diagnosis, error) {
+	diagnosis, _, err := u.findModifiableDiagnosis(ctx, userID, role, diagnosisID)
+	if err != nil {
+		return nil, err
+	}
+
+	if diagnosis.Status != entity.DiagnosisStatusDraft {
+		return nil, errors.New("status diagnosis tidak valid untuk aksi ini")
+	}
+
+	diagnosis.Status = entity.DiagnosisStatusPendingReview
+	if err := u.diagnosisRepo.UpdateReviewStatus(ctx, diagnosis, entity.DiagnosisStatusDraft, diagnosis.Version); err != nil {
+		if errors.Is(err, repository.ErrDiagnosisVersionConflict) {
+			return nil, errors.New("diagnosis telah diubah oleh pengguna lain")
+		}
+		utils.Error("Failed to submit diagnosis for review", zap.Error(err))
+		return nil, errors.New("gagal menyimpan diagnosis")
+	}
+
+	utils.Info("Diagnosis submitted for review",
+		zap.String("diagnosis_id", diagnosis.ID.String()),
+		zap.String("submitted_by", userID),
+	)
+
+	return diagnosis, nil
+}
+
+// ReviewDiagnosis mencatat hasil review dokter. Transisi yang diizinkan:
+// pending_review -> reviewed/signed dan reviewed -> signed.
+// Reviewer tidak boleh dokter yang membuat diagnosis tersebut.
+func (u *diagnosisUsecase) ReviewDiagnosis(ctx context.Context, reviewerID string, role string, diagnosisID string, req dto.ReviewDiagnosisRequest) (*entity.Diagnosis, error) {
+	reviewerUID, err := uuid.Parse(reviewerID)
+	if err != nil {
+		return nil, errors.New("invalid user ID")
+	}
+
+	diagnosis, err := u.GetDiagnosisByID(ctx, reviewerID, role, diagnosisID)
+	if err != nil {
+		return nil, err
+	}
+
+	if diagnosis.CreatedBy != nil && *diagnosis.CreatedBy == reviewerUID {
+		return nil, errors.New("reviewer tidak boleh pembuat diagnosis")
+	}
+
+	// Reviewer wajib menyebut versi yang dibacanya; amend setelahnya membatalkan review
+	if req.Version != diagnosis.Version {
+		return nil, errors.New("diagnosis telah diubah oleh pengguna lain")
+	}
+
+	fromStatus := diagnosis.Status
+	allowed := fromStatus == entity.DiagnosisStatusPendingReview ||
+		(fromStatus == entity.DiagnosisStatusReviewed && req.Decision == entity.DiagnosisStatusSigned)
+	if !allowed {
+		return nil, errors.New("status diagnosis tidak valid untuk aksi ini")
+	}
+
+	now := time.Now()
+	diagnosis.Status = req.Decision
+	diagnosis.ReviewedBy = &reviewerUID
+	diagnosis.ReviewedAt = &now
+	// Saat sign dari status reviewed tanpa catatan baru, catatan review sebelumnya dipertahankan
+	if req.Notes != "" || fromStatus == entity.DiagnosisStatusPendingReview {
+		diagnosis.ReviewNotes = req.Notes
+	}
+
+	if err := u.diagnosisRepo.UpdateReviewStatus(ctx, diagnosis, fromStatus, req.Version); err != nil {
+		if errors.Is(err, repository.ErrDiagnosisVersionConflict) {
+			return nil, errors.New("diagnosis telah diubah oleh pengguna lain")
+		}
+		utils.Error("Failed to review diagnosis", zap.Error(err))
+		return nil, errors.New("gagal menyimpan diagnosis")
+	}
+
+	utils.Info("Diagnosis reviewed",
+		zap.String("diagnosis_id", diagnosis.ID.String()),
+		zap.String("reviewed_by", reviewerID),
+		zap.String("status", diagnosis.Status),
+	)
+
+	// Ambil ulang agar relasi reviewer ikut ter-load
+	return u.diagnosisRepo.FindByID(ctx, diagnosis.ID)
+}
+
+// GetPendingReview mengembalikan antrean review, tanpa diagnosis buatan reviewer sendiri
+func (u *diagnosisUsecase) GetPendingReview(ctx context.Context, reviewerID string) ([]entity.Diagnosis, error) {
+	reviewerUID, err := uuid.Parse(reviewerID)
+	if err != nil {
+		return nil, errors.New("invalid user ID")
+	}
+
+	return u.diagnosisRepo.FindPendingReview(ctx, reviewerUID)
+}
+
+// GenerateDiagnosisReport merender laporan PDF diagnosis. Aksesnya sama dengan GetDiagnosisByID.
+func (u *diagnosisUsecase) GenerateDiagnosisReport(ctx context.Context, userID string, role string, diagnosisID string, lang report.Language) ([]byte, error) {
+	diagnosis, err := u.GetDiagnosisByID(ctx, userID, role, diagnosisID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Preload Patient hanya berisi id, name, role; ambil data lengkap untuk tanggal lahir
+	patient, err := u.userRepo.FindByID(ctx, diagnosis.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if patient == nil {
+		return nil, errors.New("pasien tidak ditemukan")
+	}
+
+	var buf bytes.Buffer
+	err = report.RenderDiagnosis(&buf, report.DiagnosisReport{
+		Diagnosis:   *diagnosis,
+		Patient:     *patient,
+		GeneratedAt: time.Now(),
+		Location:    u.location,
+	}, lang)
+	if err != nil {
+		utils.Error("Failed to render diagnosis report", zap.Error(err))
+		return nil, errors.New("gagal membuat laporan")
+	}
+
+	return buf.Bytes(), nil
+}
+
+// findModifiableDiagnosis memuat diagnosis dan memastikan user adalah pembuatnya atau admin
+func (u *diagnosisUsecase) findModifiableDiagnosis(ctx context.Context, userID string, role string, diagnosisID string) (*entity.Diagnosis, uuid.UUID, error) {
+	editorUID, err := uuid.Parse(userID)
+	if err != nil {
+		return nil, uuid.Nil, errors.New("invalid user ID")
+	}
+
+	diagnosis, err := u.GetDiagnosisByID(ctx, userID, role, diagnosisID)
+	if err != nil {
+		return nil, uuid.Nil, err
+	}
+
+	isCreator := diagnosis.CreatedBy != nil && *diagnosis.CreatedBy == editorUID
+	if role != "admin" && !isCreator {
+		return nil, uuid.Nil, errors.New("hanya pembuat diagnosis atau admin yang dapat mengubah diagnosis ini")
+	}
+
+	return diagnosis, editorUID, nil
+}
+
+// normalizeInput menjalankan validasi CreateDiagnosisRequest, menyeragamkan nilai
+// kategorikal ke vokabulari kanonik, lalu mengonversi satuan dan memeriksa kewajaran
+// nilai klinis sebelum dikirim ke ML service. Peringatan (soft) dikembalikan,
+// pelanggaran batas keras menjadi InputError.
+func normalizeInput(req *dto.CreateDiagnosisRequest) ([]clinical.Finding, *InputError) {
+	if err := req.Validate(); err != nil {
+		return nil, &InputError{Message: "Format data tidak valid", Details: err.Error()}
+	}
+	return assessInput(req, nil)
+}
+
+// normalizeFields seperti normalizeInput, tetapi validasi dan pemeriksaan kewajaran
+// hanya untuk fields (nama field struct); dipakai skenario simulasi agar nilai
+// diagnosis dasar yang tidak diubah tidak ikut ditolak
+func normalizeFields(req *dto.CreateDiagnosisRequest, fields []string) ([]clinical.Finding, *InputError) {
+	if err := req.ValidateFields(fields...); err != nil {
+		return nil, &InputError{Message: "Format data tidak valid", Details: err.Error()}
+	}
+	// Finding.Field adalah nama JSON (camelCase) dari field struct yang sama
+	checked := make(map[string]bool, len(fields))
+	for _, name := range fields {
+		checked[strings.ToLower(name)] = true
+	}
+	return assessInput(req, checked)
+}
+
+// assessInput menyeragamkan vokabulari, mengonversi satuan, dan memeriksa kewajaran.
+// Jika checked tidak nil, hanya finding untuk field di checked (lowercase) yang dipakai.
+func assessInput(req *dto.CreateDiagnosisRequest, checked map[string]bool) ([]clinical.Finding, *InputError) {
+	if err := req.Normalize(); err != nil {
+		return nil, &InputError{Message: "Nilai klinis tidak dikenali", Details: err}
+	}
+
+	measurements, warnings, err := clinical.Assess(clinical.Measurements{
+		Age:                  float64(req.Age),
+		RestingBloodPressure: req.RestingBloodPressure,
+		SerumCholesterol:     req.SerumCholesterol,
+		CholesterolUnit:      req.CholesterolUnit,
+		FastingBloodSugar:    req.FastingBloodSugar,
+		GlucoseUnit:          req.GlucoseUnit,
+		MaximumHeartRate:     float64(req.MaximumHeartRate),
+		StDepression:         req.StDepression,
+	})
+	if checked != nil {
+		unchecked := func(f clinical.Finding) bool { return !checked[strings.ToLower(f.Field)] }
+		warnings = slices.DeleteFunc(warnings, unchecked)
+		var plausibilityErr *clinical.PlausibilityError
+		if errors.As(err, &plausibilityErr) {
+			plausibilityErr.Findings = slices.DeleteFunc(plausibilityErr.Findings, unchecked)
+			if len(plausibilityErr.Findings) == 0 {
+				err = nil
+			}
+		}
+	}
+	if err != nil {
+		var plausibilityErr *clinical.PlausibilityError
+		if errors.As(err, &plausibilityErr) {
+			return nil, &InputError{Message: "Nilai klinis di luar rentang yang wajar", Details: plausibilityErr.Findings}
+		}
+		return nil, &InputError{Message: "Nilai klinis di luar rentang yang wajar", Details: err.Error()}
+	}
+
+	// Model dan database memakai mg/dL
+	req.SerumCholesterol, req.CholesterolUnit = measurements.SerumCholesterol, measurements.CholesterolUnit
+	req.FastingBloodSugar, req.GlucoseUnit = measurements.FastingBloodSugar, measurements.GlucoseUnit
+
+	return warnings, nil
+}
+
+// applyDiagnosisInput menyalin input klinis dan hasil prediksi ke entity diagnosis
+func applyDiagnosisInput(d *entity.Diagnosis, req dto.CreateDiagnosisRequest, result *services.MLPredictResult) {
+	d.Age = req.Age
+	d.Sex = req.Sex
+	d.ChestPainType = req.ChestPainType
+	d.RestingEcgResults = req.RestingEcgResults
+	d.FastingBloodSugar = req.FastingBloodSugar
+	d.RestingBloodPressure = req.RestingBloodPressure
+	d.MaximumHeartRate = req.MaximumHeartRate
+	d.ExerciseInducedAngina = req.ExerciseInducedAngina
+	d.StSegment = req.StSegment
+	d.MajorVessels = req.MajorVessels
+	d.Thalassemia = req.Thalassemia
+	d.SerumCholesterol = req.SerumCholesterol
+	d.StDepression = req.StDepression
+	d.ResultPercentage = float64(result.ResultPercentage)
+	d.CardiovascularRisk = result.CardiovascularRisk
+	d.Prediction = result.Prediction
+	d.ClinicalNotes = req.ClinicalNotes
+}
+
+func toMLRequest(req dto.CreateDiagnosisRequest) services.MLPredictRequest {
+	return services.MLPredictRequest{
+		Age:                   req.Age,
+		Sex:                   req.Sex,
+		ChestPainType:         req.ChestPainType,
+		RestingBloodPressure:  req.RestingBloodPressure,
+		SerumCholesterol:      req.SerumCholesterol,
+		FastingBloodSugar:     req.FastingBloodSugar,
+		RestingEcgResults:     req.RestingEcgResults,
+		MaximumHeartRate:      req.MaximumHeartRate,
+		ExerciseInducedAngina: req.ExerciseInducedAngina,
+		StDepression:          req.StDepression,
+		StSegment:             req.StSegment,
+		MajorVessels:          req.MajorVessels,
+		Thalassemia:           req.Thalassemia,
+	}
+}
+
+func toSimulationOutcome(r *services.MLPredictResult) dto.SimulationOutcome {
+	return dto.SimulationOutcome{
+		ResultPercentage:   float64(r.ResultPercentage),
+		CardiovascularRisk: r.CardiovascularRisk,
+		Prediction:         r.Prediction,
+	}
+}
@@ -0,0 +1,148 @@
+package usecase
+
+import (
+	"testing"
+
+	"jantungin-api-server/internal/dto"
+)
+
+func validDiagnosisRequest() dto.CreateDiagnosisRequest {
+	return dto.CreateDiagnosisRequest{
+		Age:                   54,
+		Sex:                   "Laki-laki",
+		ChestPainType:         "typical",
+		RestingBloodPressure:  130,
+		SerumCholesterol:      240,
+		FastingBloodSugar:     100,
+		RestingEcgResults:     "Normal",
+		MaximumHeartRate:      150,
+		ExerciseInducedAngina: "Tidak",
+		StDepression:          1,
+		StSegment:             "Flat",
+		MajorVessels:          0,
+		Thalassemia:           "Normal",
+	}
+}
+
+func TestNormalizeInput(t *testing.T) {
+	tests := []struct {
+		name         string
+		modify       func(r *dto.CreateDiagnosisRequest)
+		wantErr      bool
+		wantWarnings int
+		wantChol     float64
+		wantGlucose  float64
+	}{
+		{name: "mg/dL", modify: func(r *dto.CreateDiagnosisRequest) {}, wantChol: 240, wantGlucose: 100},
+		{
+			name: "kolesterol mmol/L",
+			modify: func(r *dto.CreateDiagnosisRequest) {
+				r.SerumCholesterol, r.CholesterolUnit = 6.2, "mmol/L"
+			},
+			wantChol: 239.8, wantGlucose: 100,
+		},
+		{
+			name: "glukosa mmol/L",
+			modify: func(r *dto.CreateDiagnosisRequest) {
+				r.FastingBloodSugar, r.GlucoseUnit = 7, "mmol"
+			},
+			wantChol: 240, wantGlucose: 126.1,
+		},
+		{
+			name:         "tekanan darah di atas batas lunak",
+			modify:       func(r *dto.CreateDiagnosisRequest) { r.RestingBloodPressure = 201 },
+			wantWarnings: 1, wantChol: 240, wantGlucose: 100,
+		},
+		{
+			name:         "tekanan darah tepat batas keras",
+			modify:       func(r *dto.CreateDiagnosisRequest) { r.RestingBloodPressure = 300 },
+			wantWarnings: 1, wantChol: 240, wantGlucose: 100,
+		},
+		{
+			name:    "tekanan darah di atas batas keras",
+			modify:  func(r *dto.CreateDiagnosisRequest) { r.RestingBloodPressure = 301 },
+			wantErr: true,
+		},
+		{
+			name:    "kolesterol mmol/L dikirim sebagai mg/dL",
+			modify:  func(r *dto.CreateDiagnosisRequest) { r.SerumCholesterol = 6.2 },
+			wantErr: true,
+		},
+		{
+			name:    "vokabulari tidak dikenal",
+			modify:  func(r *dto.CreateDiagnosisRequest) { r.Sex = "x" },
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := validDiagnosisRequest()
+			tt.modify(&req)
+
+			warnings, inputErr := normalizeInput(&req)
+			if tt.wantErr {
+				if inputErr == nil {
+					t.Fatal("normalizeInput harus mengembalikan InputError")
+				}
+				return
+			}
+			if inputErr != nil {
+				t.Fatalf("normalizeInput error = %s: %v", inputErr.Message, inputErr.Details)
+			}
+			if len(warnings) != tt.wantWarnings {
+				t.Errorf("warnings = %d, want %d", len(warnings), tt.wantWarnings)
+			}
+			if req.SerumCholesterol != tt.wantChol || req.CholesterolUnit != "mg/dL" {
+				t.Errorf("cholesterol = %v %s, want %v mg/dL", req.SerumCholesterol, req.CholesterolUnit, tt.wantChol)
+			}
+			if req.FastingBloodSugar != tt.wantGlucose || req.GlucoseUnit != "mg/dL" {
+				t.Errorf("glucose = %v %s, want %v mg/dL", req.FastingBloodSugar, req.GlucoseUnit, tt.wantGlucose)
+			}
+		})
+	}
+}
+
+func TestNormalizeFieldsChecksOnlyScenarioOverrides(t *testing.T) {
+	// Data lama: kolesterol 0 dan tekanan darah di atas batas lunak
+	base := validDiagnosisRequest()
+	base.SerumCholesterol = 0
+	base.RestingBloodPressure = 210
+
+	intPtr := func(v int) *int { return &v }
+	floatPtr := func(v float64) *float64 { return &v }
+
+	tests := []struct {
+		name         string
+		scenario     dto.SimulationScenario
+		wantErr      bool
+		wantWarnings int
+	}{
+		{name: "tanpa perubahan", scenario: dto.SimulationScenario{}},
+		{name: "usia diubah", scenario: dto.SimulationScenario{Age: intPtr(60)}},
+		{name: "usia di luar batas keras", scenario: dto.SimulationScenario{Age: intPtr(130)}, wantErr: true},
+		{name: "tekanan darah diubah ke nilai wajar", scenario: dto.SimulationScenario{RestingBloodPressure: floatPtr(120)}},
+		{name: "tekanan darah tetap tinggi", scenario: dto.SimulationScenario{RestingBloodPressure: floatPtr(205)}, wantWarnings: 1},
+		{name: "kolesterol diubah ke nilai tidak wajar", scenario: dto.SimulationScenario{SerumCholesterol: floatPtr(20)}, wantErr: true},
+		{name: "jumlah pembuluh di luar rentang", scenario: dto.SimulationScenario{MajorVessels: intPtr(4)}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			input := tt.scenario.ApplyTo(base)
+			warnings, inputErr := normalizeFields(&input, tt.scenario.Fields())
+			if tt.wantErr {
+				if inputErr == nil {
+					t.Fatal("normalizeFields harus mengembalikan InputError")
+				}
+				return
+			}
+			if inputErr != nil {
+				t.Fatalf("normalizeFields error = %s: %v", inputErr.Message, inputErr.Details)
+			}
+			if len(warnings) != tt.wantWarnings {
+				t.Errorf("warnings = %v, want %d", warnings, tt.wantWarnings)
+			}
+		})
+	}
+}
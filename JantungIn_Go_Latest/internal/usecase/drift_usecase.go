@@ -0,0 +1,201 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"jantungin-api-server/internal/data/entity"
+	"jantungin-api-server/internal/data/repository"
+	"jantungin-api-server/internal/drift"
+	"jantungin-api-server/pkg/background"
+	"jantungin-api-server/pkg/utils"
+
+	"go.uber.org/zap"
+)
+
+// DriftReport adalah drift input diagnosis dalam satu jendela waktu terhadap data training
+type DriftReport struct {
+	WindowStart        time.Time             `json:"windowStart"`
+	WindowEnd          time.Time             `json:"windowEnd"`
+	WindowDiagnoses    int64                 `json:"windowDiagnoses"` // semua diagnosis dalam jendela
+	SampleSize         int                   `json:"sampleSize"`      // diagnosis acak yang dianalisis, paling banyak DRIFT_MAX_SAMPLES
+	Sufficient         bool                  `json:"sufficient"`      // false jika sampel < DRIFT_MIN_SAMPLES, drift tidak dinilai
+	PSIThreshold       float64               `json:"psiThreshold"`
+	ZScoreThreshold    float64               `json:"zScoreThreshold"`
+	MeanShiftThreshold float64               `json:"meanShiftThreshold"`
+	DriftedFeatures    []string              `json:"driftedFeatures"`
+	Features           []entity.DriftFeature `json:"features"`
+}
+
+type DriftUsecase interface {
+	GetCurrentDrift(ctx context.Context, days int) (*DriftReport, error)
+	GetDriftChecks(ctx context.Context) ([]entity.DriftCheck, error)
+	RunDriftCheck(ctx context.Context) (*entity.DriftCheck, error)
+	StartMonitoring(runner *background.Runner)
+}
+
+type driftUsecase struct {
+	driftRepo     repository.DriftRepository
+	diagnosisRepo repository.DiagnosisRepository
+	baseline      *drift.Baseline
+	cfg           utils.DriftConfig
+}
+
+func NewDriftUsecase(driftRepo repository.DriftRepository, diagnosisRepo repository.DiagnosisRepository, cfg *utils.Config) DriftUsecase {
+	baseline, err := drift.DefaultBaseline()
+	if err != nil {
+		utils.Error("Failed to load drift baseline", zap.Error(err))
+	}
+
+	return &driftUsecase{
+		driftRepo:     driftRepo,
+		diagnosisRepo: diagnosisRepo,
+		baseline:      baseline,
+		cfg:           cfg.Drift,
+	}
+}
+
+// GetCurrentDrift menghitung drift untuk diagnosis beberapa hari terakhir tanpa menyimpannya.
+// days <= 0 memakai DRIFT_WINDOW_DAYS.
+func (u *driftUsecase) GetCurrentDrift(ctx context.Context, days int) (*DriftReport, error) {
+	if days <= 0 {
+		days = u.cfg.WindowDays
+	}
+	if days > 365 {
+		return nil, errors.New("days maksimal 365")
+	}
+
+	end := time.Now()
+	return u.compute(ctx, end.AddDate(0, 0, -days), end)
+}
+
+func (u *driftUsecase) GetDriftChecks(ctx context.Context) ([]entity.DriftCheck, error) {
+	return u.driftRepo.FindRecent(ctx, 100)
+}
+
+// RunDriftCheck menghitung drift untuk jendela DRIFT_WINDOW_DAYS, menyimpan hasilnya,
+// dan mencatat alert jika ada fitur yang melewati ambang batas.
+func (u *driftUsecase) RunDriftCheck(ctx context.Context) (*entity.DriftCheck, error) {
+	end := time.Now()
+	report, err := u.compute(ctx, end.AddDate(0, 0, -u.cfg.WindowDays), end)
+	if err != nil {
+		return nil, err
+	}
+
+	check := &entity.DriftCheck{
+		WindowStart:     report.WindowStart,
+		WindowEnd:       report.WindowEnd,
+		SampleSize:      report.SampleSize,
+		Alert:           len(report.DriftedFeatures) > 0,
+		DriftedFeatures: report.DriftedFeatures,
+		Features:        report.Features,
+	}
+	if err := u.driftRepo.Create(ctx, check); err != nil {
+		utils.Error("Failed to save drift check", zap.Error(err))
+		return nil, errors.New("gagal menyimpan hasil drift check")
+	}
+
+	if check.Alert {
+		for _, f := range report.Features {
+			if !f.Drifted {
+				continue
+			}
+			utils.Warn("Input drift detected",
+				zap.String("feature", f.Feature),
+				zap.Float64("psi", f.PSI),
+				zap.Float64("z_score", f.ZScore),
+				zap.Float64("sample_mean", f.SampleMean),
+				zap.Float64("training_mean", f.TrainingMean),
+				zap.Int("sample_size", f.SampleSize),
+			)
+		}
+	} else {
+		utils.Info("Drift check completed",
+			zap.Int("sample_size", check.SampleSize),
+			zap.Bool("sufficient", report.Sufficient),
+		)
+	}
+
+	return check, nil
+}
+
+// StartMonitoring menjalankan RunDriftCheck setiap DRIFT_CHECK_INTERVAL sampai runner di-shutdown
+func (u *driftUsecase) StartMonitoring(runner *background.Runner) {
+	if u.cfg.CheckInterval <= 0 {
+		utils.Info("Drift monitoring disabled")
+		return
+	}
+
+	err := runner.Go("drift-monitor", func(ctx context.Context) {
+		ticker := time.NewTicker(u.cfg.CheckInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if _, err := u.RunDriftCheck(ctx); err != nil {
+					utils.Error("Scheduled drift check failed", zap.Error(err))
+				}
+			}
+		}
+	})
+	if err != nil {
+		utils.Warn("Drift monitoring not started", zap.Error(err))
+	}
+}
+
+func (u *driftUsecase) compute(ctx context.Context, start, end time.Time) (*DriftReport, error) {
+	if u.baseline == nil {
+		return nil, errors.New("baseline data training tidak tersedia")
+	}
+
+	total, err := u.diagnosisRepo.CountCreatedBetween(ctx, start, end)
+	if err != nil {
+		utils.Error("Failed to count diagnoses for drift check", zap.Error(err))
+		return nil, err
+	}
+
+	maxSamples := u.cfg.MaxSamples
+	if maxSamples <= 0 {
+		maxSamples = 5000
+	}
+	diagnoses, err := u.diagnosisRepo.SampleCreatedBetween(ctx, start, end, maxSamples)
+	if err != nil {
+		utils.Error("Failed to read diagnoses for drift check", zap.Error(err))
+		return nil, err
+	}
+
+	features := drift.Compare(u.baseline, drift.Collect(diagnoses), drift.Thresholds{
+		PSI:       u.cfg.PSIThreshold,
+		ZScore:    u.cfg.ZScoreThreshold,
+		MeanShift: u.cfg.MeanShiftThreshold,
+	})
+
+	report := &DriftReport{
+		WindowStart:        start,
+		WindowEnd:          end,
+		WindowDiagnoses:    total,
+		SampleSize:         len(diagnoses),
+		Sufficient:         len(diagnoses) >= u.cfg.MinSamples,
+		PSIThreshold:       u.cfg.PSIThreshold,
+		ZScoreThreshold:    u.cfg.ZScoreThreshold,
+		MeanShiftThreshold: u.cfg.MeanShiftThreshold,
+		DriftedFeatures:    []string{},
+		Features:           features,
+	}
+
+	for i := range report.Features {
+		// Sampel terlalu sedikit: statistik tetap ditampilkan tetapi tidak dianggap drift
+		if !report.Sufficient {
+			report.Features[i].Drifted = false
+		}
+		if report.Features[i].Drifted {
+			report.DriftedFeatures = append(report.DriftedFeatures, report.Features[i].Feature)
+		}
+	}
+
+	return report, nil
+}
@@ -0,0 +1,12 @@
+package usecase
+
+// InputError menandakan input dari client tidak lolos validasi.
+// Details dikirim apa adanya sebagai field "error" pada response 400.
+type InputError struct {
+	Message string
+	Details any
+}
+
+func (e *InputError) Error() string {
+	return e.Message
+}
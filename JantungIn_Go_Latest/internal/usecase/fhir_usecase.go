@@ -0,0 +1,194 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+
+	"jantungin-api-server/internal/data/entity"
+	"jantungin-api-server/internal/data/repository"
+	"jantungin-api-server/internal/fhir"
+
+	"github.com/google/uuid"
+)
+
+// FHIRUsecase menyajikan data pasien dan diagnosis sebagai resource FHIR R4.
+// Pasien (role user) hanya bisa mengakses datanya sendiri, admin/dokter semua pasien.
+type FHIRUsecase interface {
+	GetPatient(ctx context.Context, userID string, role string, patientID string) (*fhir.Patient, error)
+	GetObservation(ctx context.Context, userID string, role string, observationID string) (*fhir.Observation, error)
+	GetRiskAssessment(ctx context.Context, userID string, role string, riskAssessmentID string) (*fhir.RiskAssessment, error)
+	SearchObservations(ctx context.Context, userID string, role string, patientID string, code string) ([]fhir.Resource, error)
+	SearchRiskAssessments(ctx context.Context, userID string, role string, patientID string) ([]fhir.Resource, error)
+	PatientEverything(ctx context.Context, userID string, role string, patientID string) ([]fhir.Resource, error)
+}
+
+type fhirUsecase struct {
+	userRepo      repository.UserRepository
+	diagnosisRepo repository.DiagnosisRepository
+}
+
+func NewFHIRUsecase(userRepo repository.UserRepository, diagnosisRepo repository.DiagnosisRepository) FHIRUsecase {
+	return &fhirUsecase{
+		userRepo:      userRepo,
+		diagnosisRepo: diagnosisRepo,
+	}
+}
+
+func (u *fhirUsecase) GetPatient(ctx context.Context, userID string, role string, patientID string) (*fhir.Patient, error) {
+	patient, diagnoses, err := u.loadPatient(ctx, userID, role, patientID)
+	if err != nil {
+		return nil, err
+	}
+
+	resource := fhir.PatientFromUser(*patient, latestSex(diagnoses))
+	return &resource, nil
+}
+
+func (u *fhirUsecase) GetObservation(ctx context.Context, userID string, role string, observationID string) (*fhir.Observation, error) {
+	diagnosisID, slug, ok := fhir.SplitObservationID(observationID)
+	if !ok {
+		return nil, errors.New("resource not found")
+	}
+	kind, ok := fhir.ObservationKindBySlug(slug)
+	if !ok {
+		return nil, errors.New("resource not found")
+	}
+
+	diagnosis, err := u.loadDiagnosis(ctx, userID, role, diagnosisID)
+	if err != nil {
+		return nil, err
+	}
+
+	resource := fhir.ObservationFromDiagnosis(*diagnosis, kind)
+	return &resource, nil
+}
+
+func (u *fhirUsecase) GetRiskAssessment(ctx context.Context, userID string, role string, riskAssessmentID string) (*fhir.RiskAssessment, error) {
+	diagnosis, err := u.loadDiagnosis(ctx, userID, role, riskAssessmentID)
+	if err != nil {
+		return nil, err
+	}
+
+	resource := fhir.RiskAssessmentFromDiagnosis(*diagnosis)
+	return &resource, nil
+}
+
+// SearchObservations mencari Observation milik pasien; code opsional ("code" atau "system|code")
+func (u *fhirUsecase) SearchObservations(ctx context.Context, userID string, role string, patientID string, code string) ([]fhir.Resource, error) {
+	_, diagnoses, err := u.loadPatient(ctx, userID, role, u.searchPatientID(userID, role, patientID))
+	if err != nil {
+		return nil, err
+	}
+
+	var resources []fhir.Resource
+	for _, d := range diagnoses {
+		for _, kind := range fhir.ObservationKinds() {
+			if code != "" && !kind.MatchesCode(code) {
+				continue
+			}
+			resources = append(resources, fhir.ObservationFromDiagnosis(d, kind))
+		}
+	}
+	return resources, nil
+}
+
+func (u *fhirUsecase) SearchRiskAssessments(ctx context.Context, userID string, role string, patientID string) ([]fhir.Resource, error) {
+	_, diagnoses, err := u.loadPatient(ctx, userID, role, u.searchPatientID(userID, role, patientID))
+	if err != nil {
+		return nil, err
+	}
+
+	resources := make([]fhir.Resource, len(diagnoses))
+	for i, d := range diagnoses {
+		resources[i] = fhir.RiskAssessmentFromDiagnosis(d)
+	}
+	return resources, nil
+}
+
+// PatientEverything mengembalikan Patient beserta semua RiskAssessment dan Observation-nya
+func (u *fhirUsecase) PatientEverything(ctx context.Context, userID string, role string, patientID string) ([]fhir.Resource, error) {
+	patient, diagnoses, err := u.loadPatient(ctx, userID, role, patientID)
+	if err != nil {
+		return nil, err
+	}
+
+	resources := []fhir.Resource{fhir.PatientFromUser(*patient, latestSex(diagnoses))}
+	for _, d := range diagnoses {
+		resources = append(resources, fhir.RiskAssessmentFromDiagnosis(d))
+		for _, o := range fhir.ObservationsFromDiagnosis(d) {
+			resources = append(resources, o)
+		}
+	}
+	return resources, nil
+}
+
+// searchPatientID: pasien yang mencari tanpa parameter patient otomatis memakai ID-nya sendiri
+func (u *fhirUsecase) searchPatientID(userID string, role string, patientID string) string {
+	if patientID == "" && !isClinician(role) {
+		return userID
+	}
+	return patientID
+}
+
+// loadPatient memuat pasien beserta diagnosisnya (terbaru di atas) dan mengecek hak akses
+func (u *fhirUsecase) loadPatient(ctx context.Context, userID string, role string, patientID string) (*entity.User, []entity.Diagnosis, error) {
+	if patientID == "" {
+		return nil, nil, errors.New("parameter patient wajib diisi")
+	}
+
+	uid, err := uuid.Parse(patientID)
+	if err != nil {
+		return nil, nil, errors.New("resource not found")
+	}
+
+	// Pasien lain diperlakukan sebagai tidak ditemukan agar keberadaannya tidak bocor
+	if !isClinician(role) && patientID != userID {
+		return nil, nil, errors.New("resource not found")
+	}
+
+	patient, err := u.userRepo.FindByID(ctx, uid)
+	if err != nil {
+		return nil, nil, err
+	}
+	if patient == nil || patient.Role != "user" {
+		return nil, nil, errors.New("resource not found")
+	}
+
+	diagnoses, err := u.diagnosisRepo.FindByPatientID(ctx, uid)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return patient, diagnoses, nil
+}
+
+func (u *fhirUsecase) loadDiagnosis(ctx context.Context, userID string, role string, diagnosisID string) (*entity.Diagnosis, error) {
+	did, err := uuid.Parse(diagnosisID)
+	if err != nil {
+		return nil, errors.New("resource not found")
+	}
+
+	diagnosis, err := u.diagnosisRepo.FindByID(ctx, did)
+	if err != nil {
+		return nil, err
+	}
+	if diagnosis == nil {
+		return nil, errors.New("resource not found")
+	}
+	if !isClinician(role) && diagnosis.UserID.String() != userID {
+		return nil, errors.New("resource not found")
+	}
+
+	return diagnosis, nil
+}
+
+func isClinician(role string) bool {
+	return role == "admin" || role == "dokter"
+}
+
+func latestSex(diagnoses []entity.Diagnosis) string {
+	if len(diagnoses) == 0 {
+		return ""
+	}
+	return diagnoses[0].Sex
+}
@@ -0,0 +1,623 @@
+package usecase
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"jantungin-api-server/internal/clinical"
+	"jantungin-api-server/internal/data/entity"
+	"jantungin-api-server/internal/data/repository"
+	"jantungin-api-server/internal/dto"
+	"jantungin-api-server/internal/spreadsheet"
+	"jantungin-api-server/internal/vocabulary"
+	"jantungin-api-server/pkg/background"
+	"jantungin-api-server/pkg/utils"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// ImportUsecase memproses upload CSV/XLSX berisi banyak diagnosis secara async.
+// Setiap baris divalidasi seperti POST /diagnosis, pasien dicocokkan (atau dibuat),
+// lalu prediksi dijalankan oleh sejumlah worker terbatas.
+type ImportUsecase interface {
+	StartImport(ctx context.Context, userID string, fileName string, data []byte) (*entity.ImportJob, error)
+	GetImportJobs(ctx context.Context, userID string, role string) ([]entity.ImportJob, error)
+	GetImportJob(ctx context.Context, userID string, role string, jobID string) (*entity.ImportJob, error)
+	GetImportJobRows(ctx context.Context, userID string, role string, jobID string, status string) ([]entity.ImportJobRow, error)
+}
+
+type importUsecase struct {
+	importRepo       repository.ImportJobRepository
+	userRepo         repository.UserRepository
+	diagnosisUsecase DiagnosisUsecase
+	runner           *background.Runner
+	workers          int
+	maxRows          int
+}
+
+func NewImportUsecase(
+	importRepo repository.ImportJobRepository,
+	userRepo repository.UserRepository,
+	diagnosisUsecase DiagnosisUsecase,
+	runner *background.Runner,
+	cfg *utils.Config,
+) ImportUsecase {
+	return &importUsecase{
+		importRepo:       importRepo,
+		userRepo:         userRepo,
+		diagnosisUsecase: diagnosisUsecase,
+		runner:           runner,
+		workers:          max(cfg.App.ImportWorkers, 1),
+		maxRows:          cfg.App.ImportMaxRows,
+	}
+}
+
+// Kolom file import. Nama kolom dicocokkan tanpa memperhatikan huruf besar/kecil,
+// spasi, "_" dan "-". Nama kolom dataset UCI (cp, trestbps, ...) juga diterima.
+const (
+	colPatientID             = "patientId"
+	colPatientUsername       = "patientUsername"
+	colPatientEmail          = "patientEmail"
+	colPatientName           = "patientName"
+	colPatientDateOfBirth    = "patientDateOfBirth"
+	colAge                   = "age"
+	colSex                   = "sex"
+	colChestPainType         = "chestPainType"
+	colRestingBloodPressure  = "restingBloodPressure"
+	colSerumCholesterol      = "serumCholesterol"
+	colCholesterolUnit       = "cholesterolUnit"
+	colFastingBloodSugar     = "fastingBloodSugar"
+	colGlucoseUnit           = "glucoseUnit"
+	colRestingEcgResults     = "restingEcgResults"
+	colMaximumHeartRate      = "maximumHeartRate"
+	colExerciseInducedAngina = "exerciseInducedAngina"
+	colStDepression          = "stDepression"
+	colStSegment             = "stSegment"
+	colMajorVessels          = "majorVessels"
+	colThalassemia           = "thalassemia"
+	colClinicalNotes         = "clinicalNotes"
+)
+
+var importColumnAliases = map[string][]string{
+	colPatientID:             {"patient id", "id pasien"},
+	colPatientUsername:       {"username"},
+	colPatientEmail:          {"email"},
+	colPatientName:           {"name", "nama", "nama pasien"},
+	colPatientDateOfBirth:    {"dateOfBirth", "dob", "tanggal lahir"},
+	colChestPainType:         {"cp"},
+	colRestingBloodPressure:  {"trestbps"},
+	colSerumCholesterol:      {"chol"},
+	colRestingEcgResults:     {"restecg"},
+	colMaximumHeartRate:      {"thalach"},
+	colExerciseInducedAngina: {"exang"},
+	colStDepression:          {"oldpeak"},
+	colStSegment:             {"slope"},
+	colMajorVessels:          {"ca"},
+	colThalassemia:           {"thal"},
+}
+
+var requiredImportColumns = []string{
+	colAge, colSex, colChestPainType, colRestingBloodPressure, colSerumCholesterol,
+	colFastingBloodSugar, colRestingEcgResults, colMaximumHeartRate, colExerciseInducedAngina,
+	colStDepression, colStSegment, colMajorVessels, colThalassemia,
+}
+
+var patientImportColumns = []string{colPatientID, colPatientUsername, colPatientEmail, colPatientName}
+
+var nonAlphaNum = regexp.MustCompile(`[^a-z0-9]+`)
+
+func columnKey(s string) string {
+	return nonAlphaNum.ReplaceAllString(strings.ToLower(s), "")
+}
+
+// importColumns memetakan nama kolom kanonik ke indeks kolom di header
+type importColumns map[string]int
+
+func mapImportColumns(header []string) (importColumns, error) {
+	lookup := make(map[string]string)
+	for _, name := range []string{
+		colPatientID, colPatientUsername, colPatientEmail, colPatientName, colPatientDateOfBirth,
+		colAge, colSex, colChestPainType, colRestingBloodPressure, colSerumCholesterol, colCholesterolUnit,
+		colFastingBloodSugar, colGlucoseUnit, colRestingEcgResults, colMaximumHeartRate,
+		colExerciseInducedAngina, colStDepression, colStSegment, colMajorVessels, colThalassemia, colClinicalNotes,
+	} {
+		lookup[columnKey(name)] = name
+		for _, alias := range importColumnAliases[name] {
+			lookup[columnKey(alias)] = name
+		}
+	}
+
+	columns := make(importColumns)
+	for i, h := range header {
+		if name, ok := lookup[columnKey(h)]; ok {
+			if _, dup := columns[name]; !dup {
+				columns[name] = i
+			}
+		}
+	}
+
+	var missing []string
+	for _, name := range requiredImportColumns {
+		if _, ok := columns[name]; !ok {
+			missing = append(missing, name)
+		}
+	}
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("kolom wajib tidak ditemukan: %s", strings.Join(missing, ", "))
+	}
+
+	hasPatient := false
+	for _, name := range patientImportColumns {
+		if _, ok := columns[name]; ok {
+			hasPatient = true
+		}
+	}
+	if !hasPatient {
+		return nil, fmt.Errorf("file harus memiliki salah satu kolom pasien: %s", strings.Join(patientImportColumns, ", "))
+	}
+
+	return columns, nil
+}
+
+func (c importColumns) value(row spreadsheet.Row, name string) string {
+	i, ok := c[name]
+	if !ok {
+		return ""
+	}
+	return row.Get(i)
+}
+
+func (u *importUsecase) StartImport(ctx context.Context, userID string, fileName string, data []byte) (*entity.ImportJob, error) {
+	creatorUID, err := uuid.Parse(userID)
+	if err != nil {
+		return nil, errors.New("invalid user ID")
+	}
+
+	format, err := spreadsheet.DetectFormat(fileName)
+	if err != nil {
+		return nil, &InputError{Message: "File import tidak valid", Details: err.Error()}
+	}
+
+	table, err := spreadsheet.Parse(format, data)
+	if err != nil {
+		return nil, &InputError{Message: "File import tidak valid", Details: err.Error()}
+	}
+
+	columns, err := mapImportColumns(table.Header)
+	if err != nil {
+		return nil, &InputError{Message: "File import tidak valid", Details: err.Error()}
+	}
+
+	if len(table.Rows) == 0 {
+		return nil, &InputError{Message: "File import tidak valid", Details: "file tidak memiliki baris data"}
+	}
+	if u.maxRows > 0 && len(table.Rows) > u.maxRows {
+		return nil, &InputError{
+			Message: "File import tidak valid",
+			Details: fmt.Sprintf("maksimal %d baris per file, file berisi %d baris", u.maxRows, len(table.Rows)),
+		}
+	}
+
+	job := &entity.ImportJob{
+		CreatedBy: creatorUID,
+		FileName:  fileName,
+		Format:    format,
+		Status:    entity.ImportJobStatusQueued,
+		TotalRows: len(table.Rows),
+	}
+	if err := u.importRepo.Create(ctx, job); err != nil {
+		utils.Error("Failed to create import job", zap.Error(err))
+		return nil, errors.New("gagal membuat job import")
+	}
+
+	jobCopy := *job
+	if err := u.runner.Go("diagnosis-import:"+job.ID.String(), func(ctx context.Context) {
+		u.process(ctx, jobCopy, table, columns)
+	}); err != nil {
+		job.Status = entity.ImportJobStatusFailed
+		job.Error = "server sedang berhenti, silakan upload ulang"
+		_ = u.importRepo.UpdateStatus(ctx, job)
+		return nil, errors.New("server sedang berhenti, silakan upload ulang")
+	}
+
+	utils.Info("Diagnosis import queued",
+		zap.String("job_id", job.ID.String()),
+		zap.String("created_by", userID),
+		zap.Int("rows", job.TotalRows),
+	)
+
+	return job, nil
+}
+
+func (u *importUsecase) GetImportJobs(ctx context.Context, userID string, role string) ([]entity.ImportJob, error) {
+	if role == "admin" {
+		return u.importRepo.FindAll(ctx)
+	}
+
+	uid, err := uuid.Parse(userID)
+	if err != nil {
+		return nil, errors.New("invalid user ID")
+	}
+	return u.importRepo.FindByCreator(ctx, uid)
+}
+
+// GetImportJob hanya bisa diakses pembuat job atau admin
+func (u *importUsecase) GetImportJob(ctx context.Context, userID string, role string, jobID string) (*entity.ImportJob, error) {
+	jid, err := uuid.Parse(jobID)
+	if err != nil {
+		return nil, errors.New("invalid import job ID")
+	}
+
+	job, err := u.importRepo.FindByID(ctx, jid)
+	if err != nil {
+		return nil, err
+	}
+	if job == nil || (role != "admin" && job.CreatedBy.String() != userID) {
+		return nil, errors.New("import job not found")
+	}
+
+	return job, nil
+}
+
+func (u *importUsecase) GetImportJobRows(ctx context.Context, userID string, role string, jobID string, status string) ([]entity.ImportJobRow, error) {
+	if status != "" && status != entity.ImportRowStatusSuccess && status != entity.ImportRowStatusFailed {
+		return nil, errors.New("invalid row status")
+	}
+
+	job, err := u.GetImportJob(ctx, userID, role, jobID)
+	if err != nil {
+		return nil, err
+	}
+
+	return u.importRepo.FindRows(ctx, job.ID, status)
+}
+
+// process dijalankan di background runner. Jika ctx dibatalkan (server shutdown),
+// baris yang belum diproses dilewati dan job ditandai gagal.
+func (u *importUsecase) process(ctx context.Context, job entity.ImportJob, table *spreadsheet.Table, columns importColumns) {
+	// Status akhir tetap harus tersimpan walaupun ctx sudah dibatalkan
+	statusCtx := context.WithoutCancel(ctx)
+
+	startedAt := time.Now()
+	job.Status = entity.ImportJobStatusProcessing
+	job.StartedAt = &startedAt
+	if err := u.importRepo.UpdateStatus(statusCtx, &job); err != nil {
+		utils.Error("Failed to update import job status", zap.String("job_id", job.ID.String()), zap.Error(err))
+	}
+
+	resolver := &patientResolver{userRepo: u.userRepo}
+	rows := make(chan spreadsheet.Row)
+
+	var wg sync.WaitGroup
+	for range u.workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for row := range rows {
+				result := u.processRow(ctx, job, resolver, columns, row)
+				if err := u.importRepo.SaveRowResult(statusCtx, &result); err != nil {
+					utils.Error("Failed to save import row result",
+						zap.String("job_id", job.ID.String()),
+						zap.Int("row", row.Line),
+						zap.Error(err),
+					)
+				}
+			}
+		}()
+	}
+
+feed:
+	for _, row := range table.Rows {
+		select {
+		case rows <- row:
+		case <-ctx.Done():
+			break feed
+		}
+	}
+	close(rows)
+	wg.Wait()
+
+	finishedAt := time.Now()
+	job.FinishedAt = &finishedAt
+	job.Status = entity.ImportJobStatusCompleted
+	if ctx.Err() != nil {
+		job.Status = entity.ImportJobStatusFailed
+		job.Error = "import dihentikan karena server berhenti sebelum semua baris diproses"
+	}
+	if err := u.importRepo.UpdateStatus(statusCtx, &job); err != nil {
+		utils.Error("Failed to update import job status", zap.String("job_id", job.ID.String()), zap.Error(err))
+	}
+
+	utils.Info("Diagnosis import finished",
+		zap.String("job_id", job.ID.String()),
+		zap.String("status", job.Status),
+		zap.Duration("duration", finishedAt.Sub(startedAt)),
+	)
+}
+
+func (u *importUsecase) processRow(ctx context.Context, job entity.ImportJob, resolver *patientResolver, columns importColumns, row spreadsheet.Row) entity.ImportJobRow {
+	result := entity.ImportJobRow{
+		JobID:     job.ID,
+		RowNumber: row.Line,
+		Status:    entity.ImportRowStatusFailed,
+	}
+
+	req, errs := buildImportRequest(columns, row)
+	if len(errs) > 0 {
+		result.Errors = errs
+		return result
+	}
+
+	// Validasi dulu sebelum mencocokkan pasien agar baris tidak valid tidak membuat pasien baru
+	if _, inputErr := normalizeInput(&req); inputErr != nil {
+		result.Errors = inputErrorMessages(inputErr)
+		return result
+	}
+
+	patient, err := resolver.resolve(ctx, patientIdentity{
+		ID:          columns.value(row, colPatientID),
+		Username:    columns.value(row, colPatientUsername),
+		Email:       columns.value(row, colPatientEmail),
+		Name:        columns.value(row, colPatientName),
+		DateOfBirth: columns.value(row, colPatientDateOfBirth),
+	})
+	if err != nil {
+		result.Errors = []string{err.Error()}
+		return result
+	}
+	result.PatientID = &patient.ID
+	req.PatientID = patient.ID.String()
+
+	// Baris import tidak memicu alert per diagnosis
+	created, err := u.diagnosisUsecase.CreateDiagnosis(withoutAlerts(ctx), job.CreatedBy.String(), req)
+	if err != nil {
+		var inputErr *InputError
+		if errors.As(err, &inputErr) {
+			result.Errors = inputErrorMessages(inputErr)
+		} else {
+			result.Errors = []string{err.Error()}
+		}
+		return result
+	}
+
+	diagnosisID, err := uuid.Parse(created.ID)
+	if err == nil {
+		result.DiagnosisID = &diagnosisID
+	}
+	result.Status = entity.ImportRowStatusSuccess
+	return result
+}
+
+// buildImportRequest mengubah satu baris menjadi CreateDiagnosisRequest.
+// Kesalahan format angka dikumpulkan per kolom.
+func buildImportRequest(columns importColumns, row spreadsheet.Row) (dto.CreateDiagnosisRequest, []string) {
+	var errs []string
+
+	intValue := func(name string) int {
+		raw := columns.value(row, name)
+		if raw == "" {
+			return 0
+		}
+		f, err := parseImportNumber(raw)
+		if err != nil || f != float64(int(f)) {
+			errs = append(errs, fmt.Sprintf("%s: %q bukan bilangan bulat", name, raw))
+			return 0
+		}
+		return int(f)
+	}
+	floatValue := func(name string) float64 {
+		raw := columns.value(row, name)
+		if raw == "" {
+			return 0
+		}
+		f, err := parseImportNumber(raw)
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("%s: %q bukan angka", name, raw))
+			return 0
+		}
+		return f
+	}
+
+	req := dto.CreateDiagnosisRequest{
+		Age:                   intValue(colAge),
+		Sex:                   columns.value(row, colSex),
+		ChestPainType:         columns.value(row, colChestPainType),
+		RestingBloodPressure:  floatValue(colRestingBloodPressure),
+		SerumCholesterol:      floatValue(colSerumCholesterol),
+		CholesterolUnit:       columns.value(row, colCholesterolUnit),
+		FastingBloodSugar:     floatValue(colFastingBloodSugar),
+		GlucoseUnit:           columns.value(row, colGlucoseUnit),
+		RestingEcgResults:     columns.value(row, colRestingEcgResults),
+		MaximumHeartRate:      intValue(colMaximumHeartRate),
+		ExerciseInducedAngina: columns.value(row, colExerciseInducedAngina),
+		StDepression:          floatValue(colStDepression),
+		StSegment:             columns.value(row, colStSegment),
+		MajorVessels:          intValue(colMajorVessels),
+		Thalassemia:           columns.value(row, colThalassemia),
+		ClinicalNotes:         columns.value(row, colClinicalNotes),
+	}
+
+	return req, errs
+}
+
+// parseImportNumber menerima desimal dengan titik atau koma ("1.5" / "1,5")
+func parseImportNumber(raw string) (float64, error) {
+	if !strings.Contains(raw, ".") {
+		raw = strings.Replace(raw, ",", ".", 1)
+	}
+	return strconv.ParseFloat(raw, 64)
+}
+
+// inputErrorMessages meratakan detail InputError menjadi daftar pesan per baris
+func inputErrorMessages(err *InputError) []string {
+	switch details := err.Details.(type) {
+	case vocabulary.ValueErrors:
+		messages := make([]string, len(details))
+		for i, e := range details {
+			messages[i] = e.Error()
+		}
+		return messages
+	case []clinical.Finding:
+		messages := make([]string, len(details))
+		for i, f := range details {
+			messages[i] = f.Message
+		}
+		return messages
+	case string:
+		return []string{err.Message + ": " + details}
+	case nil:
+		return []string{err.Message}
+	default:
+		return []string{fmt.Sprintf("%s: %v", err.Message, details)}
+	}
+}
+
+type patientIdentity struct {
+	ID          string
+	Username    string
+	Email       string
+	Name        string
+	DateOfBirth string
+}
+
+// patientResolver mencocokkan pasien berdasarkan ID, username, lalu email, dan membuat
+// pasien baru jika tidak ditemukan. Dikunci agar dua baris untuk pasien baru yang sama
+// tidak membuat dua akun.
+type patientResolver struct {
+	userRepo repository.UserRepository
+	mu       sync.Mutex
+}
+
+func (r *patientResolver) resolve(ctx context.Context, identity patientIdentity) (*entity.User, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	username := strings.ToLower(identity.Username)
+	email := strings.ToLower(identity.Email)
+
+	var found *entity.User
+	var err error
+	switch {
+	case identity.ID != "":
+		uid, parseErr := uuid.Parse(identity.ID)
+		if parseErr != nil {
+			return nil, fmt.Errorf("patientId %q tidak valid", identity.ID)
+		}
+		found, err = r.userRepo.FindByID(ctx, uid)
+		if err == nil && found == nil {
+			return nil, fmt.Errorf("pasien dengan patientId %s tidak ditemukan", identity.ID)
+		}
+	case username != "":
+		found, err = r.userRepo.FindByUsername(ctx, username)
+	}
+	if err != nil {
+		return nil, errors.New("gagal mencari pasien")
+	}
+	if found == nil && email != "" {
+		found, err = r.userRepo.FindByEmail(ctx, email)
+		if err != nil {
+			return nil, errors.New("gagal mencari pasien")
+		}
+	}
+
+	if found != nil {
+		if found.Role != "user" {
+			return nil, errors.New("akun yang cocok bukan akun pasien")
+		}
+		return found, nil
+	}
+
+	if strings.TrimSpace(identity.Name) == "" {
+		return nil, errors.New("pasien tidak ditemukan, isi patientName untuk membuat pasien baru")
+	}
+
+	return r.create(ctx, identity.Name, username, email, identity.DateOfBirth)
+}
+
+// create membuat akun pasien baru dengan password acak; pasien perlu reset password
+// sebelum bisa login sendiri.
+func (r *patientResolver) create(ctx context.Context, name, username, email, dateOfBirth string) (*entity.User, error) {
+	if username == "" {
+		username = generateUsername(name)
+	}
+	if len(username) < 3 {
+		return nil, errors.New("patientUsername minimal 3 karakter")
+	}
+
+	var dob *time.Time
+	if dateOfBirth != "" {
+		parsed, err := parseImportDate(dateOfBirth)
+		if err != nil {
+			return nil, err
+		}
+		dob = &parsed
+	}
+
+	randomPassword := make([]byte, 24)
+	if _, err := rand.Read(randomPassword); err != nil {
+		return nil, errors.New("gagal membuat akun pasien")
+	}
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(hex.EncodeToString(randomPassword)), bcrypt.DefaultCost)
+	if err != nil {
+		return nil, errors.New("gagal membuat akun pasien")
+	}
+
+	user := &entity.User{
+		Name:        strings.TrimSpace(name),
+		Username:    &username,
+		Password:    string(hashedPassword),
+		DateOfBirth: dob,
+		Role:        "user",
+	}
+	if email != "" {
+		user.Email = &email
+	}
+
+	if err := r.userRepo.Create(ctx, user); err != nil {
+		utils.Error("Failed to create patient from import", zap.Error(err))
+		return nil, errors.New("gagal membuat akun pasien")
+	}
+
+	return user, nil
+}
+
+// generateUsername membuat username dari nama ditambah suffix acak, misalnya "budi_santoso_3fa9c1"
+func generateUsername(name string) string {
+	base := strings.Trim(nonAlphaNum.ReplaceAllString(strings.ToLower(name), "_"), "_")
+	if len(base) > 40 {
+		base = base[:40]
+	}
+	if base == "" {
+		base = "pasien"
+	}
+
+	suffix := make([]byte, 3)
+	_, _ = rand.Read(suffix)
+	return base + "_" + hex.EncodeToString(suffix)
+}
+
+// parseImportDate menerima YYYY-MM-DD, DD/MM/YYYY, atau serial number tanggal Excel
+func parseImportDate(raw string) (time.Time, error) {
+	for _, layout := range []string{"2006-01-02", "02/01/2006", "2/1/2006"} {
+		if t, err := time.Parse(layout, raw); err == nil {
+			return t, nil
+		}
+	}
+
+	if serial, err := strconv.ParseFloat(raw, 64); err == nil && serial > 0 {
+		excelEpoch := time.Date(1899, 12, 30, 0, 0, 0, 0, time.UTC)
+		return excelEpoch.AddDate(0, 0, int(serial)), nil
+	}
+
+	return time.Time{}, fmt.Errorf("patientDateOfBirth %q tidak valid, gunakan YYYY-MM-DD", raw)
+}
@@ -3,9 +3,13 @@ package usecase
 import (
 	"context"
 	"errors"
+	"slices"
 
 	"jantungin-api-server/internal/data/entity"
 	"jantungin-api-server/internal/data/repository"
+	"jantungin-api-server/internal/dto"
+	"jantungin-api-server/internal/identity"
+	"jantungin-api-server/internal/vocabulary"
 
 	"github.com/google/uuid"
 )
@@ -14,14 +18,19 @@ type PatientUsecase interface {
 	GetAllPatients(ctx context.Context) ([]entity.User, error)
 	GetPatientByID(ctx context.Context, id string) (*entity.User, error)
 	SearchPatients(ctx context.Context, query string) ([]entity.User, error)
+	GetPatientTrend(ctx context.Context, id string) (*dto.PatientTrendData, error)
 }
 
+// Selisih risiko (poin persen) antara dua kunjungan terakhir yang masih dianggap stabil
+const trendStableMargin = 5.0
+
 type patientUsecase struct {
-	userRepo repository.UserRepository
+	userRepo      repository.UserRepository
+	diagnosisRepo repository.DiagnosisRepository
 }
 
-func NewPatientUsecase(userRepo repository.UserRepository) PatientUsecase {
-	return &patientUsecase{userRepo: userRepo}
+func NewPatientUsecase(userRepo repository.UserRepository, diagnosisRepo repository.DiagnosisRepository) PatientUsecase {
+	return &patientUsecase{userRepo: userRepo, diagnosisRepo: diagnosisRepo}
 }
 
 func (u *patientUsecase) GetAllPatients(ctx context.Context) ([]entity.User, error) {
@@ -45,9 +54,91 @@ func (u *patientUsecase) GetPatientByID(ctx context.Context, id string) (*entity
 	return user, nil
 }
 
+// SearchPatients mencari berdasarkan NIK jika query berupa 16 digit angka (pencocokan persis
+// lewat blind index, karena NIK terenkripsi), selain itu berdasarkan nama
 func (u *patientUsecase) SearchPatients(ctx context.Context, query string) ([]entity.User, error) {
 	if query == "" {
 		return []entity.User{}, nil
 	}
+
+	if identity.LooksLikeNIK(query) {
+		user, err := u.userRepo.FindByNIK(ctx, identity.NormalizeNIK(query))
+		if err != nil {
+			return nil, err
+		}
+		if user == nil || user.Role != "user" {
+			return []entity.User{}, nil
+		}
+		return []entity.User{*user}, nil
+	}
+
 	return u.userRepo.SearchByName(ctx, query)
 }
+
+// GetPatientTrend menyusun deret waktu risiko dan tanda vital utama pasien dari
+// seluruh diagnosisnya, beserta perubahan terhadap kunjungan sebelumnya.
+func (u *patientUsecase) GetPatientTrend(ctx context.Context, id string) (*dto.PatientTrendData, error) {
+	patient, err := u.GetPatientByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	diagnoses, err := u.diagnosisRepo.FindByPatientID(ctx, patient.ID)
+	if err != nil {
+		return nil, err
+	}
+	// FindByPatientID urut terbaru dulu, deret waktu dimulai dari kunjungan paling lama
+	slices.Reverse(diagnoses)
+
+	trend := &dto.PatientTrendData{
+		PatientID:   patient.ID.String(),
+		PatientName: patient.Name,
+		Visits:      len(diagnoses),
+		Direction:   "insufficient_data",
+		Points:      make([]dto.TrendPoint, len(diagnoses)),
+	}
+
+	for i, d := range diagnoses {
+		point := dto.TrendPoint{
+			DiagnosisID:          d.ID.String(),
+			Date:                 d.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
+			ResultPercentage:     d.ResultPercentage,
+			CardiovascularRisk:   d.CardiovascularRisk,
+			RestingBloodPressure: d.RestingBloodPressure,
+			SerumCholesterol:     d.SerumCholesterol,
+			MaximumHeartRate:     d.MaximumHeartRate,
+		}
+
+		if i > 0 {
+			prev := diagnoses[i-1]
+			point.ChangeSinceLast = &dto.TrendChange{
+				DaysSinceLast:        int(d.CreatedAt.Sub(prev.CreatedAt).Hours() / 24),
+				ResultPercentage:     d.ResultPercentage - prev.ResultPercentage,
+				RestingBloodPressure: d.RestingBloodPressure - prev.RestingBloodPressure,
+				SerumCholesterol:     d.SerumCholesterol - prev.SerumCholesterol,
+				MaximumHeartRate:     d.MaximumHeartRate - prev.MaximumHeartRate,
+			}
+			point.RiskWorsened = !vocabulary.IsHighRisk(prev.CardiovascularRisk) && vocabulary.IsHighRisk(d.CardiovascularRisk)
+		}
+
+		trend.Points[i] = point
+	}
+
+	if n := len(trend.Points); n >= 2 {
+		latest := trend.Points[n-1]
+		trend.ChangeSinceLast = latest.ChangeSinceLast
+		trend.RiskWorsened = latest.RiskWorsened
+
+		delta := latest.ChangeSinceLast.ResultPercentage
+		switch {
+		case latest.RiskWorsened || delta >= trendStableMargin:
+			trend.Direction = "worsening"
+		case delta <= -trendStableMargin:
+			trend.Direction = "improving"
+		default:
+			trend.Direction = "stable"
+		}
+	}
+
+	return trend, nil
+}
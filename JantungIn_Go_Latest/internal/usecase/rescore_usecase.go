@@ -0,0 +1,331 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"jantungin-api-server/internal/data/entity"
+	"jantungin-api-server/internal/data/repository"
+	"jantungin-api-server/internal/dto"
+	"jantungin-api-server/internal/services"
+	"jantungin-api-server/internal/vocabulary"
+	"jantungin-api-server/pkg/background"
+	"jantungin-api-server/pkg/utils"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// rescorePageSize adalah jumlah diagnosis yang dibaca dan diprediksi ulang per halaman
+const rescorePageSize = 200
+
+// RescoreReport adalah laporan perbandingan satu rescore job
+type RescoreReport struct {
+	Job     *entity.RescoreJob         `json:"job"`
+	Summary *repository.RescoreSummary `json:"summary"`
+}
+
+// RescoreUsecase menjalankan ulang model ML saat ini atas input diagnosis historis
+// setelah model baru di-deploy. Hasil disimpan sebagai diagnosis_scores terpisah;
+// nilai asli di diagnoses tidak pernah ditimpa.
+type RescoreUsecase interface {
+	StartRescore(ctx context.Context, userID string) (*entity.RescoreJob, error)
+	GetRescoreJobs(ctx context.Context) ([]entity.RescoreJob, error)
+	GetRescoreReport(ctx context.Context, jobID string) (*RescoreReport, error)
+	GetRescoreScores(ctx context.Context, jobID string, changedOnly bool) ([]entity.DiagnosisScore, error)
+	RecoverInterruptedJobs(runner *background.Runner)
+}
+
+type rescoreUsecase struct {
+	rescoreRepo   repository.RescoreRepository
+	diagnosisRepo repository.DiagnosisRepository
+	predictor     services.Predictor
+	runner        *background.Runner
+	modelVersion  string
+}
+
+func NewRescoreUsecase(
+	rescoreRepo repository.RescoreRepository,
+	diagnosisRepo repository.DiagnosisRepository,
+	predictor services.Predictor,
+	runner *background.Runner,
+	cfg *utils.Config,
+) RescoreUsecase {
+	return &rescoreUsecase{
+		rescoreRepo:   rescoreRepo,
+		diagnosisRepo: diagnosisRepo,
+		predictor:     predictor,
+		runner:        runner,
+		modelVersion:  cfg.App.MLModelVersion,
+	}
+}
+
+// StartRescore membuat job baru dan memprosesnya di background.
+// Hanya satu job yang boleh berjalan pada satu waktu.
+func (u *rescoreUsecase) StartRescore(ctx context.Context, userID string) (*entity.RescoreJob, error) {
+	creatorUID, err := uuid.Parse(userID)
+	if err != nil {
+		return nil, errors.New("invalid user ID")
+	}
+
+	active, err := u.rescoreRepo.FindActiveJob(ctx)
+	if err != nil {
+		utils.Error("Failed to check active rescore job", zap.Error(err))
+		return nil, errors.New("gagal membuat job rescore")
+	}
+	if active != nil {
+		return nil, errors.New("rescore job lain masih berjalan")
+	}
+
+	total, err := u.diagnosisRepo.CountAll(ctx)
+	if err != nil {
+		utils.Error("Failed to count diagnoses", zap.Error(err))
+		return nil, errors.New("gagal membuat job rescore")
+	}
+
+	job := &entity.RescoreJob{
+		CreatedBy:      creatorUID,
+		ModelVersion:   u.modelVersion,
+		Status:         entity.RescoreJobStatusQueued,
+		TotalDiagnoses: int(total),
+	}
+	if err := u.rescoreRepo.CreateJob(ctx, job); err != nil {
+		if errors.Is(err, repository.ErrRescoreJobActive) {
+			return nil, errors.New("rescore job lain masih berjalan")
+		}
+		utils.Error("Failed to create rescore job", zap.Error(err))
+		return nil, errors.New("gagal membuat job rescore")
+	}
+
+	jobCopy := *job
+	if err := u.runner.Go("diagnosis-rescore:"+job.ID.String(), func(ctx context.Context) {
+		u.process(ctx, jobCopy)
+	}); err != nil {
+		job.Status = entity.RescoreJobStatusFailed
+		job.Error = "server sedang berhenti, silakan coba lagi"
+		_ = u.rescoreRepo.UpdateJobStatus(ctx, job)
+		return nil, errors.New("server sedang berhenti, silakan coba lagi")
+	}
+
+	utils.Info("Diagnosis rescore queued",
+		zap.String("job_id", job.ID.String()),
+		zap.String("created_by", userID),
+		zap.String("model_version", job.ModelVersion),
+		zap.Int("diagnoses", job.TotalDiagnoses),
+	)
+
+	return job, nil
+}
+
+func (u *rescoreUsecase) GetRescoreJobs(ctx context.Context) ([]entity.RescoreJob, error) {
+	return u.rescoreRepo.FindJobs(ctx)
+}
+
+func (u *rescoreUsecase) GetRescoreReport(ctx context.Context, jobID string) (*RescoreReport, error) {
+	job, err := u.findJob(ctx, jobID)
+	if err != nil {
+		return nil, err
+	}
+
+	summary, err := u.rescoreRepo.Summarize(ctx, job.ID)
+	if err != nil {
+		utils.Error("Failed to summarize rescore job", zap.String("job_id", jobID), zap.Error(err))
+		return nil, err
+	}
+
+	return &RescoreReport{Job: job, Summary: summary}, nil
+}
+
+func (u *rescoreUsecase) GetRescoreScores(ctx context.Context, jobID string, changedOnly bool) ([]entity.DiagnosisScore, error) {
+	job, err := u.findJob(ctx, jobID)
+	if err != nil {
+		return nil, err
+	}
+
+	return u.rescoreRepo.FindScores(ctx, job.ID, changedOnly)
+}
+
+func (u *rescoreUsecase) findJob(ctx context.Context, jobID string) (*entity.RescoreJob, error) {
+	jid, err := uuid.Parse(jobID)
+	if err != nil {
+		return nil, errors.New("invalid rescore job ID")
+	}
+
+	job, err := u.rescoreRepo.FindJobByID(ctx, jid)
+	if err != nil {
+		return nil, err
+	}
+	if job == nil {
+		return nil, errors.New("rescore job not found")
+	}
+
+	return job, nil
+}
+
+// RecoverInterruptedJobs menandai job queued/processing yang tertinggal karena server mati
+// (crash, SIGKILL) sebagai failed saat start, agar rescore baru bisa dimulai. Job yang
+// sedang diproses instance lain tidak diubah karena instance itu memegang lock rescore.
+func (u *rescoreUsecase) RecoverInterruptedJobs(runner *background.Runner) {
+	err := runner.Go("rescore-recovery", func(ctx context.Context) {
+		var failed int64
+		acquired, err := u.rescoreRepo.WithProcessingLock(ctx, func() error {
+			var err error
+			failed, err = u.rescoreRepo.FailActiveJobs(ctx, "rescore dihentikan karena server berhenti sebelum job selesai")
+			return err
+		})
+		switch {
+		case err != nil:
+			utils.Error("Failed to recover interrupted rescore jobs", zap.Error(err))
+		case !acquired:
+			utils.Info("Rescore job is running on another instance, recovery skipped")
+		case failed > 0:
+			utils.Warn("Interrupted rescore jobs marked as failed", zap.Int64("count", failed))
+		}
+	})
+	if err != nil {
+		utils.Warn("Rescore recovery not started", zap.Error(err))
+	}
+}
+
+// process membaca diagnosis per halaman (urut id), memprediksi ulang lewat
+// PredictBatch, dan menyimpan hasil tiap halaman dalam satu transaksi.
+// Selama diproses, advisory lock rescore dipegang agar RecoverInterruptedJobs di
+// instance lain tidak menganggap job ini sisa proses yang mati.
+func (u *rescoreUsecase) process(ctx context.Context, job entity.RescoreJob) {
+	// Status akhir tetap harus tersimpan walaupun ctx sudah dibatalkan
+	statusCtx := context.WithoutCancel(ctx)
+
+	acquired, err := u.rescoreRepo.WithProcessingLock(ctx, func() error {
+		u.processLocked(ctx, statusCtx, job)
+		return nil
+	})
+	if err == nil && acquired {
+		return
+	}
+	if err == nil {
+		err = errors.New("rescore job lain masih diproses")
+	}
+
+	finishedAt := time.Now()
+	job.Status = entity.RescoreJobStatusFailed
+	job.Error = err.Error()
+	job.FinishedAt = &finishedAt
+	if err := u.rescoreRepo.UpdateJobStatus(statusCtx, &job); err != nil {
+		utils.Error("Failed to update rescore job status", zap.String("job_id", job.ID.String()), zap.Error(err))
+	}
+	utils.Error("Diagnosis rescore not started", zap.String("job_id", job.ID.String()), zap.String("error", job.Error))
+}
+
+func (u *rescoreUsecase) processLocked(ctx, statusCtx context.Context, job entity.RescoreJob) {
+	startedAt := time.Now()
+	job.Status = entity.RescoreJobStatusProcessing
+	job.StartedAt = &startedAt
+	if err := u.rescoreRepo.UpdateJobStatus(statusCtx, &job); err != nil {
+		utils.Error("Failed to update rescore job status", zap.String("job_id", job.ID.String()), zap.Error(err))
+	}
+
+	err := u.rescoreAll(ctx, job)
+
+	finishedAt := time.Now()
+	job.FinishedAt = &finishedAt
+	job.Status = entity.RescoreJobStatusCompleted
+	switch {
+	case ctx.Err() != nil:
+		job.Status = entity.RescoreJobStatusFailed
+		job.Error = "rescore dihentikan karena server berhenti sebelum semua diagnosis diproses"
+	case err != nil:
+		job.Status = entity.RescoreJobStatusFailed
+		job.Error = err.Error()
+	}
+	if err := u.rescoreRepo.UpdateJobStatus(statusCtx, &job); err != nil {
+		utils.Error("Failed to update rescore job status", zap.String("job_id", job.ID.String()), zap.Error(err))
+	}
+
+	utils.Info("Diagnosis rescore finished",
+		zap.String("job_id", job.ID.String()),
+		zap.String("status", job.Status),
+		zap.Duration("duration", finishedAt.Sub(startedAt)),
+	)
+}
+
+func (u *rescoreUsecase) rescoreAll(ctx context.Context, job entity.RescoreJob) error {
+	after := uuid.Nil
+	for ctx.Err() == nil {
+		diagnoses, err := u.diagnosisRepo.FindBatchAfter(ctx, after, rescorePageSize)
+		if err != nil {
+			utils.Error("Failed to read diagnoses for rescore", zap.String("job_id", job.ID.String()), zap.Error(err))
+			return errors.New("gagal membaca diagnosis")
+		}
+		if len(diagnoses) == 0 {
+			return nil
+		}
+		after = diagnoses[len(diagnoses)-1].ID
+
+		scores, failed := u.scorePage(ctx, job, diagnoses)
+		if ctx.Err() != nil {
+			// Hasil halaman yang terpotong shutdown tidak disimpan sebagian
+			return nil
+		}
+		if err := u.rescoreRepo.SaveScores(context.WithoutCancel(ctx), job.ID, scores, failed); err != nil {
+			utils.Error("Failed to save rescore results", zap.String("job_id", job.ID.String()), zap.Error(err))
+			return errors.New("gagal menyimpan hasil rescore")
+		}
+	}
+	return nil
+}
+
+// scorePage memprediksi ulang satu halaman diagnosis. Diagnosis yang inputnya tidak
+// lagi lolos validasi atau gagal diprediksi dihitung sebagai failed.
+func (u *rescoreUsecase) scorePage(ctx context.Context, job entity.RescoreJob, diagnoses []entity.Diagnosis) ([]entity.DiagnosisScore, int) {
+	failed := 0
+	pending := make([]entity.Diagnosis, 0, len(diagnoses))
+	mlReqs := make([]services.MLPredictRequest, 0, len(diagnoses))
+
+	for _, d := range diagnoses {
+		req := dto.ToCreateDiagnosisRequest(d)
+		if _, inputErr := normalizeInput(&req); inputErr != nil {
+			utils.Warn("Skipping diagnosis with invalid stored input",
+				zap.String("job_id", job.ID.String()),
+				zap.String("diagnosis_id", d.ID.String()),
+				zap.Any("details", inputErr.Details),
+			)
+			failed++
+			continue
+		}
+		pending = append(pending, d)
+		mlReqs = append(mlReqs, toMLRequest(req))
+	}
+
+	results := u.predictor.PredictBatch(ctx, mlReqs)
+
+	scores := make([]entity.DiagnosisScore, 0, len(pending))
+	for i, d := range pending {
+		if results[i].Err != nil {
+			utils.Warn("Rescore prediction failed",
+				zap.String("job_id", job.ID.String()),
+				zap.String("diagnosis_id", d.ID.String()),
+				zap.Error(results[i].Err),
+			)
+			failed++
+			continue
+		}
+
+		result := results[i].Result
+		scores = append(scores, entity.DiagnosisScore{
+			JobID:                      job.ID,
+			DiagnosisID:                d.ID,
+			UserID:                     d.UserID,
+			ModelVersion:               job.ModelVersion,
+			ResultPercentage:           float64(result.ResultPercentage),
+			CardiovascularRisk:         result.CardiovascularRisk,
+			Prediction:                 result.Prediction,
+			PreviousModelVersion:       d.ModelVersion,
+			PreviousResultPercentage:   d.ResultPercentage,
+			PreviousCardiovascularRisk: d.CardiovascularRisk,
+			CategoryChanged:            vocabulary.IsHighRisk(result.CardiovascularRisk) != vocabulary.IsHighRisk(d.CardiovascularRisk),
+		})
+	}
+
+	return scores, failed
+}
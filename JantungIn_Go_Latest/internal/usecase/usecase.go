@@ -3,6 +3,8 @@ package usecase
 import (
 	"jantungin-api-server/internal/data/repository"
 	"jantungin-api-server/internal/services"
+	"jantungin-api-server/pkg/background"
+	"jantungin-api-server/pkg/mailer"
 	"jantungin-api-server/pkg/utils"
 
 	"gorm.io/gorm"
@@ -13,15 +15,34 @@ type UseCase struct {
 	DiagnosisUseCase DiagnosisUsecase
 	StatsUseCase     StatsUsecase
 	PatientUseCase   PatientUsecase
+	FHIRUseCase      FHIRUsecase
+	ImportUseCase    ImportUsecase
+	RescoreUseCase   RescoreUsecase
+	DriftUseCase     DriftUsecase
+	AlertUseCase     AlertUsecase
 }
 
-func NewUseCase(userRepo repository.UserRepository, diagnosisRepo repository.DiagnosisRepository, statsRepo repository.StatsRepository, userDeviceRepo repository.UserDeviceRepository, cfg *utils.Config, db *gorm.DB) *UseCase {
-	mlClient := services.NewMLClient(cfg.App.MLServiceURL)
+func NewUseCase(userRepo repository.UserRepository, diagnosisRepo repository.DiagnosisRepository, statsRepo repository.StatsRepository, userDeviceRepo repository.UserDeviceRepository, importJobRepo repository.ImportJobRepository, rescoreRepo repository.RescoreRepository, shadowRepo repository.ShadowRepository, driftRepo repository.DriftRepository, notificationRepo repository.NotificationRepository, idempotencyRepo repository.IdempotencyRepository, runner *background.Runner, cfg *utils.Config, db *gorm.DB) *UseCase {
+	mlClient := services.NewMLClient(cfg.App.MLServiceURL, cfg.App.MLBatchSize, cfg.App.MLBatchConcurrency)
+
+	// Model kandidat untuk shadow mode, nonaktif jika URL tidak diisi
+	var shadowPredictor services.Predictor
+	if cfg.App.MLShadowURL != "" {
+		shadowPredictor = services.NewMLClient(cfg.App.MLShadowURL, cfg.App.MLBatchSize, cfg.App.MLBatchConcurrency)
+	}
+
+	alertUseCase := NewAlertUsecase(notificationRepo, diagnosisRepo, userRepo, mailer.New(cfg.SMTP), runner)
+	diagnosisUseCase := NewDiagnosisUsecase(diagnosisRepo, userRepo, shadowRepo, mlClient, shadowPredictor, alertUseCase, idempotencyRepo, runner, cfg)
 
 	return &UseCase{
 		AuthUseCase:      NewAuthUsecase(userRepo, userDeviceRepo, cfg),
-		DiagnosisUseCase: NewDiagnosisUsecase(diagnosisRepo, userRepo, mlClient),
+		DiagnosisUseCase: diagnosisUseCase,
 		StatsUseCase:     NewStatsUsecase(statsRepo),
-		PatientUseCase:   NewPatientUsecase(userRepo),
+		PatientUseCase:   NewPatientUsecase(userRepo, diagnosisRepo),
+		FHIRUseCase:      NewFHIRUsecase(userRepo, diagnosisRepo),
+		ImportUseCase:    NewImportUsecase(importJobRepo, userRepo, diagnosisUseCase, runner, cfg),
+		RescoreUseCase:   NewRescoreUsecase(rescoreRepo, diagnosisRepo, mlClient, runner, cfg),
+		DriftUseCase:     NewDriftUsecase(driftRepo, diagnosisRepo, cfg),
+		AlertUseCase:     alertUseCase,
 	}
 }
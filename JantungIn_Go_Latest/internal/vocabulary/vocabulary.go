@@ -0,0 +1,239 @@
+// Package vocabulary berisi nilai kanonik untuk field klinis kategorikal
+// beserta alias Indonesia/Inggris yang diterima dari client.
+// Nilai kanonik mengikuti format yang diharapkan ML service.
+package vocabulary
+
+import (
+	"fmt"
+	"strings"
+)
+
+type Sex string
+
+const (
+	SexMale   Sex = "Male"
+	SexFemale Sex = "Female"
+)
+
+type ChestPainType string
+
+const (
+	ChestPainTypical      ChestPainType = "Typical angina"
+	ChestPainAtypical     ChestPainType = "Atypical angina"
+	ChestPainNonAnginal   ChestPainType = "Non-anginal pain"
+	ChestPainAsymptomatic ChestPainType = "Asymptomatic"
+)
+
+type RestingEcg string
+
+const (
+	RestingEcgNormal      RestingEcg = "Normal"
+	RestingEcgSTTAbnormal RestingEcg = "ST-T wave abnormality"
+	RestingEcgLVH         RestingEcg = "Left ventricular hypertrophy"
+)
+
+type ExerciseAngina string
+
+const (
+	ExerciseAnginaYes ExerciseAngina = "Yes"
+	ExerciseAnginaNo  ExerciseAngina = "No"
+)
+
+type StSlope string
+
+const (
+	StSlopeUpsloping   StSlope = "Upsloping"
+	StSlopeFlat        StSlope = "Flat"
+	StSlopeDownsloping StSlope = "Downsloping"
+)
+
+type Thalassemia string
+
+const (
+	ThalassemiaNormal     Thalassemia = "Normal"
+	ThalassemiaFixed      Thalassemia = "Fixed defect"
+	ThalassemiaReversible Thalassemia = "Reversible defect"
+)
+
+// RiskCategory adalah kategori risiko hasil prediksi ML service (field cardiovascularRisk)
+type RiskCategory string
+
+const (
+	RiskCategoryHigh RiskCategory = "High Risk"
+	RiskCategoryLow  RiskCategory = "Low"
+)
+
+// IsHighRisk melaporkan apakah nilai cardiovascularRisk termasuk kategori risiko tinggi
+func IsHighRisk(cardiovascularRisk string) bool {
+	return RiskCategory(cardiovascularRisk) == RiskCategoryHigh
+}
+
+// Term adalah satu nilai kanonik beserta label tampilan dan alias yang diterima
+type Term struct {
+	Value   string            `json:"value"`
+	Labels  map[string]string `json:"labels"`
+	Aliases []string          `json:"aliases"`
+}
+
+// Field adalah daftar nilai yang diizinkan untuk satu field input diagnosis.
+// Name sama dengan nama field JSON pada CreateDiagnosisRequest.
+type Field struct {
+	Name  string `json:"field"`
+	Terms []Term `json:"values"`
+
+	lookup map[string]string
+}
+
+func newField(name string, terms ...Term) *Field {
+	f := &Field{Name: name, Terms: terms, lookup: make(map[string]string)}
+	for _, t := range terms {
+		f.lookup[normalizeKey(t.Value)] = t.Value
+		for _, label := range t.Labels {
+			f.lookup[normalizeKey(label)] = t.Value
+		}
+		for _, alias := range t.Aliases {
+			f.lookup[normalizeKey(alias)] = t.Value
+		}
+	}
+	return f
+}
+
+func term(value, en, id string, aliases ...string) Term {
+	return Term{
+		Value:   value,
+		Labels:  map[string]string{"en": en, "id": id},
+		Aliases: aliases,
+	}
+}
+
+var (
+	SexField = newField("sex",
+		term(string(SexMale), "Male", "Laki-laki", "m", "l", "pria", "laki laki", "lakilaki"),
+		term(string(SexFemale), "Female", "Perempuan", "f", "p", "wanita"),
+	)
+
+	ChestPainTypeField = newField("chestPainType",
+		term(string(ChestPainTypical), "Typical angina", "Angina tipikal", "typical", "ta", "1"),
+		term(string(ChestPainAtypical), "Atypical angina", "Angina atipikal", "atypical", "ata", "2"),
+		term(string(ChestPainNonAnginal), "Non-anginal pain", "Nyeri non-anginal", "non anginal pain", "non-anginal", "nyeri non angina", "nap", "3"),
+		term(string(ChestPainAsymptomatic), "Asymptomatic", "Asimptomatik", "tanpa gejala", "asy", "4"),
+	)
+
+	RestingEcgField = newField("restingEcgResults",
+		term(string(RestingEcgNormal), "Normal", "Normal", "0"),
+		term(string(RestingEcgSTTAbnormal), "ST-T wave abnormality", "Abnormalitas gelombang ST-T", "st-t abnormality", "kelainan gelombang st-t", "st", "1"),
+		term(string(RestingEcgLVH), "Left ventricular hypertrophy", "Hipertrofi ventrikel kiri", "lvh", "2"),
+	)
+
+	ExerciseAnginaField = newField("exerciseInducedAngina",
+		term(string(ExerciseAnginaYes), "Yes", "Ya", "y", "true", "1"),
+		term(string(ExerciseAnginaNo), "No", "Tidak", "n", "false", "0"),
+	)
+
+	StSlopeField = newField("stSegment",
+		term(string(StSlopeUpsloping), "Upsloping", "Menanjak", "up", "naik", "1"),
+		term(string(StSlopeFlat), "Flat", "Datar", "2"),
+		term(string(StSlopeDownsloping), "Downsloping", "Menurun", "down", "turun", "3"),
+	)
+
+	ThalassemiaField = newField("thalassemia",
+		term(string(ThalassemiaNormal), "Normal", "Normal", "3"),
+		term(string(ThalassemiaFixed), "Fixed defect", "Cacat tetap", "fixed", "6"),
+		term(string(ThalassemiaReversible), "Reversible defect", "Cacat dapat dibalik", "reversable defect", "reversible", "reversable", "7"),
+	)
+)
+
+// Fields mengembalikan semua field kategorikal sesuai urutan form diagnosis
+func Fields() []*Field {
+	return []*Field{
+		SexField,
+		ChestPainTypeField,
+		RestingEcgField,
+		ExerciseAnginaField,
+		StSlopeField,
+		ThalassemiaField,
+	}
+}
+
+// Allowed mengembalikan daftar nilai kanonik field
+func (f *Field) Allowed() []string {
+	values := make([]string, len(f.Terms))
+	for i, t := range f.Terms {
+		values[i] = t.Value
+	}
+	return values
+}
+
+// Lookup mencari nilai kanonik dari input berupa nilai kanonik, label, atau alias
+// tanpa memperhatikan huruf besar/kecil dan spasi berlebih.
+func (f *Field) Lookup(input string) (string, bool) {
+	value, ok := f.lookup[normalizeKey(input)]
+	return value, ok
+}
+
+// Normalize sama seperti Lookup tetapi mengembalikan *ValueError jika tidak dikenali
+func (f *Field) Normalize(input string) (string, error) {
+	if value, ok := f.Lookup(input); ok {
+		return value, nil
+	}
+	return "", f.invalid(input)
+}
+
+func (f *Field) invalid(input string) *ValueError {
+	return &ValueError{Field: f.Name, Value: input, Allowed: f.Allowed()}
+}
+
+// Label mengembalikan label tampilan untuk bahasa "id" atau "en".
+// Nilai yang tidak dikenal dikembalikan apa adanya.
+func (f *Field) Label(value, lang string) string {
+	canonical, ok := f.Lookup(value)
+	if !ok {
+		return value
+	}
+	for _, t := range f.Terms {
+		if t.Value == canonical {
+			if label, ok := t.Labels[lang]; ok {
+				return label
+			}
+			return t.Value
+		}
+	}
+	return value
+}
+
+func normalizeKey(s string) string {
+	return strings.ToLower(strings.Join(strings.Fields(s), " "))
+}
+
+// ValueError dikembalikan jika nilai tidak ada di vokabulari
+type ValueError struct {
+	Field   string   `json:"field"`
+	Value   string   `json:"value"`
+	Allowed []string `json:"allowed"`
+}
+
+func (e *ValueError) Error() string {
+	return fmt.Sprintf("nilai %q tidak valid untuk %s, gunakan salah satu dari: %s",
+		e.Value, e.Field, strings.Join(e.Allowed, ", "))
+}
+
+// ValueErrors mengumpulkan semua field yang tidak valid dalam satu request
+type ValueErrors []*ValueError
+
+// NormalizeInto menormalkan *value di tempat. Jika tidak dikenali, nilai dibiarkan
+// dan error ditambahkan ke errs.
+func (f *Field) NormalizeInto(value *string, errs *ValueErrors) {
+	if canonical, ok := f.Lookup(*value); ok {
+		*value = canonical
+		return
+	}
+	*errs = append(*errs, f.invalid(*value))
+}
+
+func (e ValueErrors) Error() string {
+	messages := make([]string, len(e))
+	for i, err := range e {
+		messages[i] = err.Error()
+	}
+	return strings.Join(messages, "; ")
+}
@@ -4,14 +4,17 @@ import (
 	"jantungin-api-server/internal/adaptor"
 	"jantungin-api-server/internal/data/repository"
 	"jantungin-api-server/internal/usecase"
+	"jantungin-api-server/pkg/background"
+	"jantungin-api-server/pkg/health"
 	"jantungin-api-server/pkg/middleware"
 	"jantungin-api-server/pkg/utils"
 
 	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
 	"gorm.io/gorm"
 )
 
-func Wiring(cfg *utils.Config, db *gorm.DB) *gin.Engine {
+func Wiring(cfg *utils.Config, db *gorm.DB, runner *background.Runner, checker *health.Checker) *gin.Engine {
 	utils.Info("Starting route wiring process")
 
 	if cfg.App.Env == "production" {
@@ -23,6 +26,14 @@ func Wiring(cfg *utils.Config, db *gorm.DB) *gin.Engine {
 
 	router := gin.New()
 	router.Use(middleware.Recovery())
+
+	// Health check didaftarkan sebelum Logger dan RequestTracker agar probe
+	// load balancer/orchestrator tidak memenuhi log dan statistik kunjungan
+	if checker == nil {
+		checker = health.NewChecker(cfg.Health.CheckTimeout)
+	}
+	registerHealthRoutes(router, adaptor.NewHealthAdaptor(checker))
+
 	router.Use(middleware.Logger())
 	router.Use(middleware.CORS(cfg))
 
@@ -30,14 +41,28 @@ func Wiring(cfg *utils.Config, db *gorm.DB) *gin.Engine {
 	repo := repository.NewRepository(db)
 
 	// Initialize usecases
-	usecases := usecase.NewUseCase(repo.UserRepo, repo.DiagnosisRepo, repo.StatsRepo, repo.UserDeviceRepo, cfg, db)
+	usecases := usecase.NewUseCase(repo.UserRepo, repo.DiagnosisRepo, repo.StatsRepo, repo.UserDeviceRepo, repo.ImportJobRepo, repo.RescoreRepo, repo.ShadowRepo, repo.DriftRepo, repo.NotificationRepo, repo.IdempotencyRepo, runner, cfg, db)
+
+	// Pekerjaan berkala (runner nil saat wiring dipakai tanpa server, mis. untuk inspeksi route)
+	if runner != nil {
+		usecases.DriftUseCase.StartMonitoring(runner)
+		usecases.DiagnosisUseCase.StartIdempotencyCleanup(runner)
+		usecases.RescoreUseCase.RecoverInterruptedJobs(runner)
+	}
 
 	// Initialize adaptors
 	adaptors := adaptor.NewAdaptor(usecases)
 
 	// RequestTracker middleware — catat setiap request ke DB
-	// Dipasang setelah router global middleware agar status code sudah tersedia
-	router.Use(middleware.RequestTracker(repo.StatsRepo))
+	// Dipasang setelah router global middleware agar status code sudah tersedia.
+	// Tanpa runner (inspeksi route) tidak ada request yang dilayani.
+	if runner != nil {
+		logWriter := middleware.NewRequestLogWriter(repo.StatsRepo, cfg.RequestLog)
+		if err := logWriter.Start(runner); err != nil {
+			utils.Error("Failed to start request log writer", zap.Error(err))
+		}
+		router.Use(middleware.RequestTracker(logWriter))
+	}
 
 	// Register routes
 	api := router.Group("/api/v1")
@@ -45,12 +70,22 @@ func Wiring(cfg *utils.Config, db *gorm.DB) *gin.Engine {
 	registerDiagnosisRoutes(api, adaptors, cfg)
 	registerStatsRoutes(api, adaptors, cfg)
 	registerPatientRoutes(api, adaptors, cfg)
+	registerMetaRoutes(api, adaptors)
+	registerFHIRRoutes(api, adaptors, cfg)
+	registerMonitoringRoutes(api, adaptors, cfg)
+	registerNotificationRoutes(api, adaptors, cfg)
 
 	utils.Info("Route wiring completed")
 
 	return router
 }
 
+func registerHealthRoutes(router *gin.Engine, healthAdaptor *adaptor.HealthAdaptor) {
+	// Public endpoint — liveness dan readiness probe
+	router.GET("/healthz", healthAdaptor.Liveness)
+	router.GET("/readyz", healthAdaptor.Readiness)
+}
+
 func registerAuthRoutes(api *gin.RouterGroup, adaptors *adaptor.Adaptor, cfg *utils.Config) {
 	// Auth routes (public)
 	auth := api.Group("/auth")
@@ -76,6 +111,8 @@ func registerDiagnosisRoutes(api *gin.RouterGroup, adaptors *adaptor.Adaptor, cf
 	{
 		diagnosis.GET("/history", adaptors.DiagnosisAdaptor.GetDiagnosisHistory)
 		diagnosis.GET("/:id", adaptors.DiagnosisAdaptor.GetDiagnosisByID)
+		diagnosis.GET("/:id/revisions", adaptors.DiagnosisAdaptor.GetDiagnosisRevisions)
+		diagnosis.GET("/:id/report.pdf", adaptors.DiagnosisAdaptor.GetDiagnosisReport)
 	}
 
 	// Hanya admin/dokter: buat diagnosis
@@ -84,6 +121,14 @@ func registerDiagnosisRoutes(api *gin.RouterGroup, adaptors *adaptor.Adaptor, cf
 	diagnosisAdmin.Use(middleware.RoleRequired("admin", "dokter"))
 	{
 		diagnosisAdmin.POST("", adaptors.DiagnosisAdaptor.CreateDiagnosis)
+		// Simulasi what-if, hasil tidak disimpan
+		diagnosisAdmin.POST("/simulate", adaptors.DiagnosisAdaptor.SimulateDiagnosis)
+		// Koreksi & hapus: pembuat diagnosis atau admin (dicek di usecase)
+		diagnosisAdmin.PUT("/:id", adaptors.DiagnosisAdaptor.UpdateDiagnosis)
+		diagnosisAdmin.DELETE("/:id", adaptors.DiagnosisAdaptor.DeleteDiagnosis)
+		// Alur review: draft -> pending_review -> reviewed/signed
+		diagnosisAdmin.POST("/:id/submit", adaptors.DiagnosisAdaptor.SubmitForReview)
+		diagnosisAdmin.POST("/:id/review", adaptors.DiagnosisAdaptor.ReviewDiagnosis)
 	}
 
 	// Hanya admin/dokter: endpoint admin
@@ -93,6 +138,31 @@ func registerDiagnosisRoutes(api *gin.RouterGroup, adaptors *adaptor.Adaptor, cf
 	{
 		admin.GET("/all", adaptors.DiagnosisAdaptor.GetAllDiagnoses)
 		admin.GET("/patient/:patientId", adaptors.DiagnosisAdaptor.GetPatientDiagnoses)
+		admin.GET("/pending-review", adaptors.DiagnosisAdaptor.GetPendingReview)
+		// Import massal CSV/XLSX, diproses di background
+		admin.POST("/imports", adaptors.ImportAdaptor.StartImport)
+		admin.GET("/imports", adaptors.ImportAdaptor.GetImportJobs)
+		admin.GET("/imports/:id", adaptors.ImportAdaptor.GetImportJob)
+		admin.GET("/imports/:id/rows", adaptors.ImportAdaptor.GetImportJobRows)
+	}
+
+	// Hanya admin: prediksi ulang semua diagnosis dengan model terbaru
+	rescore := api.Group("/admin/diagnosis/rescore")
+	rescore.Use(middleware.AuthRequired(cfg))
+	rescore.Use(middleware.RoleRequired("admin"))
+	{
+		rescore.POST("", adaptors.RescoreAdaptor.StartRescore)
+		rescore.GET("", adaptors.RescoreAdaptor.GetRescoreJobs)
+		rescore.GET("/:id", adaptors.RescoreAdaptor.GetRescoreReport)
+		rescore.GET("/:id/scores", adaptors.RescoreAdaptor.GetRescoreScores)
+	}
+
+	// Hanya admin: perbandingan model utama dengan model shadow (kandidat)
+	shadow := api.Group("/admin/diagnosis/shadow")
+	shadow.Use(middleware.AuthRequired(cfg))
+	shadow.Use(middleware.RoleRequired("admin"))
+	{
+		shadow.GET("/report", adaptors.DiagnosisAdaptor.GetShadowReport)
 	}
 }
 
@@ -108,6 +178,8 @@ func registerPatientRoutes(api *gin.RouterGroup, adaptors *adaptor.Adaptor, cfg
 		patients.GET("", adaptors.PatientAdaptor.GetAllPatients)
 		// GET /api/v1/admin/patients/:id
 		patients.GET("/:id", adaptors.PatientAdaptor.GetPatientByID)
+		// GET /api/v1/admin/patients/:id/trend
+		patients.GET("/:id/trend", adaptors.PatientAdaptor.GetPatientTrend)
 	}
 }
 
@@ -123,3 +195,61 @@ func registerStatsRoutes(api *gin.RouterGroup, adaptors *adaptor.Adaptor, cfg *u
 		adminStats.GET("", adaptors.StatsAdaptor.GetAdminStats)
 	}
 }
+
+func registerMetaRoutes(api *gin.RouterGroup, adaptors *adaptor.Adaptor) {
+	// Public endpoint — daftar nilai kanonik untuk dropdown form diagnosis di front end
+	meta := api.Group("/meta")
+	{
+		meta.GET("/vocabulary", adaptors.MetaAdaptor.GetVocabulary)
+	}
+}
+
+func registerFHIRRoutes(api *gin.RouterGroup, adaptors *adaptor.Adaptor, cfg *utils.Config) {
+	// FHIR R4 (read-only) untuk integrasi rumah sakit.
+	// Pasien hanya bisa membaca datanya sendiri, admin/dokter semua pasien (dicek di usecase).
+	fhir := api.Group("/fhir/r4")
+	fhir.Use(middleware.AuthRequired(cfg))
+	{
+		fhir.GET("/Patient/:id", adaptors.FHIRAdaptor.GetPatient)
+		fhir.GET("/Patient/:id/$everything", adaptors.FHIRAdaptor.PatientEverything)
+		fhir.GET("/Observation", adaptors.FHIRAdaptor.SearchObservations)
+		fhir.GET("/Observation/:id", adaptors.FHIRAdaptor.GetObservation)
+		fhir.GET("/RiskAssessment", adaptors.FHIRAdaptor.SearchRiskAssessments)
+		fhir.GET("/RiskAssessment/:id", adaptors.FHIRAdaptor.GetRiskAssessment)
+	}
+}
+
+func registerMonitoringRoutes(api *gin.RouterGroup, adaptors *adaptor.Adaptor, cfg *utils.Config) {
+	// Hanya admin: pemantauan drift input diagnosis terhadap data training model
+	monitoring := api.Group("/admin/monitoring")
+	monitoring.Use(middleware.AuthRequired(cfg))
+	monitoring.Use(middleware.RoleRequired("admin"))
+	{
+		monitoring.GET("/drift", adaptors.DriftAdaptor.GetCurrentDrift)
+		monitoring.GET("/drift/checks", adaptors.DriftAdaptor.GetDriftChecks)
+		monitoring.POST("/drift/checks", adaptors.DriftAdaptor.RunDriftCheck)
+	}
+}
+
+func registerNotificationRoutes(api *gin.RouterGroup, adaptors *adaptor.Adaptor, cfg *utils.Config) {
+	// Admin/dokter: alert diagnosis risiko tinggi milik user yang login
+	notifications := api.Group("/notifications")
+	notifications.Use(middleware.AuthRequired(cfg))
+	notifications.Use(middleware.RoleRequired("admin", "dokter"))
+	{
+		// GET /api/v1/notifications?unacknowledged=true
+		notifications.GET("", adaptors.AlertAdaptor.GetNotifications)
+		notifications.POST("/:id/acknowledge", adaptors.AlertAdaptor.AcknowledgeNotification)
+	}
+
+	// Hanya admin: aturan kapan diagnosis memicu alert
+	alertRules := api.Group("/admin/alert-rules")
+	alertRules.Use(middleware.AuthRequired(cfg))
+	alertRules.Use(middleware.RoleRequired("admin"))
+	{
+		alertRules.GET("", adaptors.AlertAdaptor.GetAlertRules)
+		alertRules.POST("", adaptors.AlertAdaptor.CreateAlertRule)
+		alertRules.PUT("/:id", adaptors.AlertAdaptor.UpdateAlertRule)
+		alertRules.DELETE("/:id", adaptors.AlertAdaptor.DeleteAlertRule)
+	}
+}
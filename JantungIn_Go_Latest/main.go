@@ -1,106 +1,10 @@
 package main
 
 import (
-	"context"
-	"flag"
 	"jantungin-api-server/cmd"
-	"jantungin-api-server/internal/data"
-	"jantungin-api-server/internal/data/entity"
-	"jantungin-api-server/internal/wire"
-	"jantungin-api-server/pkg/database"
-	"jantungin-api-server/pkg/utils"
 	"os"
-	"os/signal"
-	"syscall"
-
-	"go.uber.org/zap"
 )
 
 func main() {
-	seedMode := flag.Bool("seed", false, "Jalankan seeder data dokter dummy lalu keluar")
-	flag.Parse()
-
-	cfg, err := utils.LoadConfig()
-	if err != nil {
-		panic("Failed to load config: " + err.Error())
-	}
-
-	if err := utils.InitLogger(cfg.App.Env); err != nil {
-		panic("Failed to initialize logger: " + err.Error())
-	}
-	defer utils.Sync()
-
-	utils.Info("Starting application",
-		zap.String("name", cfg.App.Name),
-		zap.String("env", cfg.App.Env),
-		zap.String("port", cfg.App.Port),
-	)
-
-	dbManager, err := database.NewManager(cfg)
-	if err != nil {
-		utils.Fatal("Failed to initialize databases", zap.Error(err))
-	}
-	defer dbManager.Close()
-
-	db := dbManager.Postgres.GetDB()
-	err = db.AutoMigrate(
-		&entity.User{},
-		&entity.Diagnosis{},
-		&entity.RequestLog{},
-		&entity.UserDevice{},
-	)
-	if err != nil {
-		utils.Fatal("Auto Migration failed", zap.Error(err))
-	}
-	utils.Info("Auto Migration successful")
-
-	// Mode seed: jalankan seeder lalu keluar, tidak start server
-	if *seedMode {
-		utils.Info("Running doctor seeder...")
-		if err := data.SeedDoctors(db, cfg); err != nil {
-			utils.Fatal("Seeder failed", zap.Error(err))
-		}
-		utils.Info("Doctor seeder completed successfully")
-
-		utils.Info("Running patient seeder...")
-		if err := data.SeedPatients(db, cfg); err != nil {
-			utils.Fatal("Patient seeder failed", zap.Error(err))
-		}
-		utils.Info("Patient seeder completed successfully")
-
-		utils.Info("Running diagnosis seeder...")
-		if err := data.SeedDiagnoses(db, cfg); err != nil {
-			utils.Fatal("Diagnosis seeder failed", zap.Error(err))
-		}
-		utils.Info("Diagnosis seeder completed successfully")
-
-		utils.Info("Running user devices seeder...")
-		if err := data.SeedUserDevices(db, cfg); err != nil {
-			utils.Fatal("User devices seeder failed", zap.Error(err))
-		}
-		utils.Info("User devices seeder completed successfully")
-
-		utils.Info("All seeders completed successfully")
-		os.Exit(0)
-	}
-
-	ctx := context.Background()
-	router := wire.Wiring(cfg, db)
-
-	// Create and run server
-	server := cmd.NewServer(router, cfg)
-
-	go func() {
-		if err := server.Run(); err != nil {
-			utils.Fatal("Failed to start server", zap.Error(err))
-		}
-	}()
-
-	quit := make(chan os.Signal, 1)
-	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
-	<-quit
-
-	utils.Info("Shutting down application")
-	server.Shutdown(ctx)
-	utils.Info("Application stopped gracefully")
+	os.Exit(cmd.Execute(os.Args[1:]))
 }
@@ -0,0 +1,80 @@
+package background
+
+import (
+	"context"
+	"fmt"
+	"jantungin-api-server/pkg/utils"
+	"runtime/debug"
+	"sync"
+
+	"go.uber.org/zap"
+)
+
+// Runner menjalankan pekerjaan di goroutine terpisah dari request HTTP (import,
+// rescore, dsb.) dan menunggu semuanya selesai saat aplikasi shutdown.
+// Context yang diberikan ke setiap task dibatalkan ketika Shutdown dipanggil.
+type Runner struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+
+	mu     sync.Mutex
+	closed bool
+}
+
+func NewRunner() *Runner {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Runner{ctx: ctx, cancel: cancel}
+}
+
+// Go menjalankan fn di background. Panic di dalam fn ditangkap dan dicatat.
+// Mengembalikan error jika runner sudah di-shutdown.
+func (r *Runner) Go(name string, fn func(ctx context.Context)) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.closed {
+		return fmt.Errorf("background runner is shutting down, task %q rejected", name)
+	}
+
+	r.wg.Add(1)
+	go func() {
+		defer r.wg.Done()
+		defer func() {
+			if rec := recover(); rec != nil {
+				utils.Error("Background task panicked",
+					zap.String("task", name),
+					zap.Any("panic", rec),
+					zap.ByteString("stack", debug.Stack()),
+				)
+			}
+		}()
+
+		fn(r.ctx)
+	}()
+
+	return nil
+}
+
+// Shutdown menolak task baru, membatalkan context task yang sedang berjalan,
+// lalu menunggu sampai semuanya selesai atau ctx habis.
+func (r *Runner) Shutdown(ctx context.Context) error {
+	r.mu.Lock()
+	r.closed = true
+	r.mu.Unlock()
+
+	r.cancel()
+
+	done := make(chan struct{})
+	go func() {
+		r.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("background tasks did not finish in time: %w", ctx.Err())
+	}
+}
@@ -39,7 +39,7 @@ func (m *Manager) Close() error {
 }
 
 func (m *Manager) HealthCheck(ctx context.Context) error {
-	if err := m.Postgres.HealthCheck(); err != nil {
+	if err := m.Postgres.HealthCheck(ctx); err != nil {
 		return fmt.Errorf("PostgreSQL health check failed: %w", err)
 	}
 	// if err := m.Redis.HealthCheck(ctx); err != nil {
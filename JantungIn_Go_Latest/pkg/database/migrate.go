@@ -0,0 +1,326 @@
+package database
+
+import (
+	"cmp"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io/fs"
+	"regexp"
+	"slices"
+	"strconv"
+
+	"jantungin-api-server/pkg/utils"
+
+	"go.uber.org/zap"
+)
+
+// NilVersion menandakan belum ada migrasi yang dijalankan (sama dengan golang-migrate)
+const NilVersion int64 = -1
+
+// migrationLockKey adalah key pg_advisory_lock agar hanya satu instance yang
+// menjalankan migrasi saat beberapa instance boot bersamaan
+const migrationLockKey int64 = 7_315_302_452_110_045
+
+// File lain di direktori migrasi (mis. migrations.md, seed_devices.sql) diabaikan
+var migrationFilePattern = regexp.MustCompile(`^(\d+)_(.+)\.(up|down)\.sql$`)
+
+// ErrDirtySchema dikembalikan jika migrasi sebelumnya terhenti di tengah jalan.
+// Perbaiki skema secara manual lalu jalankan `migrate force <versi>`.
+var ErrDirtySchema = errors.New("database schema is dirty")
+
+type Migration struct {
+	Version int64
+	Name    string
+
+	upFile   string
+	downFile string
+}
+
+type MigrationStatus struct {
+	Version int64 // NilVersion jika belum ada migrasi
+	Dirty   bool
+	Applied []Migration
+	Pending []Migration
+}
+
+// Migrator menjalankan migrasi SQL bernomor (000001_nama.up.sql / .down.sql) dari fs.FS.
+// Versi disimpan di tabel schema_migrations (version, dirty) dengan format yang sama
+// seperti CLI golang-migrate, sehingga database yang sebelumnya dimigrasikan lewat
+// `make db-up` bisa langsung dipakai. Setiap file dijalankan dalam satu transaksi.
+type Migrator struct {
+	db         *sql.DB
+	source     fs.FS
+	migrations []Migration
+}
+
+func NewMigrator(db *sql.DB, source fs.FS) (*Migrator, error) {
+	entries, err := fs.ReadDir(source, ".")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read migrations: %w", err)
+	}
+
+	byVersion := make(map[int64]*Migration)
+	for _, entry := range entries {
+		match := migrationFilePattern.FindStringSubmatch(entry.Name())
+		if entry.IsDir() || match == nil {
+			continue
+		}
+
+		version, err := strconv.ParseInt(match[1], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid migration version %q: %w", entry.Name(), err)
+		}
+		m, ok := byVersion[version]
+		if !ok {
+			m = &Migration{Version: version, Name: match[2]}
+			byVersion[version] = m
+		}
+		if m.Name != match[2] {
+			return nil, fmt.Errorf("migration version %d has different names: %q and %q", version, m.Name, match[2])
+		}
+		if match[3] == "up" {
+			m.upFile = entry.Name()
+		} else {
+			m.downFile = entry.Name()
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, m := range byVersion {
+		if m.upFile == "" {
+			return nil, fmt.Errorf("migration %d_%s has no up file", m.Version, m.Name)
+		}
+		migrations = append(migrations, *m)
+	}
+	slices.SortFunc(migrations, func(a, b Migration) int {
+		return cmp.Compare(a.Version, b.Version)
+	})
+
+	return &Migrator{db: db, source: source, migrations: migrations}, nil
+}
+
+// Up menjalankan semua migrasi yang belum diterapkan. Mengembalikan jumlah migrasi yang dijalankan.
+func (m *Migrator) Up(ctx context.Context) (int, error) {
+	applied := 0
+	err := m.withLock(ctx, func(conn *sql.Conn) error {
+		version, err := m.currentVersion(ctx, conn)
+		if err != nil {
+			return err
+		}
+
+		for _, migration := range m.migrations {
+			if migration.Version <= version {
+				continue
+			}
+			if err := m.run(ctx, conn, migration.upFile, version, migration.Version); err != nil {
+				return err
+			}
+			version = migration.Version
+			applied++
+		}
+		return nil
+	})
+	return applied, err
+}
+
+// Down membatalkan steps migrasi terakhir. Mengembalikan jumlah migrasi yang dibatalkan.
+func (m *Migrator) Down(ctx context.Context, steps int) (int, error) {
+	reverted := 0
+	err := m.withLock(ctx, func(conn *sql.Conn) error {
+		version, err := m.currentVersion(ctx, conn)
+		if err != nil {
+			return err
+		}
+
+		for reverted < steps && version != NilVersion {
+			i := slices.IndexFunc(m.migrations, func(mg Migration) bool { return mg.Version == version })
+			if i < 0 {
+				return fmt.Errorf("migration %d not found in embedded migrations", version)
+			}
+			migration := m.migrations[i]
+			if migration.downFile == "" {
+				return fmt.Errorf("migration %d_%s has no down file", migration.Version, migration.Name)
+			}
+
+			previous := NilVersion
+			if i > 0 {
+				previous = m.migrations[i-1].Version
+			}
+			if err := m.run(ctx, conn, migration.downFile, version, previous); err != nil {
+				return err
+			}
+			version = previous
+			reverted++
+		}
+		return nil
+	})
+	return reverted, err
+}
+
+// Force menetapkan versi skema tanpa menjalankan migrasi dan menghapus status dirty.
+// Dipakai setelah skema diperbaiki manual atau untuk database lama yang dibuat AutoMigrate.
+func (m *Migrator) Force(ctx context.Context, version int64) error {
+	if version != NilVersion && !slices.ContainsFunc(m.migrations, func(mg Migration) bool { return mg.Version == version }) {
+		return fmt.Errorf("migration %d not found in embedded migrations", version)
+	}
+	return m.withLock(ctx, func(conn *sql.Conn) error {
+		return markVersion(ctx, conn, version, false)
+	})
+}
+
+// Status hanya membaca database: tabel schema_migrations yang belum ada dilaporkan
+// sebagai NilVersion tanpa dibuat, sehingga aman dipanggil readiness probe berulang kali
+func (m *Migrator) Status(ctx context.Context) (*MigrationStatus, error) {
+	version, dirty := NilVersion, false
+	var table sql.NullString
+	if err := m.db.QueryRowContext(ctx, `SELECT to_regclass('schema_migrations')::text`).Scan(&table); err != nil {
+		return nil, err
+	}
+	if table.Valid {
+		var err error
+		version, dirty, err = readVersion(ctx, m.db)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	status := &MigrationStatus{Version: version, Dirty: dirty}
+	for _, migration := range m.migrations {
+		if migration.Version <= version {
+			status.Applied = append(status.Applied, migration)
+		} else {
+			status.Pending = append(status.Pending, migration)
+		}
+	}
+	return status, nil
+}
+
+// Check menolak skema yang dirty atau masih punya migrasi yang belum dijalankan,
+// untuk instance yang start tanpa menjalankan migrasi otomatis
+func (m *Migrator) Check(ctx context.Context) error {
+	status, err := m.Status(ctx)
+	if err != nil {
+		return err
+	}
+	if status.Dirty {
+		return fmt.Errorf("%w at version %d, fix it manually then run `migrate force <version>`", ErrDirtySchema, status.Version)
+	}
+	if len(status.Pending) > 0 {
+		return fmt.Errorf("database schema is at version %d, %d migration(s) pending, run `migrate up`", status.Version, len(status.Pending))
+	}
+	return nil
+}
+
+// currentVersion membaca versi skema dan menolak melanjutkan jika skema dirty
+func (m *Migrator) currentVersion(ctx context.Context, conn *sql.Conn) (int64, error) {
+	version, dirty, err := readVersion(ctx, conn)
+	if err != nil {
+		return 0, err
+	}
+	if dirty {
+		return 0, fmt.Errorf("%w at version %d, fix it manually then run `migrate force <version>`", ErrDirtySchema, version)
+	}
+	return version, nil
+}
+
+// run menandai skema dirty di versi tujuan, menjalankan file dalam transaksi, lalu menandai
+// bersih. Jika file gagal, transaksi di-rollback dan versi dikembalikan ke semula.
+func (m *Migrator) run(ctx context.Context, conn *sql.Conn, file string, from int64, to int64) error {
+	body, err := fs.ReadFile(m.source, file)
+	if err != nil {
+		return err
+	}
+
+	utils.Info("Running migration", zap.String("file", file))
+	if err := markVersion(ctx, conn, to, true); err != nil {
+		return err
+	}
+
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, string(body)); err != nil {
+		_ = tx.Rollback()
+		if restoreErr := markVersion(context.WithoutCancel(ctx), conn, from, false); restoreErr != nil {
+			utils.Error("Failed to restore schema version after failed migration", zap.Error(restoreErr))
+		}
+		return fmt.Errorf("migration %s failed: %w", file, err)
+	}
+	if err := setVersion(ctx, tx, to, false); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+// withLock menjalankan fn dengan advisory lock pada satu koneksi; instance lain menunggu
+// sampai migrasi selesai lalu mendapati skema sudah terbaru
+func (m *Migrator) withLock(ctx context.Context, fn func(conn *sql.Conn) error) error {
+	conn, err := m.db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	if _, err := conn.ExecContext(ctx, "SELECT pg_advisory_lock($1)", migrationLockKey); err != nil {
+		return fmt.Errorf("failed to acquire migration lock: %w", err)
+	}
+	defer func() {
+		if _, err := conn.ExecContext(context.WithoutCancel(ctx), "SELECT pg_advisory_unlock($1)", migrationLockKey); err != nil {
+			utils.Warn("Failed to release migration lock", zap.Error(err))
+		}
+	}()
+
+	if err := ensureMigrationTable(ctx, conn); err != nil {
+		return err
+	}
+	return fn(conn)
+}
+
+type execQuerier interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+func ensureMigrationTable(ctx context.Context, db execQuerier) error {
+	_, err := db.ExecContext(ctx, `CREATE TABLE IF NOT EXISTS schema_migrations (version BIGINT NOT NULL PRIMARY KEY, dirty BOOLEAN NOT NULL)`)
+	return err
+}
+
+func readVersion(ctx context.Context, db execQuerier) (int64, bool, error) {
+	var version int64
+	var dirty bool
+	err := db.QueryRowContext(ctx, `SELECT version, dirty FROM schema_migrations LIMIT 1`).Scan(&version, &dirty)
+	if errors.Is(err, sql.ErrNoRows) {
+		return NilVersion, false, nil
+	}
+	return version, dirty, err
+}
+
+// markVersion menyimpan versi dalam transaksi tersendiri
+func markVersion(ctx context.Context, conn *sql.Conn, version int64, dirty bool) error {
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	if err := setVersion(ctx, tx, version, dirty); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+// setVersion menyimpan satu baris versi seperti golang-migrate (TRUNCATE lalu INSERT)
+func setVersion(ctx context.Context, db execQuerier, version int64, dirty bool) error {
+	if _, err := db.ExecContext(ctx, `TRUNCATE schema_migrations`); err != nil {
+		return err
+	}
+	if version == NilVersion && !dirty {
+		return nil
+	}
+	_, err := db.ExecContext(ctx, `INSERT INTO schema_migrations (version, dirty) VALUES ($1, $2)`, version, dirty)
+	return err
+}
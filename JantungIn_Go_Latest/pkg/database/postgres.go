@@ -1,6 +1,7 @@
 package database
 
 import (
+	"context"
 	"fmt"
 	"jantungin-api-server/pkg/utils"
 	"time"
@@ -75,12 +76,12 @@ func (p *PostgresDB) GetDB() *gorm.DB {
 	return p.DB
 }
 
-func (p *PostgresDB) HealthCheck() error {
+func (p *PostgresDB) HealthCheck(ctx context.Context) error {
 	sqlDB, err := p.DB.DB()
 	if err != nil {
 		return err
 	}
-	return sqlDB.Ping()
+	return sqlDB.PingContext(ctx)
 }
 
 func (p *PostgresDB) GetStats() map[string]any {
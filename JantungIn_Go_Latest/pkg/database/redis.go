@@ -0,0 +1,68 @@
+package database
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net"
+	"strings"
+
+	"jantungin-api-server/pkg/utils"
+)
+
+// PingRedis mengirim AUTH (jika ada password), SELECT, dan PING lewat protokol RESP.
+// Dipakai readiness check selama aplikasi belum memakai client Redis.
+func PingRedis(ctx context.Context, cfg *utils.RedisConfig) error {
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", net.JoinHostPort(cfg.Host, cfg.Port))
+	if err != nil {
+		return fmt.Errorf("failed to connect to Redis: %w", err)
+	}
+	defer conn.Close()
+
+	if deadline, ok := ctx.Deadline(); ok {
+		if err := conn.SetDeadline(deadline); err != nil {
+			return err
+		}
+	}
+
+	reader := bufio.NewReader(conn)
+	command := func(args ...string) (string, error) {
+		var b strings.Builder
+		fmt.Fprintf(&b, "*%d\r\n", len(args))
+		for _, arg := range args {
+			fmt.Fprintf(&b, "$%d\r\n%s\r\n", len(arg), arg)
+		}
+		if _, err := conn.Write([]byte(b.String())); err != nil {
+			return "", err
+		}
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			return "", err
+		}
+		line = strings.TrimRight(line, "\r\n")
+		if strings.HasPrefix(line, "-") {
+			return "", fmt.Errorf("redis: %s", strings.TrimPrefix(line, "-"))
+		}
+		return line, nil
+	}
+
+	if cfg.Password != "" {
+		if _, err := command("AUTH", cfg.Password); err != nil {
+			return err
+		}
+	}
+	if cfg.DB != 0 {
+		if _, err := command("SELECT", fmt.Sprint(cfg.DB)); err != nil {
+			return err
+		}
+	}
+	reply, err := command("PING")
+	if err != nil {
+		return err
+	}
+	if reply != "+PONG" {
+		return fmt.Errorf("unexpected Redis PING reply %q", reply)
+	}
+	return nil
+}
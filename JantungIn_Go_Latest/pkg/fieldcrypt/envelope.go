@@ -0,0 +1,357 @@
+package fieldcrypt
+
+import (
+	"context"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"jantungin-api-server/pkg/utils"
+)
+
+// devMasterKey hanya untuk development tanpa ENCRYPTION_MASTER_KEY(_FILE);
+// utils.Config.Validate menolak production tanpa master key
+var devMasterKey = []byte("jantungin-dev-master-key-0000000")
+
+// WrappedKey adalah data key yang disimpan terbungkus (wrapped) oleh master key
+type WrappedKey struct {
+	ID          string
+	Version     int
+	MasterKeyID string
+	Ciphertext  []byte
+	Active      bool
+}
+
+// KeyStore menyimpan data key terbungkus. Paling banyak satu key berstatus aktif.
+type KeyStore interface {
+	FindKeys(ctx context.Context) ([]WrappedKey, error)
+	FindKey(ctx context.Context, id string) (*WrappedKey, error)
+	// CreateKey tidak mengubah apa pun jika ID sudah ada
+	CreateKey(ctx context.Context, key WrappedKey) error
+	ActivateKey(ctx context.Context, id string) error
+	RewrapKey(ctx context.Context, id string, masterKeyID string, ciphertext []byte) error
+}
+
+// MasterKey membungkus data key dengan AES-256-GCM; ID data key dipakai sebagai
+// additional data sehingga wrapped key tidak bisa ditukar antar ID
+type MasterKey struct {
+	id   string
+	aead cipher.AEAD
+}
+
+func NewMasterKey(id string, key []byte) (*MasterKey, error) {
+	aead, err := newAEAD(id, key)
+	if err != nil {
+		return nil, err
+	}
+	return &MasterKey{id: id, aead: aead}, nil
+}
+
+func (m *MasterKey) wrap(dataKeyID string, dataKey []byte) ([]byte, error) {
+	nonce := make([]byte, m.aead.NonceSize(), m.aead.NonceSize()+len(dataKey)+m.aead.Overhead())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	return m.aead.Seal(nonce, nonce, dataKey, []byte(dataKeyID)), nil
+}
+
+func (m *MasterKey) unwrap(dataKeyID string, wrapped []byte) ([]byte, error) {
+	if len(wrapped) < m.aead.NonceSize() {
+		return nil, errors.New("fieldcrypt: wrapped key too short")
+	}
+	nonce, ciphertext := wrapped[:m.aead.NonceSize()], wrapped[m.aead.NonceSize():]
+	key, err := m.aead.Open(nil, nonce, ciphertext, []byte(dataKeyID))
+	if err != nil {
+		return nil, fmt.Errorf("fieldcrypt: unwrap key %q with master key %q: %w", dataKeyID, m.id, err)
+	}
+	return key, nil
+}
+
+// Envelope mengelola data key di KeyStore: membuat, merotasi, membungkus ulang dengan
+// master key terbaru, dan membangun Keyring untuk serializer.
+type Envelope struct {
+	store    KeyStore
+	master   *MasterKey
+	previous map[string]*MasterKey
+	indexKey []byte
+
+	// ENCRYPTION_KEY lama (sebelum envelope encryption), diimpor ke KeyStore saat Open
+	legacy         map[string][]byte
+	legacyActiveID string
+}
+
+func NewEnvelope(store KeyStore, cfg utils.EncryptionConfig) (*Envelope, error) {
+	masterKey, err := loadMasterKey(cfg)
+	if err != nil {
+		return nil, err
+	}
+	master, err := NewMasterKey(cfg.MasterKeyID, masterKey)
+	if err != nil {
+		return nil, err
+	}
+
+	e := &Envelope{
+		store:    store,
+		master:   master,
+		previous: make(map[string]*MasterKey),
+		indexKey: []byte(cfg.BlindIndexKey),
+		legacy:   make(map[string][]byte),
+	}
+
+	for _, entry := range cfg.PreviousMasterKeys {
+		id, encoded, err := splitKeyEntry(entry, "ENCRYPTION_PREVIOUS_MASTER_KEYS")
+		if err != nil {
+			return nil, err
+		}
+		if id == "" {
+			continue
+		}
+		key, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			return nil, fmt.Errorf("fieldcrypt: previous master key %q is not valid base64: %w", id, err)
+		}
+		if e.previous[id], err = NewMasterKey(id, key); err != nil {
+			return nil, err
+		}
+	}
+
+	if cfg.LegacyKey != "" {
+		e.legacy[cfg.LegacyKeyID] = []byte(cfg.LegacyKey)
+		e.legacyActiveID = cfg.LegacyKeyID
+	}
+	for _, entry := range cfg.LegacyPreviousKeys {
+		id, key, err := splitKeyEntry(entry, "ENCRYPTION_PREVIOUS_KEYS")
+		if err != nil {
+			return nil, err
+		}
+		if id != "" {
+			e.legacy[id] = []byte(key)
+		}
+	}
+
+	return e, nil
+}
+
+func loadMasterKey(cfg utils.EncryptionConfig) ([]byte, error) {
+	encoded := cfg.MasterKey
+	if cfg.MasterKeyFile != "" {
+		content, err := os.ReadFile(cfg.MasterKeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("fieldcrypt: read master key file: %w", err)
+		}
+		encoded = string(content)
+	}
+	if encoded == "" {
+		utils.Warn("ENCRYPTION_MASTER_KEY not configured, using insecure development master key")
+		return devMasterKey, nil
+	}
+
+	key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(encoded))
+	if err != nil {
+		return nil, fmt.Errorf("fieldcrypt: master key is not valid base64: %w", err)
+	}
+	return key, nil
+}
+
+func splitKeyEntry(entry string, name string) (string, string, error) {
+	entry = strings.TrimSpace(entry)
+	if entry == "" {
+		return "", "", nil
+	}
+	id, key, ok := strings.Cut(entry, ":")
+	if !ok {
+		return "", "", fmt.Errorf("fieldcrypt: %s entry must be keyID:key", name)
+	}
+	return id, key, nil
+}
+
+// Open menyiapkan KeyStore (impor kunci lama, buat data key pertama jika belum ada)
+// lalu membangun Keyring dari semua data key. Kunci yang dibuat instance lain setelah
+// Open dimuat otomatis saat pertama kali ditemui.
+func (e *Envelope) Open(ctx context.Context) (*Keyring, error) {
+	keys, err := e.store.FindKeys(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	existing := make(map[string]bool, len(keys))
+	for _, k := range keys {
+		existing[k.ID] = true
+	}
+	changed := false
+	for id, key := range e.legacy {
+		if existing[id] {
+			continue
+		}
+		if err := e.createKey(ctx, id, key); err != nil {
+			return nil, err
+		}
+		changed = true
+	}
+
+	if activeKey(keys) == nil {
+		activeID := e.legacyActiveID
+		if activeID == "" {
+			if activeID, err = e.generateKey(ctx, keys); err != nil {
+				return nil, err
+			}
+		}
+		if err := e.store.ActivateKey(ctx, activeID); err != nil {
+			return nil, err
+		}
+		changed = true
+	}
+
+	if changed {
+		if keys, err = e.store.FindKeys(ctx); err != nil {
+			return nil, err
+		}
+	}
+	active := activeKey(keys)
+	if active == nil {
+		return nil, errors.New("fieldcrypt: no active data key")
+	}
+
+	plain := make(map[string][]byte, len(keys))
+	for _, k := range keys {
+		if plain[k.ID], err = e.unwrap(k); err != nil {
+			return nil, err
+		}
+	}
+
+	keyring, err := NewKeyring(active.ID, plain, e.indexKey)
+	if err != nil {
+		return nil, err
+	}
+	keyring.SetResolver(e.resolve)
+	return keyring, nil
+}
+
+// Rotate membuat data key versi berikutnya dan menjadikannya aktif. Instance yang sedang
+// berjalan tetap mengenkripsi dengan kunci lama sampai di-restart; data lama dienkripsi
+// ulang oleh job encryption maintenance.
+func (e *Envelope) Rotate(ctx context.Context) (string, error) {
+	keys, err := e.store.FindKeys(ctx)
+	if err != nil {
+		return "", err
+	}
+	id, err := e.generateKey(ctx, keys)
+	if err != nil {
+		return "", err
+	}
+	if err := e.store.ActivateKey(ctx, id); err != nil {
+		return "", err
+	}
+	return id, nil
+}
+
+// Rewrap membungkus ulang data key yang masih memakai master key lama dengan master key
+// aktif. Mengembalikan jumlah data key yang dibungkus ulang.
+func (e *Envelope) Rewrap(ctx context.Context) (int, error) {
+	keys, err := e.store.FindKeys(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	rewrapped := 0
+	for _, k := range keys {
+		if k.MasterKeyID == e.master.id {
+			continue
+		}
+		plain, err := e.unwrap(k)
+		if err != nil {
+			return rewrapped, err
+		}
+		wrapped, err := e.master.wrap(k.ID, plain)
+		if err != nil {
+			return rewrapped, err
+		}
+		if err := e.store.RewrapKey(ctx, k.ID, e.master.id, wrapped); err != nil {
+			return rewrapped, err
+		}
+		rewrapped++
+	}
+	return rewrapped, nil
+}
+
+func (e *Envelope) generateKey(ctx context.Context, keys []WrappedKey) (string, error) {
+	version := 0
+	for _, k := range keys {
+		version = max(version, k.Version)
+	}
+	id := "k" + strconv.Itoa(version+1)
+
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		return "", err
+	}
+	if err := e.createKey(ctx, id, key); err != nil {
+		return "", err
+	}
+	return id, nil
+}
+
+func (e *Envelope) createKey(ctx context.Context, id string, key []byte) error {
+	if !ValidKeyID(id) {
+		return fmt.Errorf("fieldcrypt: invalid key ID %q", id)
+	}
+	if len(key) != 32 {
+		return fmt.Errorf("fieldcrypt: key %q must be 32 bytes, got %d", id, len(key))
+	}
+
+	wrapped, err := e.master.wrap(id, key)
+	if err != nil {
+		return err
+	}
+	return e.store.CreateKey(ctx, WrappedKey{
+		ID:          id,
+		Version:     keyVersion(id),
+		MasterKeyID: e.master.id,
+		Ciphertext:  wrapped,
+	})
+}
+
+func (e *Envelope) unwrap(k WrappedKey) ([]byte, error) {
+	master := e.master
+	if k.MasterKeyID != e.master.id {
+		var ok bool
+		if master, ok = e.previous[k.MasterKeyID]; !ok {
+			return nil, fmt.Errorf("fieldcrypt: data key %q is wrapped by master key %q, add it to ENCRYPTION_PREVIOUS_MASTER_KEYS", k.ID, k.MasterKeyID)
+		}
+	}
+	return master.unwrap(k.ID, k.Ciphertext)
+}
+
+func (e *Envelope) resolve(keyID string) ([]byte, error) {
+	k, err := e.store.FindKey(context.Background(), keyID)
+	if err != nil {
+		return nil, err
+	}
+	if k == nil {
+		return nil, fmt.Errorf("data key %q not found", keyID)
+	}
+	return e.unwrap(*k)
+}
+
+func activeKey(keys []WrappedKey) *WrappedKey {
+	for i := range keys {
+		if keys[i].Active {
+			return &keys[i]
+		}
+	}
+	return nil
+}
+
+// keyVersion mengambil nomor versi dari ID "k<n>"; ID lain (kunci lama) versi 0
+func keyVersion(id string) int {
+	n, err := strconv.Atoi(strings.TrimPrefix(id, "k"))
+	if err != nil || !strings.HasPrefix(id, "k") {
+		return 0
+	}
+	return n
+}
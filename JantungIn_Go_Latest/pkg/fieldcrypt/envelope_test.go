@@ -0,0 +1,241 @@
+package fieldcrypt
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"strings"
+	"testing"
+
+	"jantungin-api-server/pkg/utils"
+)
+
+// memoryKeyStore adalah KeyStore di memori untuk test
+type memoryKeyStore struct {
+	keys []WrappedKey
+}
+
+func (s *memoryKeyStore) FindKeys(ctx context.Context) ([]WrappedKey, error) {
+	return append([]WrappedKey(nil), s.keys...), nil
+}
+
+func (s *memoryKeyStore) FindKey(ctx context.Context, id string) (*WrappedKey, error) {
+	for _, k := range s.keys {
+		if k.ID == id {
+			return &k, nil
+		}
+	}
+	return nil, nil
+}
+
+func (s *memoryKeyStore) CreateKey(ctx context.Context, key WrappedKey) error {
+	for _, k := range s.keys {
+		if k.ID == key.ID {
+			return nil
+		}
+	}
+	s.keys = append(s.keys, key)
+	return nil
+}
+
+func (s *memoryKeyStore) ActivateKey(ctx context.Context, id string) error {
+	for i := range s.keys {
+		s.keys[i].Active = s.keys[i].ID == id
+	}
+	return nil
+}
+
+func (s *memoryKeyStore) RewrapKey(ctx context.Context, id string, masterKeyID string, ciphertext []byte) error {
+	for i := range s.keys {
+		if s.keys[i].ID == id {
+			s.keys[i].MasterKeyID = masterKeyID
+			s.keys[i].Ciphertext = ciphertext
+		}
+	}
+	return nil
+}
+
+func masterKey(b byte) string {
+	return base64.StdEncoding.EncodeToString(bytes.Repeat([]byte{b}, 32))
+}
+
+func testConfig() utils.EncryptionConfig {
+	return utils.EncryptionConfig{
+		MasterKey:     masterKey('a'),
+		MasterKeyID:   "m1",
+		BlindIndexKey: strings.Repeat("i", 32),
+	}
+}
+
+func openEnvelope(t *testing.T, store KeyStore, cfg utils.EncryptionConfig) (*Envelope, *Keyring) {
+	t.Helper()
+	envelope, err := NewEnvelope(store, cfg)
+	if err != nil {
+		t.Fatalf("NewEnvelope: %v", err)
+	}
+	keyring, err := envelope.Open(context.Background())
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	return envelope, keyring
+}
+
+func TestMasterKeyWrapUnwrap(t *testing.T) {
+	master, err := NewMasterKey("m1", bytes.Repeat([]byte("a"), 32))
+	if err != nil {
+		t.Fatal(err)
+	}
+	dataKey := bytes.Repeat([]byte("d"), 32)
+
+	wrapped, err := master.wrap("k1", dataKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Contains(wrapped, dataKey) {
+		t.Error("wrapped key berisi data key plaintext")
+	}
+	got, err := master.unwrap("k1", wrapped)
+	if err != nil || !bytes.Equal(got, dataKey) {
+		t.Fatalf("unwrap = %x, %v", got, err)
+	}
+
+	if _, err := master.unwrap("k2", wrapped); err == nil {
+		t.Error("wrapped key tidak boleh bisa dibuka dengan ID data key lain")
+	}
+	other, _ := NewMasterKey("m2", bytes.Repeat([]byte("b"), 32))
+	if _, err := other.unwrap("k1", wrapped); err == nil {
+		t.Error("wrapped key tidak boleh bisa dibuka dengan master key lain")
+	}
+	wrapped[len(wrapped)-1] ^= 0x01
+	if _, err := master.unwrap("k1", wrapped); err == nil {
+		t.Error("wrapped key yang diubah harus gagal dibuka")
+	}
+}
+
+func TestEnvelopeOpenCreatesFirstKey(t *testing.T) {
+	store := &memoryKeyStore{}
+	_, keyring := openEnvelope(t, store, testConfig())
+
+	if len(store.keys) != 1 || !store.keys[0].Active || store.keys[0].ID != "k1" {
+		t.Fatalf("keys setelah Open = %+v, want satu key aktif k1", store.keys)
+	}
+	if store.keys[0].MasterKeyID != "m1" {
+		t.Errorf("MasterKeyID = %q, want m1", store.keys[0].MasterKeyID)
+	}
+	if keyring.ActiveKeyID() != "k1" {
+		t.Errorf("ActiveKeyID = %q, want k1", keyring.ActiveKeyID())
+	}
+
+	// Open kedua memakai key yang sama, tidak membuat key baru
+	_, again := openEnvelope(t, store, testConfig())
+	value, _ := keyring.Encrypt("x")
+	if got, err := again.Decrypt(value); err != nil || got != "x" {
+		t.Errorf("Decrypt setelah Open ulang = %q, %v", got, err)
+	}
+	if len(store.keys) != 1 {
+		t.Errorf("Open ulang membuat key baru: %d key", len(store.keys))
+	}
+}
+
+func TestEnvelopeRotateKeepsOldDataReadable(t *testing.T) {
+	ctx := context.Background()
+	store := &memoryKeyStore{}
+	envelope, before := openEnvelope(t, store, testConfig())
+
+	old, err := before.Encrypt("3201011708900001")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	id, err := envelope.Rotate(ctx)
+	if err != nil {
+		t.Fatalf("Rotate: %v", err)
+	}
+	if id != "k2" {
+		t.Errorf("Rotate = %q, want k2", id)
+	}
+
+	_, after := openEnvelope(t, store, testConfig())
+	if after.ActiveKeyID() != "k2" {
+		t.Fatalf("ActiveKeyID setelah rotasi = %q, want k2", after.ActiveKeyID())
+	}
+	if got, err := after.Decrypt(old); err != nil || got != "3201011708900001" {
+		t.Errorf("Decrypt data kunci lama = %q, %v", got, err)
+	}
+	if !after.NeedsReencrypt(old) {
+		t.Error("data kunci lama harus ditandai perlu re-encrypt")
+	}
+	fresh, _ := after.Encrypt("x")
+	if !strings.HasPrefix(fresh, Prefix("k2")) {
+		t.Errorf("enkripsi baru %q tidak memakai k2", fresh)
+	}
+
+	// Instance yang dibuka sebelum rotasi memuat k2 lewat resolver
+	if got, err := before.Decrypt(fresh); err != nil || got != "x" {
+		t.Errorf("Decrypt data kunci baru oleh keyring lama = %q, %v", got, err)
+	}
+}
+
+func TestEnvelopeRewrapMasterKey(t *testing.T) {
+	ctx := context.Background()
+	store := &memoryKeyStore{}
+	_, before := openEnvelope(t, store, testConfig())
+	value, _ := before.Encrypt("rahasia")
+
+	rotated := testConfig()
+	rotated.MasterKey, rotated.MasterKeyID = masterKey('b'), "m2"
+
+	// Tanpa master key lama, data key tidak bisa dibuka
+	if envelope, err := NewEnvelope(store, rotated); err != nil {
+		t.Fatal(err)
+	} else if _, err := envelope.Open(ctx); err == nil {
+		t.Fatal("Open tanpa ENCRYPTION_PREVIOUS_MASTER_KEYS harus error")
+	}
+
+	rotated.PreviousMasterKeys = []string{"m1:" + masterKey('a')}
+	envelope, _ := openEnvelope(t, store, rotated)
+	n, err := envelope.Rewrap(ctx)
+	if err != nil || n != 1 {
+		t.Fatalf("Rewrap = %d, %v, want 1", n, err)
+	}
+	if store.keys[0].MasterKeyID != "m2" {
+		t.Errorf("MasterKeyID setelah rewrap = %q, want m2", store.keys[0].MasterKeyID)
+	}
+	if n, err := envelope.Rewrap(ctx); err != nil || n != 0 {
+		t.Errorf("Rewrap kedua = %d, %v, want 0", n, err)
+	}
+
+	// Setelah rewrap master key lama tidak diperlukan lagi
+	rotated.PreviousMasterKeys = nil
+	_, after := openEnvelope(t, store, rotated)
+	if got, err := after.Decrypt(value); err != nil || got != "rahasia" {
+		t.Errorf("Decrypt setelah rewrap = %q, %v", got, err)
+	}
+}
+
+func TestEnvelopeImportsLegacyKey(t *testing.T) {
+	legacy := legacyKeyring(t)
+	value, _ := legacy.Encrypt("plaintext lama")
+
+	cfg := testConfig()
+	cfg.LegacyKey, cfg.LegacyKeyID = strings.Repeat("L", 32), "legacy"
+	store := &memoryKeyStore{}
+	_, keyring := openEnvelope(t, store, cfg)
+
+	if keyring.ActiveKeyID() != "legacy" {
+		t.Errorf("ActiveKeyID = %q, want legacy", keyring.ActiveKeyID())
+	}
+	if got, err := keyring.Decrypt(value); err != nil || got != "plaintext lama" {
+		t.Errorf("Decrypt data ENCRYPTION_KEY lama = %q, %v", got, err)
+	}
+}
+
+// legacyKeyring meniru keyring sebelum envelope encryption (ENCRYPTION_KEY langsung)
+func legacyKeyring(t *testing.T) *Keyring {
+	t.Helper()
+	k, err := NewKeyring("legacy", map[string][]byte{"legacy": []byte(strings.Repeat("L", 32))}, testIndexKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return k
+}
@@ -0,0 +1,237 @@
+// Package fieldcrypt mengenkripsi kolom sensitif secara transparan lewat serializer GORM
+// "encrypted" (AES-256-GCM) dan menyediakan blind index (HMAC-SHA256) untuk pencarian
+// kesamaan nilai pada kolom terenkripsi.
+//
+// Format ciphertext: "enc:v1:<keyID>:<base64url(nonce|ciphertext)>". Key ID disimpan di
+// setiap nilai sehingga kunci bisa dirotasi: nilai lama tetap bisa dibaca dengan kunci
+// lama, dan perintah re-encrypt menulis ulang semuanya dengan kunci aktif.
+// Nilai tanpa prefix dianggap plaintext lama (sebelum enkripsi) dan dibaca apa adanya.
+package fieldcrypt
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+const prefix = "enc:v1:"
+
+// ErrNoKeyring dikembalikan jika SetDefault belum dipanggil
+var ErrNoKeyring = errors.New("fieldcrypt: keyring not configured")
+
+// Keyring berisi kunci enkripsi per key ID dan kunci blind index.
+// Kunci blind index tidak ikut dirotasi; menggantinya berarti menghitung ulang semua index.
+type Keyring struct {
+	activeID string
+	indexKey []byte
+
+	mu       sync.RWMutex
+	aeads    map[string]cipher.AEAD
+	resolver func(keyID string) ([]byte, error)
+}
+
+// NewKeyring membuat keyring dari kunci 32 byte per key ID. activeID dipakai untuk enkripsi,
+// kunci lain hanya untuk dekripsi data lama.
+func NewKeyring(activeID string, keys map[string][]byte, indexKey []byte) (*Keyring, error) {
+	if _, ok := keys[activeID]; !ok {
+		return nil, fmt.Errorf("fieldcrypt: active key %q not found", activeID)
+	}
+	if len(indexKey) < 32 {
+		return nil, errors.New("fieldcrypt: blind index key must be at least 32 bytes")
+	}
+
+	k := &Keyring{
+		activeID: activeID,
+		aeads:    make(map[string]cipher.AEAD, len(keys)),
+		indexKey: indexKey,
+	}
+	for id, key := range keys {
+		aead, err := newAEAD(id, key)
+		if err != nil {
+			return nil, err
+		}
+		k.aeads[id] = aead
+	}
+	return k, nil
+}
+
+// SetResolver memasang fungsi untuk memuat kunci yang belum dikenal keyring (mis. kunci
+// baru hasil rotasi oleh instance lain). Kunci yang berhasil dimuat disimpan di keyring.
+func (k *Keyring) SetResolver(fn func(keyID string) ([]byte, error)) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	k.resolver = fn
+}
+
+func (k *Keyring) aead(keyID string) (cipher.AEAD, error) {
+	k.mu.RLock()
+	aead, ok := k.aeads[keyID]
+	resolver := k.resolver
+	k.mu.RUnlock()
+	if ok {
+		return aead, nil
+	}
+	if resolver == nil {
+		return nil, fmt.Errorf("fieldcrypt: unknown key ID %q", keyID)
+	}
+
+	key, err := resolver(keyID)
+	if err != nil {
+		return nil, fmt.Errorf("fieldcrypt: load key %q: %w", keyID, err)
+	}
+	aead, err = newAEAD(keyID, key)
+	if err != nil {
+		return nil, err
+	}
+
+	k.mu.Lock()
+	k.aeads[keyID] = aead
+	k.mu.Unlock()
+	return aead, nil
+}
+
+// ValidKeyID melaporkan apakah id boleh dipakai sebagai key ID (huruf, angka, '-', '.')
+func ValidKeyID(id string) bool {
+	if id == "" || len(id) > 50 {
+		return false
+	}
+	for _, r := range id {
+		if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '-' || r == '.') {
+			return false
+		}
+	}
+	return true
+}
+
+func newAEAD(id string, key []byte) (cipher.AEAD, error) {
+	if !ValidKeyID(id) {
+		return nil, fmt.Errorf("fieldcrypt: invalid key ID %q", id)
+	}
+	if len(key) != 32 {
+		return nil, fmt.Errorf("fieldcrypt: key %q must be 32 bytes, got %d", id, len(key))
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// ActiveKeyID adalah key ID yang dipakai untuk enkripsi baru
+func (k *Keyring) ActiveKeyID() string {
+	return k.activeID
+}
+
+// Encrypt mengenkripsi plaintext dengan kunci aktif
+func (k *Keyring) Encrypt(plaintext string) (string, error) {
+	aead, err := k.aead(k.activeID)
+	if err != nil {
+		return "", err
+	}
+
+	nonce := make([]byte, aead.NonceSize(), aead.NonceSize()+len(plaintext)+aead.Overhead())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+	sealed := aead.Seal(nonce, nonce, []byte(plaintext), nil)
+
+	return Prefix(k.activeID) + base64.RawURLEncoding.EncodeToString(sealed), nil
+}
+
+// Decrypt membuka nilai hasil Encrypt dengan kunci sesuai key ID-nya.
+// Nilai tanpa prefix dikembalikan apa adanya (plaintext lama).
+func (k *Keyring) Decrypt(value string) (string, error) {
+	keyID, payload, ok := parse(value)
+	if !ok {
+		return value, nil
+	}
+
+	aead, err := k.aead(keyID)
+	if err != nil {
+		return "", err
+	}
+
+	data, err := base64.RawURLEncoding.DecodeString(payload)
+	if err != nil {
+		return "", fmt.Errorf("fieldcrypt: invalid ciphertext: %w", err)
+	}
+	if len(data) < aead.NonceSize() {
+		return "", errors.New("fieldcrypt: ciphertext too short")
+	}
+
+	nonce, ciphertext := data[:aead.NonceSize()], data[aead.NonceSize():]
+	plaintext, err := aead.Open(nil, nonce, ciphertext, nil)
+	if err != nil {
+		return "", fmt.Errorf("fieldcrypt: decrypt with key %q: %w", keyID, err)
+	}
+	return string(plaintext), nil
+}
+
+// NeedsReencrypt melaporkan apakah nilai masih plaintext atau dienkripsi dengan kunci lama
+func (k *Keyring) NeedsReencrypt(value string) bool {
+	if value == "" {
+		return false
+	}
+	keyID, _, ok := parse(value)
+	return !ok || keyID != k.activeID
+}
+
+// BlindIndex menghitung HMAC-SHA256 (hex) dari nilai yang dinormalisasi (trim, lowercase),
+// untuk pencarian kesamaan tanpa mendekripsi kolom
+func (k *Keyring) BlindIndex(value string) string {
+	mac := hmac.New(sha256.New, k.indexKey)
+	mac.Write([]byte(strings.ToLower(strings.TrimSpace(value))))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// Prefix adalah awalan ciphertext yang dienkripsi dengan keyID, untuk filter LIKE di SQL
+func Prefix(keyID string) string {
+	return prefix + keyID + ":"
+}
+
+// IsEncrypted melaporkan apakah value berformat ciphertext fieldcrypt
+func IsEncrypted(value string) bool {
+	_, _, ok := parse(value)
+	return ok
+}
+
+func parse(value string) (keyID string, payload string, ok bool) {
+	rest, found := strings.CutPrefix(value, prefix)
+	if !found {
+		return "", "", false
+	}
+	return strings.Cut(rest, ":")
+}
+
+var defaultKeyring atomic.Pointer[Keyring]
+
+// SetDefault memasang keyring yang dipakai serializer GORM dan BlindIndex paket ini.
+// Dipanggil sekali saat startup sebelum koneksi database dipakai.
+func SetDefault(k *Keyring) {
+	defaultKeyring.Store(k)
+}
+
+// Default mengembalikan keyring yang dipasang SetDefault, atau nil
+func Default() *Keyring {
+	return defaultKeyring.Load()
+}
+
+// BlindIndex menghitung blind index dengan keyring default.
+// Mengembalikan nil jika value nil/kosong atau keyring belum dipasang.
+func BlindIndex(value *string) *string {
+	k := Default()
+	if k == nil || value == nil || *value == "" {
+		return nil
+	}
+	index := k.BlindIndex(*value)
+	return &index
+}
@@ -0,0 +1,199 @@
+package fieldcrypt
+
+import (
+	"bytes"
+	"encoding/base64"
+	"strings"
+	"testing"
+)
+
+var testIndexKey = bytes.Repeat([]byte("i"), 32)
+
+func testKeyring(t *testing.T, activeID string, ids ...string) *Keyring {
+	t.Helper()
+	keys := make(map[string][]byte, len(ids))
+	for _, id := range ids {
+		keys[id] = bytes.Repeat([]byte(id[len(id)-1:]), 32)
+	}
+	k, err := NewKeyring(activeID, keys, testIndexKey)
+	if err != nil {
+		t.Fatalf("NewKeyring: %v", err)
+	}
+	return k
+}
+
+func TestEncryptDecryptRoundTrip(t *testing.T) {
+	k := testKeyring(t, "k1", "k1")
+
+	for _, plaintext := range []string{"", "3201011708900001", "pasien@example.com", "catatan klinis: nyeri dada ✓"} {
+		ciphertext, err := k.Encrypt(plaintext)
+		if err != nil {
+			t.Fatalf("Encrypt(%q): %v", plaintext, err)
+		}
+		if !strings.HasPrefix(ciphertext, "enc:v1:k1:") {
+			t.Errorf("ciphertext %q tidak berawalan enc:v1:k1:", ciphertext)
+		}
+		got, err := k.Decrypt(ciphertext)
+		if err != nil {
+			t.Fatalf("Decrypt: %v", err)
+		}
+		if got != plaintext {
+			t.Errorf("Decrypt(Encrypt(%q)) = %q", plaintext, got)
+		}
+	}
+}
+
+func TestEncryptUsesRandomNonce(t *testing.T) {
+	k := testKeyring(t, "k1", "k1")
+	a, _ := k.Encrypt("sama")
+	b, _ := k.Encrypt("sama")
+	if a == b {
+		t.Error("dua enkripsi plaintext yang sama menghasilkan ciphertext identik")
+	}
+}
+
+func TestDecryptDetectsTampering(t *testing.T) {
+	k := testKeyring(t, "k1", "k1", "k2")
+	ciphertext, err := k.Encrypt("3201011708900001")
+	if err != nil {
+		t.Fatal(err)
+	}
+	payload := strings.TrimPrefix(ciphertext, Prefix("k1"))
+	data, err := base64.RawURLEncoding.DecodeString(payload)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	flip := func(i int) string {
+		tampered := bytes.Clone(data)
+		tampered[i] ^= 0x01
+		return Prefix("k1") + base64.RawURLEncoding.EncodeToString(tampered)
+	}
+
+	tests := []struct {
+		name  string
+		value string
+	}{
+		{name: "nonce diubah", value: flip(0)},
+		{name: "ciphertext diubah", value: flip(len(data) / 2)},
+		{name: "tag diubah", value: flip(len(data) - 1)},
+		{name: "dipotong", value: ciphertext[:len(ciphertext)-4]},
+		{name: "terlalu pendek", value: Prefix("k1") + "AAAA"},
+		{name: "bukan base64", value: Prefix("k1") + "!!!"},
+		{name: "key ID ditukar", value: Prefix("k2") + payload},
+		{name: "key ID tidak dikenal", value: Prefix("k9") + payload},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got, err := k.Decrypt(tt.value); err == nil {
+				t.Errorf("Decrypt(%q) = %q, want error", tt.value, got)
+			}
+		})
+	}
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		value       string
+		wantKeyID   string
+		wantPayload string
+		wantOK      bool
+	}{
+		{value: "enc:v1:k1:abc", wantKeyID: "k1", wantPayload: "abc", wantOK: true},
+		{value: "enc:v1:legacy-2024.1:abc", wantKeyID: "legacy-2024.1", wantPayload: "abc", wantOK: true},
+		{value: "enc:v1:k1:", wantKeyID: "k1", wantPayload: "", wantOK: true},
+		{value: "enc:v1:k1", wantOK: false},
+		{value: "enc:v2:k1:abc", wantOK: false},
+		{value: "3201011708900001", wantOK: false},
+		{value: "", wantOK: false},
+	}
+	for _, tt := range tests {
+		keyID, payload, ok := parse(tt.value)
+		if ok != tt.wantOK || (ok && (keyID != tt.wantKeyID || payload != tt.wantPayload)) {
+			t.Errorf("parse(%q) = (%q, %q, %v), want (%q, %q, %v)",
+				tt.value, keyID, payload, ok, tt.wantKeyID, tt.wantPayload, tt.wantOK)
+		}
+		if IsEncrypted(tt.value) != tt.wantOK {
+			t.Errorf("IsEncrypted(%q) = %v, want %v", tt.value, !tt.wantOK, tt.wantOK)
+		}
+	}
+}
+
+func TestDecryptPlaintextPassthrough(t *testing.T) {
+	k := testKeyring(t, "k1", "k1")
+	got, err := k.Decrypt("plaintext lama")
+	if err != nil || got != "plaintext lama" {
+		t.Errorf("Decrypt(plaintext) = %q, %v", got, err)
+	}
+}
+
+func TestNeedsReencrypt(t *testing.T) {
+	old := testKeyring(t, "k1", "k1")
+	k := testKeyring(t, "k2", "k1", "k2")
+	oldValue, _ := old.Encrypt("x")
+	newValue, _ := k.Encrypt("x")
+
+	tests := []struct {
+		value string
+		want  bool
+	}{
+		{value: "", want: false},
+		{value: "plaintext", want: true},
+		{value: oldValue, want: true},
+		{value: newValue, want: false},
+	}
+	for _, tt := range tests {
+		if got := k.NeedsReencrypt(tt.value); got != tt.want {
+			t.Errorf("NeedsReencrypt(%q) = %v, want %v", tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestBlindIndex(t *testing.T) {
+	k := testKeyring(t, "k1", "k1")
+	rotated := testKeyring(t, "k2", "k1", "k2")
+
+	index := k.BlindIndex("Pasien@Example.com")
+	if len(index) != 64 {
+		t.Errorf("panjang blind index = %d, want 64", len(index))
+	}
+	if got := k.BlindIndex("  pasien@example.com "); got != index {
+		t.Error("blind index harus sama setelah trim dan lowercase")
+	}
+	if got := rotated.BlindIndex("pasien@example.com"); got != index {
+		t.Error("blind index tidak boleh berubah saat data key dirotasi")
+	}
+	if got := k.BlindIndex("pasien2@example.com"); got == index {
+		t.Error("nilai berbeda menghasilkan blind index yang sama")
+	}
+
+	other, err := NewKeyring("k1", map[string][]byte{"k1": bytes.Repeat([]byte("1"), 32)}, bytes.Repeat([]byte("j"), 32))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if other.BlindIndex("pasien@example.com") == index {
+		t.Error("blind index key berbeda menghasilkan index yang sama")
+	}
+}
+
+func TestNewKeyringValidation(t *testing.T) {
+	key := bytes.Repeat([]byte("1"), 32)
+	tests := []struct {
+		name     string
+		activeID string
+		keys     map[string][]byte
+		indexKey []byte
+	}{
+		{name: "kunci aktif tidak ada", activeID: "k2", keys: map[string][]byte{"k1": key}, indexKey: testIndexKey},
+		{name: "index key pendek", activeID: "k1", keys: map[string][]byte{"k1": key}, indexKey: []byte("pendek")},
+		{name: "data key bukan 32 byte", activeID: "k1", keys: map[string][]byte{"k1": key[:16]}, indexKey: testIndexKey},
+		{name: "key ID dengan titik dua", activeID: "k:1", keys: map[string][]byte{"k:1": key}, indexKey: testIndexKey},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewKeyring(tt.activeID, tt.keys, tt.indexKey); err == nil {
+				t.Error("NewKeyring harus error")
+			}
+		})
+	}
+}
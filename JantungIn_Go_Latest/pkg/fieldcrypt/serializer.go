@@ -0,0 +1,144 @@
+package fieldcrypt
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strconv"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+)
+
+// SerializerName dipakai di tag GORM: `gorm:"type:text;serializer:encrypted"`
+const SerializerName = "encrypted"
+
+func init() {
+	schema.RegisterSerializer(SerializerName, Serializer{})
+}
+
+// Serializer mengenkripsi field string, *string, integer, dan float sebagai teks.
+// String kosong dan pointer nil disimpan apa adanya ("" / NULL) tanpa enkripsi.
+type Serializer struct{}
+
+func (Serializer) Scan(ctx context.Context, field *schema.Field, dst reflect.Value, dbValue any) error {
+	fieldValue := reflect.New(field.FieldType)
+
+	if dbValue != nil {
+		var raw string
+		switch v := dbValue.(type) {
+		case string:
+			raw = v
+		case []byte:
+			raw = string(v)
+		default:
+			// Kolom numerik lama yang belum diubah ke text
+			raw = fmt.Sprint(v)
+		}
+
+		k := Default()
+		if k == nil && IsEncrypted(raw) {
+			return ErrNoKeyring
+		}
+		plaintext := raw
+		if k != nil {
+			var err error
+			if plaintext, err = k.Decrypt(raw); err != nil {
+				return fmt.Errorf("%s.%s: %w", field.Schema.Table, field.DBName, err)
+			}
+		}
+
+		if err := setValue(fieldValue.Elem(), plaintext); err != nil {
+			return fmt.Errorf("%s.%s: %w", field.Schema.Table, field.DBName, err)
+		}
+	}
+
+	field.ReflectValueOf(ctx, dst).Set(fieldValue.Elem())
+	return nil
+}
+
+func (Serializer) Value(ctx context.Context, field *schema.Field, dst reflect.Value, fieldValue any) (any, error) {
+	rv := reflect.ValueOf(fieldValue)
+	if rv.Kind() == reflect.Pointer {
+		if rv.IsNil() {
+			return nil, nil
+		}
+		rv = rv.Elem()
+	}
+
+	plaintext, err := formatValue(rv)
+	if err != nil {
+		return nil, fmt.Errorf("%s.%s: %w", field.Schema.Table, field.DBName, err)
+	}
+	if plaintext == "" {
+		return "", nil
+	}
+
+	k := Default()
+	if k == nil {
+		return nil, ErrNoKeyring
+	}
+	return k.Encrypt(plaintext)
+}
+
+func formatValue(v reflect.Value) (string, error) {
+	switch v.Kind() {
+	case reflect.String:
+		return v.String(), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(v.Int(), 10), nil
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(v.Float(), 'g', -1, 64), nil
+	default:
+		return "", fmt.Errorf("fieldcrypt: unsupported type %s", v.Type())
+	}
+}
+
+func setValue(v reflect.Value, plaintext string) error {
+	if v.Kind() == reflect.Pointer {
+		v.Set(reflect.New(v.Type().Elem()))
+		v = v.Elem()
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(plaintext)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if plaintext == "" {
+			return nil
+		}
+		n, err := strconv.ParseInt(plaintext, 10, 64)
+		if err != nil {
+			return err
+		}
+		v.SetInt(n)
+	case reflect.Float32, reflect.Float64:
+		if plaintext == "" {
+			return nil
+		}
+		f, err := strconv.ParseFloat(plaintext, 64)
+		if err != nil {
+			return err
+		}
+		v.SetFloat(f)
+	default:
+		return fmt.Errorf("fieldcrypt: unsupported type %s", v.Type())
+	}
+	return nil
+}
+
+// Columns mengembalikan nama kolom model yang memakai serializer encrypted
+func Columns(db *gorm.DB, model any) ([]string, error) {
+	stmt := &gorm.Statement{DB: db}
+	if err := stmt.Parse(model); err != nil {
+		return nil, err
+	}
+
+	var columns []string
+	for _, f := range stmt.Schema.Fields {
+		if f.DBName != "" && f.TagSettings["SERIALIZER"] == SerializerName {
+			columns = append(columns, f.DBName)
+		}
+	}
+	return columns, nil
+}
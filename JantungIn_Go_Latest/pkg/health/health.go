@@ -0,0 +1,149 @@
+// Package health menjalankan pengecekan dependensi untuk endpoint readiness.
+package health
+
+import (
+	"context"
+	"math"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"jantungin-api-server/pkg/utils"
+
+	"go.uber.org/zap"
+)
+
+const (
+	StatusUp   = "up"
+	StatusDown = "down"
+
+	StatusReady    = "ready"
+	StatusNotReady = "not_ready"
+)
+
+// CheckResult hasil satu dependensi. Dependensi non-critical yang down tidak membuat
+// service not ready, hanya dilaporkan. Critical dan Error tidak ikut di response
+// karena /readyz publik; detail error hanya dicatat di log.
+type CheckResult struct {
+	Name      string  `json:"name"`
+	Status    string  `json:"status"`
+	Critical  bool    `json:"-"`
+	LatencyMs float64 `json:"latencyMs"`
+	Error     string  `json:"-"`
+}
+
+type Report struct {
+	Status       string        `json:"status"`
+	ShuttingDown bool          `json:"shuttingDown"`
+	Checks       []CheckResult `json:"checks"`
+}
+
+func (r Report) Ready() bool {
+	return r.Status == StatusReady
+}
+
+type check struct {
+	name     string
+	critical bool
+	fn       func(ctx context.Context) error
+}
+
+// Checker menyimpan daftar pengecekan dependensi dan status shutdown service
+type Checker struct {
+	timeout      time.Duration
+	checks       []check
+	shuttingDown atomic.Bool
+
+	mu         sync.Mutex
+	lastStatus map[string]string // untuk mencatat log hanya saat status berubah
+}
+
+// NewChecker membuat Checker; timeout membatasi setiap pengecekan (<= 0 berarti 2 detik)
+func NewChecker(timeout time.Duration) *Checker {
+	if timeout <= 0 {
+		timeout = 2 * time.Second
+	}
+	return &Checker{timeout: timeout, lastStatus: make(map[string]string)}
+}
+
+// Register menambah pengecekan. Panggil sebelum server menerima request.
+func (c *Checker) Register(name string, critical bool, fn func(ctx context.Context) error) {
+	c.checks = append(c.checks, check{name: name, critical: critical, fn: fn})
+}
+
+// SetShuttingDown membuat Check melaporkan not ready agar load balancer berhenti
+// mengirim request baru sebelum server ditutup
+func (c *Checker) SetShuttingDown() {
+	c.shuttingDown.Store(true)
+}
+
+// Check menjalankan semua pengecekan secara paralel, masing-masing dengan timeout sendiri
+func (c *Checker) Check(ctx context.Context) Report {
+	results := make([]CheckResult, len(c.checks))
+
+	var wg sync.WaitGroup
+	for i, chk := range c.checks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i] = c.run(ctx, chk)
+		}()
+	}
+	wg.Wait()
+
+	report := Report{Status: StatusReady, ShuttingDown: c.shuttingDown.Load(), Checks: results}
+	if report.ShuttingDown {
+		report.Status = StatusNotReady
+	}
+	for _, result := range results {
+		if result.Critical && result.Status == StatusDown {
+			report.Status = StatusNotReady
+		}
+	}
+	c.logChanges(results)
+	return report
+}
+
+// logChanges mencatat dependensi yang berubah status, agar probe berkala tidak memenuhi log
+func (c *Checker) logChanges(results []CheckResult) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, result := range results {
+		previous, seen := c.lastStatus[result.Name]
+		c.lastStatus[result.Name] = result.Status
+		if previous == result.Status || (!seen && result.Status == StatusUp) {
+			continue
+		}
+		if result.Status == StatusDown {
+			utils.Warn("Dependency health check failed",
+				zap.String("dependency", result.Name),
+				zap.Bool("critical", result.Critical),
+				zap.String("error", result.Error),
+			)
+		} else {
+			utils.Info("Dependency recovered", zap.String("dependency", result.Name))
+		}
+	}
+}
+
+func (c *Checker) run(ctx context.Context, chk check) CheckResult {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	start := time.Now()
+	err := chk.fn(ctx)
+	latency := time.Since(start)
+
+	result := CheckResult{
+		Name:      chk.name,
+		Status:    StatusUp,
+		Critical:  chk.critical,
+		LatencyMs: math.Round(float64(latency.Microseconds())/10) / 100,
+	}
+	if err != nil {
+		result.Status = StatusDown
+		result.Error = err.Error()
+	}
+	return result
+}
@@ -0,0 +1,133 @@
+// Package mailer mengirim email teks biasa lewat SMTP (net/smtp).
+package mailer
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"mime"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"strconv"
+	"strings"
+	"time"
+
+	"jantungin-api-server/pkg/utils"
+)
+
+// ErrDisabled dikembalikan jika pengiriman email dinonaktifkan (SMTP_ENABLED=false)
+var ErrDisabled = errors.New("email delivery is disabled")
+
+type Message struct {
+	To      []string
+	Subject string
+	Body    string // teks biasa, UTF-8
+}
+
+type Mailer interface {
+	Send(ctx context.Context, msg Message) error
+}
+
+// New membuat Mailer SMTP, atau mailer nonaktif jika SMTP_ENABLED bukan true
+func New(cfg utils.SMTPConfig) Mailer {
+	if !cfg.Enabled {
+		return disabledMailer{}
+	}
+	return &smtpMailer{cfg: cfg, timeout: 15 * time.Second}
+}
+
+type disabledMailer struct{}
+
+func (disabledMailer) Send(context.Context, Message) error {
+	return ErrDisabled
+}
+
+type smtpMailer struct {
+	cfg     utils.SMTPConfig
+	timeout time.Duration
+}
+
+// Send mengirim satu email. STARTTLS dipakai jika server mendukungnya; autentikasi
+// PLAIN hanya dilakukan jika SMTP_USERNAME diisi.
+func (m *smtpMailer) Send(ctx context.Context, msg Message) error {
+	if len(msg.To) == 0 {
+		return errors.New("mailer: no recipients")
+	}
+
+	addr := net.JoinHostPort(m.cfg.Host, strconv.Itoa(m.cfg.Port))
+	dialer := net.Dialer{Timeout: m.timeout}
+	conn, err := dialer.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return fmt.Errorf("mailer: dial %s: %w", addr, err)
+	}
+
+	deadline := time.Now().Add(m.timeout)
+	if d, ok := ctx.Deadline(); ok && d.Before(deadline) {
+		deadline = d
+	}
+	_ = conn.SetDeadline(deadline)
+
+	client, err := smtp.NewClient(conn, m.cfg.Host)
+	if err != nil {
+		conn.Close()
+		return fmt.Errorf("mailer: %w", err)
+	}
+	defer client.Close()
+
+	if ok, _ := client.Extension("STARTTLS"); ok {
+		if err := client.StartTLS(&tls.Config{ServerName: m.cfg.Host}); err != nil {
+			return fmt.Errorf("mailer: starttls: %w", err)
+		}
+	}
+
+	if m.cfg.Username != "" {
+		auth := smtp.PlainAuth("", m.cfg.Username, m.cfg.Password, m.cfg.Host)
+		if err := client.Auth(auth); err != nil {
+			return fmt.Errorf("mailer: auth: %w", err)
+		}
+	}
+
+	if err := client.Mail(m.cfg.FromEmail); err != nil {
+		return fmt.Errorf("mailer: mail from: %w", err)
+	}
+	for _, to := range msg.To {
+		if err := client.Rcpt(to); err != nil {
+			return fmt.Errorf("mailer: rcpt %s: %w", to, err)
+		}
+	}
+
+	w, err := client.Data()
+	if err != nil {
+		return fmt.Errorf("mailer: data: %w", err)
+	}
+	if _, err := w.Write(m.compose(msg)); err != nil {
+		w.Close()
+		return fmt.Errorf("mailer: write: %w", err)
+	}
+	if err := w.Close(); err != nil {
+		return fmt.Errorf("mailer: data: %w", err)
+	}
+
+	return client.Quit()
+}
+
+func (m *smtpMailer) compose(msg Message) []byte {
+	from := mail.Address{Name: m.cfg.FromName, Address: m.cfg.FromEmail}
+
+	var b strings.Builder
+	b.WriteString("From: " + from.String() + "\r\n")
+	b.WriteString("To: " + strings.Join(msg.To, ", ") + "\r\n")
+	b.WriteString("Subject: " + mime.QEncoding.Encode("utf-8", msg.Subject) + "\r\n")
+	b.WriteString("Date: " + time.Now().Format(time.RFC1123Z) + "\r\n")
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
+	b.WriteString("Content-Transfer-Encoding: 8bit\r\n")
+	b.WriteString("\r\n")
+	// Baris body dinormalisasi ke CRLF sesuai RFC 5322
+	b.WriteString(strings.ReplaceAll(strings.ReplaceAll(msg.Body, "\r\n", "\n"), "\n", "\r\n"))
+	b.WriteString("\r\n")
+
+	return []byte(b.String())
+}
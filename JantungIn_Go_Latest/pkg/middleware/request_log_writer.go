@@ -0,0 +1,190 @@
+package middleware
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+
+	"jantungin-api-server/internal/data/entity"
+	"jantungin-api-server/internal/data/repository"
+	"jantungin-api-server/pkg/background"
+	"jantungin-api-server/pkg/utils"
+
+	"go.uber.org/zap"
+)
+
+const (
+	// requestLogTimeout batas waktu satu INSERT batch request log
+	requestLogTimeout = 5 * time.Second
+	// maxRequestLogBatch menjaga jumlah parameter INSERT di bawah batas PostgreSQL (65535)
+	maxRequestLogBatch = 5000
+)
+
+// RequestLogStats penghitung RequestLogWriter sejak server start
+type RequestLogStats struct {
+	Enqueued uint64 `json:"enqueued"`
+	Written  uint64 `json:"written"`
+	Dropped  uint64 `json:"dropped"` // antrean penuh
+	Failed   uint64 `json:"failed"`  // INSERT gagal
+	Batches  uint64 `json:"batches"`
+}
+
+// RequestLogWriter menampung request log di antrean berukuran tetap dan menulisnya
+// per batch dari satu goroutine, sehingga beban ke pool koneksi database tidak
+// bertambah seiring jumlah request. Sisa antrean ditulis saat runner di-shutdown.
+type RequestLogWriter struct {
+	repo          repository.StatsRepository
+	queue         chan *entity.RequestLog
+	batchSize     int
+	flushInterval time.Duration
+	block         bool
+	blockTimeout  time.Duration
+
+	enqueued atomic.Uint64
+	written  atomic.Uint64
+	dropped  atomic.Uint64
+	failed   atomic.Uint64
+	batches  atomic.Uint64
+}
+
+// NewRequestLogWriter membuat writer; nilai cfg yang tidak valid diganti default.
+// Panggil Start agar antrean mulai ditulis.
+func NewRequestLogWriter(repo repository.StatsRepository, cfg utils.RequestLogConfig) *RequestLogWriter {
+	if cfg.QueueSize <= 0 {
+		cfg.QueueSize = 10000
+	}
+	if cfg.BatchSize <= 0 {
+		cfg.BatchSize = 500
+	}
+	if cfg.BatchSize > maxRequestLogBatch {
+		cfg.BatchSize = maxRequestLogBatch
+	}
+	if cfg.FlushInterval <= 0 {
+		cfg.FlushInterval = time.Second
+	}
+
+	return &RequestLogWriter{
+		repo:          repo,
+		queue:         make(chan *entity.RequestLog, cfg.QueueSize),
+		batchSize:     cfg.BatchSize,
+		flushInterval: cfg.FlushInterval,
+		block:         cfg.Overflow == utils.RequestLogOverflowBlock && cfg.BlockTimeout > 0,
+		blockTimeout:  cfg.BlockTimeout,
+	}
+}
+
+// Start menjalankan flusher di runner; runner.Shutdown menunggu sisa antrean ditulis
+func (w *RequestLogWriter) Start(runner *background.Runner) error {
+	return runner.Go("request-log-writer", w.run)
+}
+
+// Enqueue memasukkan log ke antrean. Jika antrean penuh, log dibuang (overflow drop)
+// atau ditunggu paling lama blockTimeout (overflow block). Mengembalikan false jika dibuang.
+func (w *RequestLogWriter) Enqueue(log *entity.RequestLog) bool {
+	select {
+	case w.queue <- log:
+		w.enqueued.Add(1)
+		return true
+	default:
+	}
+
+	if w.block {
+		timer := time.NewTimer(w.blockTimeout)
+		defer timer.Stop()
+		select {
+		case w.queue <- log:
+			w.enqueued.Add(1)
+			return true
+		case <-timer.C:
+		}
+	}
+
+	w.dropped.Add(1)
+	return false
+}
+
+func (w *RequestLogWriter) Stats() RequestLogStats {
+	return RequestLogStats{
+		Enqueued: w.enqueued.Load(),
+		Written:  w.written.Load(),
+		Dropped:  w.dropped.Load(),
+		Failed:   w.failed.Load(),
+		Batches:  w.batches.Load(),
+	}
+}
+
+func (w *RequestLogWriter) run(ctx context.Context) {
+	ticker := time.NewTicker(w.flushInterval)
+	defer ticker.Stop()
+
+	batch := make([]*entity.RequestLog, 0, w.batchSize)
+	flush := func() {
+		if len(batch) > 0 {
+			w.write(ctx, batch)
+			batch = batch[:0]
+		}
+	}
+
+	var reportedDrops uint64
+	for {
+		select {
+		case log := <-w.queue:
+			batch = append(batch, log)
+			if len(batch) >= w.batchSize {
+				flush()
+			}
+
+		case <-ticker.C:
+			flush()
+			// Drop dilaporkan sekali per interval, bukan per request
+			if dropped := w.dropped.Load(); dropped > reportedDrops {
+				utils.Warn("Request log queue full, logs dropped",
+					zap.Uint64("dropped", dropped-reportedDrops),
+					zap.Uint64("total_dropped", dropped),
+				)
+				reportedDrops = dropped
+			}
+
+		case <-ctx.Done():
+			// Server sudah berhenti menerima request; tulis semua yang tersisa
+		drain:
+			for {
+				select {
+				case log := <-w.queue:
+					batch = append(batch, log)
+					if len(batch) >= w.batchSize {
+						flush()
+					}
+				default:
+					break drain
+				}
+			}
+			flush()
+
+			stats := w.Stats()
+			utils.Info("Request log writer stopped",
+				zap.Uint64("enqueued", stats.Enqueued),
+				zap.Uint64("written", stats.Written),
+				zap.Uint64("dropped", stats.Dropped),
+				zap.Uint64("failed", stats.Failed),
+				zap.Uint64("batches", stats.Batches),
+			)
+			return
+		}
+	}
+}
+
+// write menulis satu batch. Context runner dibatalkan saat shutdown, padahal sisa
+// antrean tetap harus ditulis, jadi pembatalannya dilepas dan diganti timeout sendiri.
+func (w *RequestLogWriter) write(ctx context.Context, batch []*entity.RequestLog) {
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), requestLogTimeout)
+	defer cancel()
+
+	w.batches.Add(1)
+	if err := w.repo.InsertRequestLogs(ctx, batch); err != nil {
+		w.failed.Add(uint64(len(batch)))
+		utils.Error("Failed to write request logs", zap.Int("count", len(batch)), zap.Error(err))
+		return
+	}
+	w.written.Add(uint64(len(batch)))
+}
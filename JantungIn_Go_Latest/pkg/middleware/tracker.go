@@ -1,17 +1,17 @@
 package middleware
 
 import (
-	"context"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 
 	"jantungin-api-server/internal/data/entity"
-	"jantungin-api-server/internal/data/repository"
 )
 
-func RequestTracker(statsRepo repository.StatsRepository) gin.HandlerFunc {
+// RequestTracker mencatat setiap request ke tabel request_logs lewat antrean writer;
+// request tidak menunggu INSERT selesai
+func RequestTracker(writer *RequestLogWriter) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		start := time.Now()
 
@@ -37,13 +37,9 @@ func RequestTracker(statsRepo repository.StatsRepository) gin.HandlerFunc {
 			UserAgent:  c.Request.UserAgent(),
 			LatencyMs:  latencyMs,
 			UserID:     userID,
+			CreatedAt:  time.Now(), // waktu request, bukan waktu batch ditulis
 		}
 
-		// Semua nilai sudah di-capture sebelum goroutine
-		// Pakai context.Background() karena c.Request.Context() sudah cancelled
-		// saat request selesai dan goroutine baru mulai jalan
-		go func(l *entity.RequestLog) {
-			_ = statsRepo.InsertRequestLog(context.Background(), l)
-		}(log)
+		writer.Enqueue(log)
 	}
 }
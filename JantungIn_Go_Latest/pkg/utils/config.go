@@ -1,6 +1,7 @@
 package utils
 
 import (
+	"fmt"
 	"log"
 	"os"
 	"strconv"
@@ -11,23 +12,35 @@ import (
 )
 
 type Config struct {
-	App      AppConfig
-	Database DatabaseConfig
-	Redis    RedisConfig
-	JWT      JWTConfig
-	SMTP     SMTPConfig
-	CDN      CDNConfig
-	Cors     CorsConfig
+	App        AppConfig
+	Database   DatabaseConfig
+	Redis      RedisConfig
+	JWT        JWTConfig
+	SMTP       SMTPConfig
+	CDN        CDNConfig
+	Cors       CorsConfig
+	Drift      DriftConfig
+	Encryption EncryptionConfig
+	Legacy     LegacyConfig
+	Health     HealthConfig
+	RequestLog RequestLogConfig
 }
 
 type AppConfig struct {
-	Name            string
-	Env             string
-	Port            string
-	Timezone        string
-	ShutdownTimeout time.Duration
-	EncryptionKey   string
-	MLServiceURL    string
+	Name               string
+	Env                string
+	Port               string
+	Timezone           string
+	ShutdownTimeout    time.Duration
+	MLServiceURL       string
+	MLModelVersion     string
+	MLShadowURL        string
+	MLShadowVersion    string
+	MLBatchSize        int
+	MLBatchConcurrency int
+	ImportWorkers      int
+	ImportMaxRows      int
+	IdempotencyTTL     time.Duration
 }
 
 type DatabaseConfig struct {
@@ -37,13 +50,14 @@ type DatabaseConfig struct {
 	Password        string
 	Name            string
 	SSLMode         string
+	AutoMigrate     bool // jalankan migrasi SQL saat start; jika false, start ditolak bila ada migrasi tertunda
 	MaxIdleConns    int
 	MaxOpenConns    int
 	ConnMaxLifetime time.Duration
 }
 
 type RedisConfig struct {
-	Host      string
+	Host      string // kosong berarti Redis tidak dipakai
 	Port      string
 	Password  string
 	DB        int
@@ -57,6 +71,7 @@ type JWTConfig struct {
 }
 
 type SMTPConfig struct {
+	Enabled   bool
 	Host      string
 	Port      int
 	Username  string
@@ -77,6 +92,64 @@ type CorsConfig struct {
 	AllowedOrigins []string
 }
 
+// DriftConfig mengatur pemantauan drift input diagnosis terhadap distribusi data training
+type DriftConfig struct {
+	CheckInterval      time.Duration // 0 menonaktifkan pengecekan berkala
+	WindowDays         int
+	MinSamples         int
+	MaxSamples         int // diagnosis acak yang dianalisis per jendela
+	PSIThreshold       float64
+	ZScoreThreshold    float64
+	MeanShiftThreshold float64 // selisih rata-rata minimal (dalam satuan scale training) agar z-score dianggap drift
+}
+
+// HealthConfig mengatur endpoint /readyz dan penundaan saat shutdown
+type HealthConfig struct {
+	CheckTimeout time.Duration // batas waktu setiap pengecekan dependensi
+	RequireML    bool          // ML service down membuat service not ready
+	DrainDelay   time.Duration // jeda antara /readyz not ready dan penutupan server
+}
+
+// Kebijakan RequestLogConfig.Overflow saat antrean request log penuh
+const (
+	RequestLogOverflowDrop  = "drop"  // log baru langsung dibuang
+	RequestLogOverflowBlock = "block" // request menunggu paling lama BlockTimeout, lalu log dibuang
+)
+
+// RequestLogConfig mengatur antrean penulisan request_logs oleh RequestTracker
+type RequestLogConfig struct {
+	QueueSize     int
+	BatchSize     int           // jumlah baris per INSERT
+	FlushInterval time.Duration // antrean ditulis paling lambat setiap interval ini
+	Overflow      string
+	BlockTimeout  time.Duration
+}
+
+// Nilai default khusus development; Validate menolaknya di production
+const (
+	DefaultEncryptionKey = "12345678901234567890123456789012"
+	DefaultBlindIndexKey = "abcdefghijabcdefghijabcdefghij12"
+)
+
+// EncryptionConfig mengatur enkripsi kolom sensitif (envelope encryption).
+// Data key disimpan di tabel encryption_keys, dibungkus (wrap) dengan master key.
+type EncryptionConfig struct {
+	MasterKey          string   // base64 32 byte; diabaikan jika MasterKeyFile diisi
+	MasterKeyFile      string   // path file berisi master key base64
+	MasterKeyID        string   // versi master key, ganti saat master key dirotasi
+	PreviousMasterKeys []string // "masterKeyID:base64", untuk re-wrap data key lama
+	LegacyKey          string   // ENCRYPTION_KEY lama (32 karakter), diimpor sebagai data key
+	LegacyKeyID        string
+	LegacyPreviousKeys []string // "keyID:key"
+	BlindIndexKey      string
+}
+
+// LegacyConfig dipakai perintah migrate-legacy untuk membaca database API Node/Sequelize lama
+type LegacyConfig struct {
+	DatabaseURL   string // DATABASE_URL milik API lama
+	EncryptionKey string // ENCRYPTION_KEY milik API lama (AES-256-CBC untuk nik_encrypted)
+}
+
 func LoadConfig() (*Config, error) {
 	if err := godotenv.Load(); err != nil {
 		log.Println("Warning: .env file not found, using environment variables")
@@ -84,13 +157,20 @@ func LoadConfig() (*Config, error) {
 
 	cfg := &Config{
 		App: AppConfig{
-			Name:            getEnv("APP_NAME", "My App"),
-			Env:             getEnv("APP_ENV", "development"),
-			Port:            getEnv("APP_PORT", "8080"),
-			Timezone:        getEnv("APP_TIMEZONE", "Asia/Jakarta"),
-			ShutdownTimeout: parseDuration("SHUTDOWN_TIMEOUT", "10s"),
-			EncryptionKey:   getEnv("ENCRYPTION_KEY", "12345678901234567890123456789012"),
-			MLServiceURL:    getEnv("ML_SERVICE_URL", "http://localhost:1001"),
+			Name:               getEnv("APP_NAME", "My App"),
+			Env:                getEnv("APP_ENV", "development"),
+			Port:               getEnv("APP_PORT", "8080"),
+			Timezone:           getEnv("APP_TIMEZONE", "Asia/Jakarta"),
+			ShutdownTimeout:    parseDuration("SHUTDOWN_TIMEOUT", "10s"),
+			MLServiceURL:       getEnv("ML_SERVICE_URL", "http://localhost:1001"),
+			MLModelVersion:     getEnv("ML_MODEL_VERSION", "1.0.0"),
+			MLShadowURL:        getEnv("ML_SHADOW_SERVICE_URL", ""),
+			MLShadowVersion:    getEnv("ML_SHADOW_MODEL_VERSION", ""),
+			MLBatchSize:        getEnvInt("ML_BATCH_SIZE", 50),
+			MLBatchConcurrency: getEnvInt("ML_BATCH_CONCURRENCY", 4),
+			ImportWorkers:      getEnvInt("IMPORT_WORKERS", 4),
+			ImportMaxRows:      getEnvInt("IMPORT_MAX_ROWS", 1000),
+			IdempotencyTTL:     parseDuration("IDEMPOTENCY_TTL", "24h"),
 		},
 		Database: DatabaseConfig{
 			Host:            getEnv("DB_HOST", "localhost"),
@@ -99,23 +179,25 @@ func LoadConfig() (*Config, error) {
 			Password:        getEnv("DB_PASSWORD", ""),
 			Name:            getEnv("DB_NAME", "jantungin_db"),
 			SSLMode:         getEnv("DB_SSLMODE", "disable"),
+			AutoMigrate:     getEnv("DB_AUTO_MIGRATE", "true") == "true",
 			MaxIdleConns:    getEnvInt("DB_MAX_IDLE_CONNS", 10),
 			MaxOpenConns:    getEnvInt("DB_MAX_OPEN_CONNS", 100),
 			ConnMaxLifetime: parseDuration("DB_CONN_MAX_LIFETIME", "1h"),
 		},
-		// Redis: RedisConfig{
-		// 	Host:      getEnv("REDIS_HOST", "localhost"),
-		// 	Port:      getEnv("REDIS_PORT", "6379"),
-		// 	Password:  getEnv("REDIS_PASSWORD", ""),
-		// 	DB:        getEnvInt("REDIS_DB", 0),
-		// 	SessionDB: getEnvInt("REDIS_SESSION_DB", 1),
-		// },
+		Redis: RedisConfig{
+			Host:      getEnv("REDIS_HOST", ""),
+			Port:      getEnv("REDIS_PORT", "6379"),
+			Password:  getEnv("REDIS_PASSWORD", ""),
+			DB:        getEnvInt("REDIS_DB", 0),
+			SessionDB: getEnvInt("REDIS_SESSION_DB", 1),
+		},
 		JWT: JWTConfig{
 			Secret:             getEnv("JWT_SECRET", "change-this-secret-key"),
 			AccessTokenExpire:  parseDuration("JWT_ACCESS_TOKEN_EXPIRE", "15m"),
 			RefreshTokenExpire: parseDuration("JWT_REFRESH_TOKEN_EXPIRE", "168h"), // 7 days
 		},
 		SMTP: SMTPConfig{
+			Enabled:   getEnv("SMTP_ENABLED", "false") == "true",
 			Host:      getEnv("SMTP_HOST", "smtp.gmail.com"),
 			Port:      getEnvInt("SMTP_PORT", 587),
 			Username:  getEnv("SMTP_USERNAME", ""),
@@ -133,24 +215,73 @@ func LoadConfig() (*Config, error) {
 		Cors: CorsConfig{
 			AllowedOrigins: parseSlice("CORS_ALLOWED_ORIGINS", []string{"http://localhost:3000"}),
 		},
+		Drift: DriftConfig{
+			CheckInterval:      parseDuration("DRIFT_CHECK_INTERVAL", "24h"),
+			WindowDays:         getEnvInt("DRIFT_WINDOW_DAYS", 30),
+			MinSamples:         getEnvInt("DRIFT_MIN_SAMPLES", 30),
+			MaxSamples:         getEnvInt("DRIFT_MAX_SAMPLES", 5000),
+			PSIThreshold:       getEnvFloat("DRIFT_PSI_THRESHOLD", 0.25),
+			ZScoreThreshold:    getEnvFloat("DRIFT_ZSCORE_THRESHOLD", 3),
+			MeanShiftThreshold: getEnvFloat("DRIFT_MEAN_SHIFT_THRESHOLD", 0.2),
+		},
+		Encryption: EncryptionConfig{
+			MasterKey:          getEnv("ENCRYPTION_MASTER_KEY", ""),
+			MasterKeyFile:      getEnv("ENCRYPTION_MASTER_KEY_FILE", ""),
+			MasterKeyID:        getEnv("ENCRYPTION_MASTER_KEY_ID", "m1"),
+			PreviousMasterKeys: parseSlice("ENCRYPTION_PREVIOUS_MASTER_KEYS", nil),
+			LegacyKey:          getEnv("ENCRYPTION_KEY", ""),
+			LegacyKeyID:        getEnv("ENCRYPTION_KEY_ID", "k1"),
+			LegacyPreviousKeys: parseSlice("ENCRYPTION_PREVIOUS_KEYS", nil),
+			BlindIndexKey:      getEnv("BLIND_INDEX_KEY", DefaultBlindIndexKey),
+		},
+		Legacy: LegacyConfig{
+			DatabaseURL: getEnv("LEGACY_DATABASE_URL", ""),
+			// Sama dengan fallback API lama jika ENCRYPTION_KEY-nya tidak diisi
+			EncryptionKey: getEnv("LEGACY_ENCRYPTION_KEY", "fallback_encryption_key_for_development"),
+		},
+		Health: HealthConfig{
+			CheckTimeout: parseDuration("HEALTH_CHECK_TIMEOUT", "2s"),
+			RequireML:    getEnv("HEALTH_REQUIRE_ML", "false") == "true",
+			DrainDelay:   parseDuration("SHUTDOWN_DRAIN_DELAY", "0s"),
+		},
+		RequestLog: RequestLogConfig{
+			QueueSize:     getEnvInt("REQUEST_LOG_QUEUE_SIZE", 10000),
+			BatchSize:     getEnvInt("REQUEST_LOG_BATCH_SIZE", 500),
+			FlushInterval: parseDuration("REQUEST_LOG_FLUSH_INTERVAL", "1s"),
+			Overflow:      getEnv("REQUEST_LOG_OVERFLOW", RequestLogOverflowDrop),
+			BlockTimeout:  parseDuration("REQUEST_LOG_BLOCK_TIMEOUT", "50ms"),
+		},
+	}
+
+	// Development: data lama dienkripsi dengan kunci default sebelum ada master key
+	if cfg.Encryption.LegacyKey == "" && cfg.App.Env != "production" {
+		cfg.Encryption.LegacyKey = DefaultEncryptionKey
 	}
 
-	// if err := cfg.Validate(); err != nil {
-	// 	return nil, err
-	// }
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
 
 	return cfg, nil
 }
 
-// func (c *Config) Validate() error {
-// 	if c.Database.Password == "" {
-// 		return fmt.Errorf("DB_PASSWORD is required")
-// 	}
-// 	if c.JWT.Secret == "change-this-secret-key" && c.App.Env == "production" {
-// 		return fmt.Errorf("JWT_SECRET must be changed in production")
-// 	}
-// 	return nil
-// }
+// Validate menolak konfigurasi yang tidak aman untuk production
+func (c *Config) Validate() error {
+	if c.App.Env != "production" {
+		return nil
+	}
+
+	if c.Encryption.MasterKey == "" && c.Encryption.MasterKeyFile == "" {
+		return fmt.Errorf("ENCRYPTION_MASTER_KEY_FILE or ENCRYPTION_MASTER_KEY is required in production")
+	}
+	if c.Encryption.LegacyKey == DefaultEncryptionKey {
+		return fmt.Errorf("ENCRYPTION_KEY must not use the default value in production")
+	}
+	if c.Encryption.BlindIndexKey == DefaultBlindIndexKey {
+		return fmt.Errorf("BLIND_INDEX_KEY must be changed in production")
+	}
+	return nil
+}
 
 func getEnv(key, defaultValue string) string {
 	if value := os.Getenv(key); value != "" {
@@ -168,6 +299,15 @@ func getEnvInt(key string, defaultValue int) int {
 	return defaultValue
 }
 
+func getEnvFloat(key string, defaultValue float64) float64 {
+	if value := os.Getenv(key); value != "" {
+		if floatVal, err := strconv.ParseFloat(value, 64); err == nil {
+			return floatVal
+		}
+	}
+	return defaultValue
+}
+
 func parseDuration(key, defaultValue string) time.Duration {
 	value := getEnv(key, defaultValue)
 	duration, err := time.ParseDuration(value)
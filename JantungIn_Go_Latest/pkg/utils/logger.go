@@ -1,6 +1,7 @@
 package utils
 
 import (
+	"io"
 	"os"
 	"path/filepath"
 
@@ -12,6 +13,12 @@ var Logger *zap.Logger
 
 // InitLogger initializes the Zap logger with file output
 func InitLogger(env string) error {
+	return InitLoggerTo(env, os.Stdout)
+}
+
+// InitLoggerTo sama dengan InitLogger tetapi log console ditulis ke console,
+// dipakai perintah CLI agar stdout hanya berisi output hasil perintah
+func InitLoggerTo(env string, console io.Writer) error {
 	logsDir := "logs"
 	if err := os.MkdirAll(logsDir, 0755); err != nil {
 		return err
@@ -62,7 +69,7 @@ func InitLogger(env string) error {
 
 	consoleCore := zapcore.NewCore(
 		consoleEncoder,
-		zapcore.AddSync(os.Stdout),
+		zapcore.AddSync(console),
 		level,
 	)
 
@@ -104,7 +104,9 @@ class Predictor:
     def is_loaded(self) -> bool:
         return self._w1 is not None
 
-    def _forward(self, x: np.ndarray) -> float:
+    def _forward(self, x: np.ndarray) -> np.ndarray:
+        # x berbentuk (n, 13), hasil berupa probabilitas (n,)
+
         # Layer 1: ReLU
         x = np.maximum(0.0, x @ self._w1 + self._b1)
 
@@ -114,23 +116,24 @@ class Predictor:
         # Layer 3: Sigmoid
         x = 1.0 / (1.0 + np.exp(-(x @ self._w3 + self._b3)))
 
-        return float(x[0])
+        return x[:, 0]
 
     def predict(self, features: list[float]) -> dict:
+        return self.predict_batch([features])[0]
+
+    def predict_batch(self, features: list[list[float]]) -> list[dict]:
         if not self.is_loaded():
             raise RuntimeError("Model belum dimuat")
 
         # StandardScaler: (x - mean) / scale
-        scaled = np.array(
-            [
-                (features[i] - self._scaler_mean[i]) / self._scaler_scale[i]
-                for i in range(len(features))
-            ],
-            dtype=np.float32,
-        )
+        mean = np.array(self._scaler_mean, dtype=np.float64)
+        scale = np.array(self._scaler_scale, dtype=np.float64)
+        scaled = ((np.array(features, dtype=np.float64) - mean) / scale).astype(np.float32)
 
-        probability = self._forward(scaled)
+        return [self._to_result(float(p)) for p in self._forward(scaled)]
 
+    @staticmethod
+    def _to_result(probability: float) -> dict:
         result_percentage = round(probability * 100)
         cardiovascular_risk = "High Risk" if probability >= 0.5 else "Low"
         prediction = "Berisiko" if probability >= 0.5 else "Tidak Berisiko"
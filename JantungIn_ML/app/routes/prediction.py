@@ -1,7 +1,15 @@
 from fastapi import APIRouter, HTTPException
+from pydantic import ValidationError
 
 from app.model.predictor import Predictor
-from app.schemas.prediction import DiagnosisInput, PredictionResponse, PredictionResult
+from app.schemas.prediction import (
+    BatchPredictionItem,
+    BatchPredictionRequest,
+    BatchPredictionResponse,
+    DiagnosisInput,
+    PredictionResponse,
+    PredictionResult,
+)
 
 router = APIRouter()
 
@@ -61,3 +69,48 @@ def predict(payload: DiagnosisInput):
         message="Prediction successful",
         data=PredictionResult(**result),
     )
+
+
+def _format_validation_error(err: ValidationError) -> str:
+    return "; ".join(
+        f"{'.'.join(str(part) for part in e['loc'])}: {e['msg']}" for e in err.errors()
+    )
+
+
+@router.post("/predict/batch", response_model=BatchPredictionResponse)
+def predict_batch(payload: BatchPredictionRequest):
+    """
+    Prediksi banyak record sekaligus. Urutan hasil sama dengan urutan input;
+    record yang tidak valid dilaporkan per item tanpa menggagalkan batch.
+    """
+    predictor = Predictor.get_instance()
+
+    if not predictor.is_loaded():
+        raise HTTPException(status_code=503, detail="Model belum siap")
+
+    items: list[BatchPredictionItem] = []
+    valid_indexes: list[int] = []
+    features: list[list[float]] = []
+
+    for i, raw in enumerate(payload.items):
+        try:
+            data = DiagnosisInput.model_validate(raw)
+        except ValidationError as e:
+            items.append(
+                BatchPredictionItem(index=i, success=False, error=_format_validation_error(e))
+            )
+            continue
+
+        items.append(BatchPredictionItem(index=i, success=True))
+        valid_indexes.append(i)
+        features.append(_map_to_features(data))
+
+    if features:
+        for i, result in zip(valid_indexes, predictor.predict_batch(features)):
+            items[i].data = PredictionResult(**result)
+
+    return BatchPredictionResponse(
+        success=True,
+        message="Batch prediction completed",
+        data=items,
+    )
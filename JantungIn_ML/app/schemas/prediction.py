@@ -1,5 +1,10 @@
+from typing import Any, Optional
+
 from pydantic import BaseModel, Field
 
+# Batas jumlah record per request /predict/batch
+MAX_BATCH_SIZE = 100
+
 
 class DiagnosisInput(BaseModel):
     age: int = Field(..., ge=1, le=120)
@@ -36,3 +41,22 @@ class PredictionResponse(BaseModel):
     success: bool
     message: str
     data: PredictionResult
+
+
+class BatchPredictionRequest(BaseModel):
+    # Item divalidasi satu per satu di route agar satu record tidak valid
+    # tidak menggagalkan seluruh batch
+    items: list[dict[str, Any]] = Field(..., min_length=1, max_length=MAX_BATCH_SIZE)
+
+
+class BatchPredictionItem(BaseModel):
+    index: int
+    success: bool
+    data: Optional[PredictionResult] = None
+    error: Optional[str] = None
+
+
+class BatchPredictionResponse(BaseModel):
+    success: bool
+    message: str
+    data: list[BatchPredictionItem]